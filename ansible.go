@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// ansibleArgs holds the module parameters from the JSON args file Ansible
+// passes as the first argument. Internal _ansible_* keys are ignored.
+type ansibleArgs struct {
+	Password   string      `json:"password"`
+	Iterations json.Number `json:"iterations"`
+	Existing   string      `json:"existing"`
+}
+
+type ansibleResult struct {
+	Changed  bool   `json:"changed"`
+	Failed   bool   `json:"failed"`
+	Verifier string `json:"verifier,omitempty"`
+	Msg      string `json:"msg,omitempty"`
+}
+
+func runAnsible(argsFile string, w io.Writer, defaultIters int) int {
+	result, err := ansibleModule(argsFile, defaultIters)
+	if err != nil {
+		result = ansibleResult{Failed: true, Msg: err.Error()}
+	}
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+		return 1
+	}
+	if result.Failed {
+		return 1
+	}
+	return 0
+}
+
+func ansibleModule(argsFile string, defaultIters int) (ansibleResult, error) {
+	if argsFile == "" {
+		return ansibleResult{}, errorf("no arguments file given")
+	}
+
+	f, err := os.Open(argsFile)
+	if err != nil {
+		return ansibleResult{}, errorf("failed to read arguments: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxDocumentSize+1))
+	if err != nil {
+		return ansibleResult{}, errorf("failed to read arguments: %w", err)
+	}
+	if len(data) > maxDocumentSize {
+		return ansibleResult{}, errorf("arguments file exceeds %d bytes", maxDocumentSize)
+	}
+
+	var args ansibleArgs
+	if err := json.Unmarshal(data, &args); err != nil {
+		return ansibleResult{}, errorf("failed to decode arguments: %w", err)
+	}
+
+	iterations := defaultIters
+	if args.Iterations != "" {
+		n, err := args.Iterations.Int64()
+		if err != nil {
+			return ansibleResult{}, errorf("invalid iterations %q: %w", args.Iterations, err)
+		}
+		iterations = int(n)
+	}
+
+	if err := validatePassword(args.Password); err != nil {
+		return ansibleResult{}, errorf("invalid password: %w", err)
+	}
+
+	// An existing verifier that already matches the password and the
+	// requested cost is left alone so repeated runs report no change.
+	if args.Existing != "" {
+		_, existing, err := scram.ParseVerifier(args.Existing)
+		if err == nil && existing.Iterations == iterations {
+			ok, err := verifySCRAMSHA256(args.Password, args.Existing)
+			if err == nil && ok {
+				return ansibleResult{Verifier: args.Existing, Msg: "verifier is up to date"}, nil
+			}
+		}
+	}
+
+	hash, err := generateSCRAMSHA256(args.Password, iterations)
+	if err != nil {
+		return ansibleResult{}, err
+	}
+
+	return ansibleResult{Changed: true, Verifier: hash, Msg: "verifier generated"}, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type auditConfig struct {
+	DSN           string
+	MinIterations int
+	All           bool
+}
+
+func auditFlags(config *auditConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	fs.StringVar(&config.DSN, "dsn", os.Getenv("DATABASE_URL"), "PostgreSQL connection URL (default: $DATABASE_URL, then PG* variables)")
+	fs.IntVar(&config.MinIterations, "min-iterations", defaultIterations, "Report SCRAM verifiers with fewer iterations than this")
+	fs.BoolVar(&config.All, "all", false, "Also audit roles that cannot log in")
+	return fs
+}
+
+func runAudit(args []string) int {
+	config := auditConfig{}
+
+	fs := auditFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	flagged, err := audit(config, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if flagged > 0 {
+		return 1
+	}
+	return 0
+}
+
+// audit reports the stored password of every login role and returns how
+// many roles need attention. Verifiers are checked with the lint rules.
+func audit(config auditConfig, w io.Writer) (int, error) {
+	conn, err := connectPostgres(config.DSN)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if err := reportMD5Settings(conn, w); err != nil {
+		return 0, err
+	}
+
+	query := "SELECT rolname, rolpassword FROM pg_authid WHERE rolcanlogin ORDER BY rolname"
+	if config.All {
+		query = "SELECT rolname, rolpassword FROM pg_authid ORDER BY rolname"
+	}
+	rows, err := conn.Query(query)
+	if err != nil {
+		return 0, errorf("failed to list roles: %w", err)
+	}
+
+	policy := lintConfig{MinIterations: config.MinIterations, MinSaltLength: 16}
+	allowed := map[string]bool{"SCRAM-SHA-256": true}
+
+	flagged := 0
+	for _, row := range rows {
+		role := *row[0]
+		if row[1] == nil {
+			fmt.Fprintf(w, "%s: no password set\n", role)
+			flagged++
+			continue
+		}
+
+		problems := lintVerifier(*row[1], policy, allowed)
+		if len(problems) == 0 {
+			fmt.Fprintf(w, "%s: OK\n", role)
+			continue
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(w, "%s: %s\n", role, problem)
+		}
+		flagged++
+	}
+
+	fmt.Fprintf(w, "%d roles audited, %d need attention\n", len(rows), flagged)
+	return flagged, nil
+}
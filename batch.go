@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// validateBatch checks the options that go with -batch, which reads one
+// password per record from stdin and prints one result per record. With
+// -tty each record is a user name instead, and that user's password is
+// prompted for on the terminal. With -json-records each record is a JSON
+// object that can also override some of the flags.
+func validateBatch(config Config) error {
+	if !config.Batch {
+		if config.JSONRecords {
+			return errorf("-json-records requires -batch")
+		}
+		if config.Null {
+			return errorf("-0 requires -batch")
+		}
+		if config.Print0 {
+			return errorf("-print0 requires -batch")
+		}
+		return nil
+	}
+	if config.TUI || (config.Confirm && !config.TTY) || config.Keychain != "" || config.PasswordFile != "" || config.Credential != "" ||
+		config.SaltedPassword != "" || config.Passphrase != "" || config.Terraform || config.Ansible {
+		return errorf("-batch reads passwords from stdin and cannot be combined with another password source")
+	}
+	if config.Apply || config.Copy || config.Target == "cockroach" {
+		return errorf("-batch cannot be combined with -apply, -copy or -target cockroach")
+	}
+	if config.SaltFromKey && !config.TTY && !config.JSONRecords {
+		return errorf("-batch -salt-from-key requires -tty or -json-records, so that each record names the user a salt is derived for")
+	}
+	return nil
+}
+
+// runBatch hashes each record read from in and writes the results in the
+// same order, to -output or stdout. Records are lines, or NUL-terminated
+// with -0 so that passwords may contain newlines; with -print0 each
+// record's result is NUL-terminated instead of newline-terminated. The
+// first invalid record stops the batch.
+//
+// With -tty the records are user names: each user's password is prompted
+// for on the terminal, the user is the -role for pg-md5 output, and with
+// -salt-from-key the salt is derived from saltKey for that user. With
+// -json-records each record is parsed by parseBatchRecord instead.
+func runBatch(config Config, in io.Reader, saltKey []byte) int {
+	sep, term := byte('\n'), "\n"
+	if config.Null {
+		sep = 0
+	}
+	if config.Print0 {
+		term = "\x00"
+	}
+
+	var buf bytes.Buffer
+	var out io.Writer = os.Stdout
+	if config.Output != "" {
+		out = &buf
+	}
+
+	scanner := bufio.NewScanner(in)
+	// Room for the longest allowed password and a CRLF, or for a JSON
+	// record holding it with every byte escaped.
+	maxRecord := maxPasswordLength + 2
+	if config.JSONRecords {
+		maxRecord = 6*maxPasswordLength + 4096
+	}
+	scanner.Buffer(make([]byte, 0, 4096), maxRecord)
+	scanner.Split(splitRecords(sep))
+	n := 0
+	for scanner.Scan() {
+		n++
+		record := scanner.Text()
+		if !config.Null {
+			record = strings.TrimSuffix(record, "\r")
+		}
+
+		recordConfig, password, salt := config, record, []byte(nil)
+		if config.JSONRecords {
+			var err error
+			if recordConfig, password, err = parseBatchRecord(config, record, saltKey != nil); err != nil {
+				f := failure{Code: codeInvalidRecord, Message: err.Error(), Record: n}
+				var fe *fieldError
+				if errors.As(err, &fe) {
+					f.Field = fe.Field
+				}
+				reportFailure(f, msg.Sprintf("Error in record %d: %v\n", n, err))
+				return exitError
+			}
+		} else if config.TTY {
+			if record == "" {
+				reportFailure(failure{Code: codeInvalidRecord, Message: "empty user name", Field: "user", Record: n},
+					msg.Sprintf("Error in record %d: empty user name\n", n))
+				return exitError
+			}
+			recordConfig.User, recordConfig.Role = record, record
+		}
+		if config.TTY {
+			var code int
+			if password, code = promptBatchPassword(config, recordConfig.User, n); code != 0 {
+				return code
+			}
+		}
+		if saltKey != nil {
+			var err error
+			if salt, err = deriveSalt(saltKey, recordConfig.User); err != nil {
+				reportFailure(failure{Code: codeError, Record: n}, msg.Sprintf("Error deriving salt: %v\n", err))
+				return exitError
+			}
+		}
+
+		if err := validatePassword(password); err != nil {
+			reportFailure(failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password", Record: n},
+				msg.Sprintf("Invalid password in record %d: %v\n", n, msg.Error(err)))
+			return exitPolicy
+		}
+		for _, warning := range passwordWarnings(password) {
+			if config.Strict {
+				reportFailure(failure{Code: codeInvalidPassword, Message: warning, Field: "password", Record: n},
+					msg.Sprintf("Invalid password in record %d: %v\n", n, warning))
+				return exitPolicy
+			}
+			warn(failure{Message: warning, Field: "password", Record: n}, msg.Sprintf("Warning in record %d: %s\n", n, warning))
+		}
+
+		output, err := generateOutput(recordConfig, password, salt)
+		if errors.Is(err, scram.ErrIterationsTooLow) {
+			reportFailure(failure{Code: codeInvalidOptions, Field: "iterations", Record: n},
+				msg.Sprintf("Invalid options: %v\n", scram.ErrIterationsTooLow))
+			return exitError
+		}
+		if err != nil {
+			reportFailure(failure{Code: codeError, Message: err.Error(), Record: n}, msg.Sprintf("Error in record %d: %v\n", n, err))
+			return exitError
+		}
+		if _, err := io.WriteString(out, strings.Join(output, "\n")+term); err != nil {
+			reportFailure(failure{Code: codeOutputError}, msg.Sprintf("Error writing output: %v\n", err))
+			return exitError
+		}
+		logGenerated(recordConfig, n)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) && config.JSONRecords {
+			reportFailure(failure{Code: codeInvalidRecord, Message: fmt.Sprintf("record is longer than %d bytes", maxRecord), Record: n + 1},
+				msg.Sprintf("Error in record %d: record is longer than %d bytes\n", n+1, maxRecord))
+			return exitError
+		}
+		if errors.Is(err, bufio.ErrTooLong) {
+			reportFailure(failure{Code: codeInvalidPassword, Message: msg.Error(&PasswordTooLongError{Max: maxPasswordLength}), Field: "password", Record: n + 1},
+				msg.Sprintf("Invalid password in record %d: %v\n", n+1, msg.Error(&PasswordTooLongError{Max: maxPasswordLength})))
+			return exitPolicy
+		}
+		reportFailure(failure{Code: codeReadError}, msg.Sprintf("Error reading records from stdin: %v\n", err))
+		return exitError
+	}
+
+	if config.Output != "" {
+		if err := writeOutputFile(config.Output, buf.Bytes(), config.Force); err != nil {
+			reportFailure(failure{Code: codeOutputError}, msg.Sprintf("Error writing output: %v\n", err))
+			return exitError
+		}
+	}
+	return 0
+}
+
+// batchRecord is a -json-records record. Fields that are left out keep
+// the value given by the flags.
+type batchRecord struct {
+	Password   string `json:"password"`
+	User       string `json:"user"`
+	Role       string `json:"role"`
+	Iterations int    `json:"iterations"`
+	Mechanism  string `json:"mechanism"`
+	Format     string `json:"format"`
+}
+
+// parseBatchRecord parses a -json-records record, returning config with
+// the record's overrides applied and the record's password. With -tty the
+// password is prompted for, so the record names the user instead, who is
+// also the role unless the record gives one. needUser is set when a
+// derived salt requires every record to name its user.
+func parseBatchRecord(config Config, record string, needUser bool) (Config, string, error) {
+	dec := json.NewDecoder(strings.NewReader(record))
+	dec.DisallowUnknownFields()
+	var r batchRecord
+	if err := dec.Decode(&r); err != nil {
+		return config, "", errorf("invalid JSON record: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return config, "", errorf("invalid JSON record: unexpected data after the object")
+	}
+
+	switch {
+	case config.TTY && r.Password != "":
+		return config, "", &fieldError{"password", errorf("the password field cannot be used with -tty, which prompts for it")}
+	case config.TTY && r.User == "":
+		return config, "", &fieldError{"user", errorf("the user field is required with -tty")}
+	case !config.TTY && r.Password == "":
+		return config, "", &fieldError{"password", errorf("the password field is required")}
+	case needUser && r.User == "":
+		return config, "", &fieldError{"user", errorf("the user field is required with -salt-from-key")}
+	}
+
+	if r.User != "" {
+		config.User = r.User
+		if config.TTY {
+			config.Role = r.User
+		}
+	}
+	if r.Role != "" {
+		config.Role = r.Role
+	}
+	if r.Iterations != 0 {
+		if r.Iterations < 1 {
+			return config, "", &fieldError{"iterations", errorf("iterations must be at least 1")}
+		}
+		config.Iterations = r.Iterations
+	}
+	if r.Format != "" {
+		config.Format = r.Format
+	}
+	if err := validateFormat(config); err != nil {
+		return config, "", &fieldError{"format", err}
+	}
+	if needUser && !saltedFormat(config.Format) {
+		return config, "", &fieldError{"format", errorf("-salt-from-key only supports -format scram, both, yaml or json")}
+	}
+	if r.Mechanism != "" {
+		if _, ok := scram.MechanismHash(r.Mechanism); !ok || strings.HasSuffix(r.Mechanism, "-PLUS") {
+			return config, "", &fieldError{"mechanism", errorf("unknown mechanism %q (want SCRAM-SHA-1, SCRAM-SHA-256 or SCRAM-SHA-512)", r.Mechanism)}
+		}
+		if r.Mechanism != "SCRAM-SHA-256" && config.Format != "scram" && config.Format != "both" {
+			return config, "", &fieldError{"mechanism", errorf("mechanism %s only applies to -format scram or both", r.Mechanism)}
+		}
+		config.Mechanism = r.Mechanism
+	}
+	return config, r.Password, nil
+}
+
+// promptBatchPassword prompts on the terminal for user's password, twice
+// with -confirm, and returns it or the exit code to stop the batch with.
+// record is the index of the record naming user.
+func promptBatchPassword(config Config, user string, record int) (string, int) {
+	prompt := msg.Sprintf("Password for %s: ", user)
+	if passwordPrompt != "" {
+		prompt = promptText(passwordPrompt)
+	}
+	if !config.Confirm {
+		password, err := promptPasswordWithText(prompt)
+		if err != nil {
+			reportFailure(failure{Code: codeReadError, Record: record}, msg.Sprintf("Error reading password: %v\n", err))
+			return "", exitError
+		}
+		return password, 0
+	}
+	password, err := promptConfirmedPassword(prompt, config.Attempts)
+	if errors.Is(err, errPasswordMismatch) {
+		reportFailure(failure{Code: codePasswordMismatch, Field: "password", Record: record},
+			msg.Sprintf("Error: %v after %d attempts\n", msg.Error(err), max(config.Attempts, 1)))
+		return "", exitMismatch
+	}
+	if err != nil {
+		reportFailure(failure{Code: codeReadError, Record: record}, msg.Sprintf("Error reading password: %v\n", err))
+		return "", exitError
+	}
+	return password, 0
+}
+
+// splitRecords is a bufio.SplitFunc for records terminated by sep, where
+// the last record need not be terminated.
+func splitRecords(sep byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
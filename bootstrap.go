@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type bootstrapConfig struct {
+	Manifest string
+	OutDir   string
+	Force    bool
+}
+
+// manifest describes the users to provision. It is read from JSON or
+// from the equivalent YAML.
+type manifest struct {
+	// Iterations applies to users that do not set their own; 0 means the
+	// target's default.
+	Iterations int `json:"iterations"`
+	// Namespace is set on the generated Kubernetes secrets.
+	Namespace string         `json:"namespace"`
+	Users     []manifestUser `json:"users"`
+}
+
+type manifestUser struct {
+	Name       string   `json:"name"`
+	Password   string   `json:"password"`
+	Generate   bool     `json:"generate"`
+	Iterations int      `json:"iterations"`
+	Targets    []string `json:"targets"`
+	// Secret names the Kubernetes secret; the default is derived from
+	// the user name.
+	Secret string `json:"secret"`
+}
+
+// bootstrapTargets maps each target to the artifact it is written to.
+var bootstrapTargets = map[string]string{
+	"postgres":   "postgres.sql",
+	"cockroach":  "cockroach.sql",
+	"pgbouncer":  "userlist.txt",
+	"kubernetes": "secrets.yaml",
+}
+
+// generatedPasswordLength gives about 143 bits from generatedAlphabet.
+const (
+	generatedPasswordLength = 24
+	generatedAlphabet       = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+var secretNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+func bootstrapFlags(config *bootstrapConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("bootstrap", flag.ExitOnError)
+	fs.StringVar(&config.Manifest, "manifest", "", "JSON or YAML manifest of users (- for stdin)")
+	fs.StringVar(&config.OutDir, "out-dir", ".", "Directory to write the artifacts to")
+	fs.BoolVar(&config.Force, "force", false, "Overwrite existing artifacts")
+	return fs
+}
+
+func runBootstrap(args []string) int {
+	config := bootstrapConfig{}
+
+	fs := bootstrapFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if err := bootstrap(config, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func bootstrap(config bootstrapConfig, w io.Writer) error {
+	if config.Manifest == "" {
+		return errorf("-manifest is required")
+	}
+	m, err := readManifest(config.Manifest)
+	if err != nil {
+		return err
+	}
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	// Refuse before any work is done if an artifact would be clobbered.
+	artifacts := make(map[string]*bytes.Buffer)
+	for _, u := range m.Users {
+		for _, target := range u.Targets {
+			artifacts[bootstrapTargets[target]] = &bytes.Buffer{}
+		}
+		if u.Generate {
+			artifacts["passwords.txt"] = &bytes.Buffer{}
+		}
+	}
+	for name := range artifacts {
+		if err := checkOutputFile(filepath.Join(config.OutDir, name), config.Force); err != nil {
+			return err
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, u := range m.Users {
+		password := u.Password
+		if u.Generate {
+			if password, err = generateRandomPassword(); err != nil {
+				return err
+			}
+			fmt.Fprintf(artifacts["passwords.txt"], "%s:%s\n", u.Name, password)
+			counts["passwords.txt"]++
+		}
+
+		// Verifiers are shared between targets that use the same cost.
+		verifiers := make(map[int]string)
+		verifier := func(iterations int) (string, error) {
+			if v, ok := verifiers[iterations]; ok {
+				return v, nil
+			}
+			v, err := generateSCRAMSHA256(password, iterations)
+			verifiers[iterations] = v
+			return v, err
+		}
+
+		for _, target := range u.Targets {
+			iterations := m.iterationsFor(u, target)
+			v, err := verifier(iterations)
+			if err != nil {
+				return errorf("user %s: %w", u.Name, err)
+			}
+
+			name := bootstrapTargets[target]
+			buf := artifacts[name]
+			switch target {
+			case "postgres", "cockroach":
+				fmt.Fprintln(buf, passwordStatement(target, u.Name, v))
+			case "pgbouncer":
+				fmt.Fprintf(buf, "%s %s\n", userlistQuote(u.Name), userlistQuote(v))
+			case "kubernetes":
+				writeKubernetesSecret(buf, m.Namespace, u, password, v)
+			}
+			counts[name]++
+		}
+	}
+
+	if err := os.MkdirAll(config.OutDir, 0700); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(artifacts) {
+		path := filepath.Join(config.OutDir, name)
+		if err := writeOutputFile(path, artifacts[name].Bytes(), config.Force); err != nil {
+			return errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Fprintf(w, "Wrote %s (%d entries)\n", path, counts[name])
+	}
+	return nil
+}
+
+func readManifest(path string) (*manifest, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errorf("failed to read manifest: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxDocumentSize+1))
+	if err != nil {
+		return nil, errorf("failed to read manifest: %w", err)
+	}
+	if len(data) > maxDocumentSize {
+		return nil, errorf("manifest exceeds %d bytes", maxDocumentSize)
+	}
+
+	// YAML is converted to JSON so both formats decode through the same
+	// struct tags and unknown-field check.
+	if trimmed := bytes.TrimSpace(data); !bytes.HasPrefix(trimmed, []byte("{")) {
+		doc, err := parseYAML(string(data))
+		if err != nil {
+			return nil, errorf("failed to parse manifest: %w", err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, errorf("failed to parse manifest: %w", err)
+		}
+	}
+
+	var m manifest
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, errorf("failed to decode manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func (m *manifest) validate() error {
+	if len(m.Users) == 0 {
+		return errorf("manifest lists no users")
+	}
+	if m.Iterations < 0 {
+		return errorf("iterations must be at least 1")
+	}
+	if m.Namespace != "" && !secretNamePattern.MatchString(m.Namespace) {
+		return errorf("%q is not a valid Kubernetes namespace", m.Namespace)
+	}
+
+	seen := make(map[string]bool)
+	secrets := make(map[string]bool)
+	for i := range m.Users {
+		u := &m.Users[i]
+		if u.Name == "" {
+			return errorf("user %d has no name", i+1)
+		}
+		if seen[u.Name] {
+			return errorf("user %s is listed twice", u.Name)
+		}
+		seen[u.Name] = true
+
+		switch {
+		case u.Generate && u.Password != "":
+			return errorf("user %s sets both password and generate", u.Name)
+		case !u.Generate:
+			if err := validatePassword(u.Password); err != nil {
+				return errorf("user %s: invalid password: %w", u.Name, err)
+			}
+		}
+		if u.Iterations < 0 {
+			return errorf("user %s: iterations must be at least 1", u.Name)
+		}
+
+		if len(u.Targets) == 0 {
+			return errorf("user %s has no targets", u.Name)
+		}
+		listed := make(map[string]bool)
+		for _, target := range u.Targets {
+			if _, ok := bootstrapTargets[target]; !ok {
+				return errorf("user %s: unknown target %q (use postgres, cockroach, pgbouncer or kubernetes)", u.Name, target)
+			}
+			if listed[target] {
+				return errorf("user %s: target %s is listed twice", u.Name, target)
+			}
+			listed[target] = true
+			if target != "kubernetes" {
+				continue
+			}
+			if u.Secret == "" {
+				u.Secret = secretName(u.Name)
+			}
+			if !secretNamePattern.MatchString(u.Secret) {
+				return errorf("user %s: %q is not a valid Kubernetes secret name", u.Name, u.Secret)
+			}
+			if secrets[u.Secret] {
+				return errorf("user %s: secret %s is used twice", u.Name, u.Secret)
+			}
+			secrets[u.Secret] = true
+		}
+	}
+	return nil
+}
+
+// iterationsFor picks the user's iteration count, then the manifest's,
+// then the target's default.
+func (m *manifest) iterationsFor(u manifestUser, target string) int {
+	switch {
+	case u.Iterations > 0:
+		return u.Iterations
+	case m.Iterations > 0:
+		return m.Iterations
+	case target == "cockroach":
+		return cockroachIterations
+	}
+	return defaultIterations
+}
+
+// secretName derives a Kubernetes secret name from a user name.
+func secretName(user string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, user)
+	return strings.Trim(name, "-.") + "-credentials"
+}
+
+// userlistQuote quotes a value for a PgBouncer userlist.txt.
+func userlistQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// writeKubernetesSecret appends an Opaque secret holding the user name,
+// password and verifier. Values are base64 so no YAML quoting is needed.
+func writeKubernetesSecret(w io.Writer, namespace string, u manifestUser, password, verifier string) {
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	fmt.Fprintln(w, "---")
+	fmt.Fprintln(w, "apiVersion: v1")
+	fmt.Fprintln(w, "kind: Secret")
+	fmt.Fprintln(w, "metadata:")
+	fmt.Fprintf(w, "  name: %s\n", u.Secret)
+	if namespace != "" {
+		fmt.Fprintf(w, "  namespace: %s\n", namespace)
+	}
+	fmt.Fprintln(w, "type: Opaque")
+	fmt.Fprintln(w, "data:")
+	fmt.Fprintf(w, "  username: %s\n", b64(u.Name))
+	fmt.Fprintf(w, "  password: %s\n", b64(password))
+	fmt.Fprintf(w, "  verifier: %s\n", b64(verifier))
+}
+
+// generateRandomPassword returns a password drawn uniformly from
+// generatedAlphabet.
+func generateRandomPassword() (string, error) {
+	max := big.NewInt(int64(len(generatedAlphabet)))
+	b := make([]byte, generatedPasswordLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errorf("failed to generate password: %w", err)
+		}
+		b[i] = generatedAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
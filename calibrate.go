@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// defaultCalibrationBudget is the derivation time aimed for by
+// -iterations auto when no duration is given.
+const defaultCalibrationBudget = 100 * time.Millisecond
+
+// iterationsValue is the value of -i and -iterations: either a count, or
+// auto[:duration] to pick the count by benchmarking the host, in which case
+// budget is set and count is filled in later by calibrateIterations.
+type iterationsValue struct {
+	count  *int
+	budget *time.Duration
+}
+
+func (v iterationsValue) String() string {
+	if v.count == nil {
+		return ""
+	}
+	if *v.budget > 0 {
+		return "auto:" + v.budget.String()
+	}
+	return strconv.Itoa(*v.count)
+}
+
+func (v iterationsValue) Set(value string) error {
+	if rest, ok := strings.CutPrefix(value, "auto"); ok && (rest == "" || rest[0] == ':') {
+		budget := defaultCalibrationBudget
+		if rest != "" {
+			d, err := time.ParseDuration(rest[1:])
+			if err != nil {
+				return err
+			}
+			if d <= 0 {
+				return errorf("auto duration must be positive")
+			}
+			budget = d
+		}
+		*v.budget = budget
+		return nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errorf("expected a number or auto[:duration]")
+	}
+	*v.count = n
+	*v.budget = 0
+	return nil
+}
+
+// calibrateIterations returns the largest iteration count whose PBKDF2
+// derivation with h fits in budget on this host, but never less than
+// defaultIterations. The rate is measured with doubling trial counts until
+// a trial takes a tenth of the budget, so the estimate is not dominated by
+// timer resolution and calibration costs well under the budget itself.
+func calibrateIterations(h crypto.Hash, budget time.Duration) (int, error) {
+	password := []byte("calibration")
+	salt := make([]byte, 16)
+
+	n := 1024
+	for {
+		start := time.Now()
+		if _, err := (scram.PBKDF2{}).Key(h, password, salt, n); err != nil {
+			return 0, err
+		}
+		elapsed := time.Since(start)
+		if elapsed >= budget/10 || elapsed >= time.Second {
+			iterations := int(float64(n) * float64(budget) / float64(elapsed))
+			return max(iterations, defaultIterations), nil
+		}
+		n *= 2
+	}
+}
+
+// formatHash is the hash whose PBKDF2 cost -format determines, for
+// calibration.
+func formatHash(format string) crypto.Hash {
+	if format == "prosody" {
+		return crypto.SHA1
+	}
+	return crypto.SHA256
+}
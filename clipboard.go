@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// clipboardCommands lists, per platform, the commands tried in order to
+// write and read the system clipboard.
+var clipboardCommands = map[string][]struct{ copy, paste []string }{
+	"darwin": {
+		{[]string{"pbcopy"}, []string{"pbpaste"}},
+	},
+	"windows": {
+		{[]string{"clip"}, []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}},
+	},
+	"linux": {
+		{[]string{"wl-copy"}, []string{"wl-paste", "--no-newline"}},
+		{[]string{"xclip", "-selection", "clipboard"}, []string{"xclip", "-selection", "clipboard", "-o"}},
+		{[]string{"xsel", "--clipboard", "--input"}, []string{"xsel", "--clipboard", "--output"}},
+	},
+}
+
+type clipboard struct {
+	copy, paste []string
+}
+
+func findClipboard() (clipboard, error) {
+	for _, c := range clipboardCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(c.copy[0]); err == nil {
+			return clipboard{c.copy, c.paste}, nil
+		}
+	}
+	return clipboard{}, errorf("no clipboard tool found for %s", runtime.GOOS)
+}
+
+func (c clipboard) write(text string) error {
+	cmd := exec.Command(c.copy[0], c.copy[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return errorf("failed to write clipboard: %w", err)
+	}
+	return nil
+}
+
+func (c clipboard) read() (string, error) {
+	out, err := exec.Command(c.paste[0], c.paste[1:]...).Output()
+	return normalizeNewlines(string(out)), err
+}
+
+// normalizeNewlines converts CRLF line endings to LF and drops trailing
+// ones, since clip and Get-Clipboard on Windows convert line endings and
+// most paste commands append one.
+func normalizeNewlines(s string) string {
+	return strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\r\n")
+}
+
+// copyToClipboard places text on the clipboard and, unless timeout is zero,
+// blocks until the timeout expires or the process is interrupted, then
+// clears the clipboard if it still holds text.
+func copyToClipboard(text string, timeout time.Duration) error {
+	cb, err := findClipboard()
+	if err != nil {
+		return err
+	}
+	if err := cb.write(text); err != nil {
+		return err
+	}
+	if timeout == 0 {
+		fmt.Fprintln(os.Stderr, msg.Text("Copied to clipboard."))
+		return nil
+	}
+
+	msg.Eprintf("Copied to clipboard; clearing in %s (Ctrl-C to clear now).\n", timeout)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	select {
+	case <-time.After(timeout):
+	case <-interrupt:
+	}
+
+	// Leave the clipboard alone if the user has since copied something else.
+	if current, err := cb.read(); err == nil && current != normalizeNewlines(text) {
+		return nil
+	}
+	if err := cb.write(""); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, msg.Text("Clipboard cleared."))
+	return nil
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SonOfBytes/scram-sha-256/internal/pgwire"
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	"github.com/SonOfBytes/scram-sha-256/sasl/pgauth"
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+type conformanceConfig struct {
+	UseStdin bool
+	Host     string
+	User     string
+	Protocol string
+	Database string
+	SSLMode  string
+}
+
+// conformanceResult is one row of the pass/fail matrix.
+type conformanceResult struct {
+	scenario string
+	status   string // PASS, FAIL or SKIP
+	detail   string
+}
+
+func conformanceFlags(config *conformanceConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("conformance", flag.ExitOnError)
+	fs.BoolVar(&config.UseStdin, "stdin", false, "Read password from stdin instead of prompting")
+	fs.StringVar(&config.Host, "host", "", "Server to test, as host:port")
+	fs.StringVar(&config.User, "user", "", "User to authenticate as")
+	fs.StringVar(&config.Protocol, "protocol", "postgres", "Wire protocol: postgres, or scram for the line protocol of serve -mock")
+	fs.StringVar(&config.Database, "database", "", "Database to connect to (postgres only)")
+	fs.StringVar(&config.SSLMode, "sslmode", "prefer", "TLS mode: disable, prefer, require or verify-full (postgres only)")
+	return fs
+}
+
+func runConformance(args []string) int {
+	config := conformanceConfig{}
+
+	fs := conformanceFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if config.Host == "" || config.User == "" {
+		fmt.Fprintln(os.Stderr, "Error: -host and -user are required")
+		return 1
+	}
+	if config.Protocol != "postgres" && config.Protocol != "scram" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported protocol %q (use postgres or scram)\n", config.Protocol)
+		return 1
+	}
+
+	var password string
+	var err error
+	if config.UseStdin {
+		password, err = readPasswordFromStdin()
+	} else {
+		password, err = promptPassword()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+		return 1
+	}
+
+	var results []conformanceResult
+	if config.Protocol == "postgres" {
+		results, err = postgresConformance(config, password)
+	} else {
+		results = scramConformance(config, password)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	failed := printConformance(os.Stdout, results)
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d scenarios failed\n", failed)
+		return 1
+	}
+	return 0
+}
+
+func printConformance(w io.Writer, results []conformanceResult) int {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCENARIO\tRESULT\tDETAIL")
+	failed := 0
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.scenario, r.status, r.detail)
+		if r.status == "FAIL" {
+			failed++
+		}
+	}
+	tw.Flush()
+	return failed
+}
+
+// postgresConformance runs each scenario on a fresh connection. The first
+// connection also discovers whether TLS is in use and which mechanisms the
+// server offers, which decide the channel binding scenarios.
+func postgresConformance(config conformanceConfig, password string) ([]conformanceResult, error) {
+	host, port, err := net.SplitHostPort(config.Host)
+	if err != nil {
+		return nil, errorf("invalid -host: %w", err)
+	}
+	base := pgwire.Config{Host: host, Port: port, User: config.User, Database: config.Database, SSLMode: config.SSLMode}
+
+	// connect authenticates with password after letting adjust configure
+	// the exchange, and returns the mechanism used. It records whether
+	// the connection used TLS.
+	var usesTLS bool
+	connect := func(password string, adjust func(*pgauth.Authenticator)) (string, error) {
+		cfg := base
+		cfg.Password = password
+		cfg.Auth = func(a *pgauth.Authenticator) {
+			usesTLS = a.TLS != nil
+			if adjust != nil {
+				adjust(a)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+		defer cancel()
+		conn, err := pgwire.Connect(ctx, cfg)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+		return conn.Mechanism, nil
+	}
+
+	var results []conformanceResult
+	add := func(scenario, status, detail string) {
+		results = append(results, conformanceResult{scenario, status, detail})
+	}
+
+	var recorder *pgauth.Authenticator
+	mech, err := connect(password, func(a *pgauth.Authenticator) { recorder = a })
+	switch {
+	case err != nil:
+		if !expectRejected(err) {
+			return nil, errorf("failed to connect to %s: %w", config.Host, err)
+		}
+		add("authenticate", "FAIL", err.Error())
+	case !strings.HasPrefix(mech, "SCRAM-"):
+		add("authenticate", "FAIL", "server accepted the connection without SCRAM")
+	default:
+		add("authenticate", "PASS", fmt.Sprintf("%s, server signature verified", mech))
+	}
+	plus := false
+	if recorder != nil {
+		for _, name := range recorder.Offered() {
+			plus = plus || strings.HasSuffix(name, "-PLUS")
+		}
+	}
+
+	if _, err := connect(password+"-wrong", nil); expectRejected(err) {
+		add("wrong password rejected", "PASS", err.Error())
+	} else {
+		add("wrong password rejected", "FAIL", rejectionDetail(err))
+	}
+
+	switch {
+	case !usesTLS:
+		add("channel binding", "SKIP", "connection does not use TLS")
+		add("channel binding mismatch rejected", "SKIP", "connection does not use TLS")
+		add("downgrade rejected", "SKIP", "connection does not use TLS")
+		return results, nil
+	case !plus:
+		add("channel binding", "SKIP", "server does not offer a -PLUS mechanism")
+		add("channel binding mismatch rejected", "SKIP", "server does not offer a -PLUS mechanism")
+		// A server without -PLUS must accept the y flag.
+		if mech, err := connect(password, func(a *pgauth.Authenticator) { a.Allowed = []string{"SCRAM-SHA-256"} }); err != nil {
+			add("downgrade flag accepted", "FAIL", err.Error())
+		} else {
+			add("downgrade flag accepted", "PASS", mech+" with gs2 flag y")
+		}
+		return results, nil
+	}
+
+	if mech, err := connect(password, func(a *pgauth.Authenticator) { a.Allowed = []string{"SCRAM-SHA-256-PLUS"} }); err != nil {
+		add("channel binding", "FAIL", err.Error())
+	} else {
+		add("channel binding", "PASS", mech+" with tls-server-end-point")
+	}
+
+	_, err = connect(password, func(a *pgauth.Authenticator) {
+		a.Allowed = []string{"SCRAM-SHA-256-PLUS"}
+		a.ChannelBinding = &sasl.ChannelBinding{Type: "tls-server-end-point", Data: make([]byte, 32)}
+	})
+	if expectRejected(err) {
+		add("channel binding mismatch rejected", "PASS", err.Error())
+	} else {
+		add("channel binding mismatch rejected", "FAIL", rejectionDetail(err))
+	}
+
+	// Without HidePlus the client sees the -PLUS offer and sends "n,,",
+	// which a correct server accepts.
+	_, err = connect(password, func(a *pgauth.Authenticator) {
+		a.Allowed = []string{"SCRAM-SHA-256"}
+		a.HidePlus = true
+	})
+	if expectRejected(err) {
+		add("downgrade rejected", "PASS", err.Error())
+	} else {
+		add("downgrade rejected", "FAIL", rejectionDetail(err))
+	}
+	return results, nil
+}
+
+// expectRejected reports whether the server itself refused the
+// connection, as opposed to the client giving up.
+func expectRejected(err error) bool {
+	var pgErr *pgwire.Error
+	return errors.As(err, &pgErr)
+}
+
+func rejectionDetail(err error) string {
+	if err == nil {
+		return "server accepted the connection"
+	}
+	return err.Error()
+}
+
+// scramConformance tests a server speaking the line protocol of serve
+// -mock, which has no TLS, so only the password scenarios apply.
+func scramConformance(config conformanceConfig, password string) []conformanceResult {
+	var results []conformanceResult
+	add := func(scenario, status, detail string) {
+		results = append(results, conformanceResult{scenario, status, detail})
+	}
+
+	for _, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA512} {
+		name := scram.MechanismName(h)
+		if err := scramLineExchange(config.Host, h, config.User, password); err != nil {
+			add(name+" authenticate", "FAIL", err.Error())
+		} else {
+			add(name+" authenticate", "PASS", "server signature verified")
+		}
+
+		err := scramLineExchange(config.Host, h, config.User, password+"-wrong")
+		switch {
+		case errors.Is(err, scram.ErrProofMismatch):
+			add(name+" wrong password rejected", "PASS", err.Error())
+		default:
+			add(name+" wrong password rejected", "FAIL", rejectionDetail(err))
+		}
+	}
+	add("channel binding", "SKIP", "protocol has no TLS")
+	add("downgrade rejected", "SKIP", "protocol has no TLS")
+	return results
+}
+
+// scramLineExchange runs one conversation using the testscram protocol.
+func scramLineExchange(addr string, h crypto.Hash, user, password string) error {
+	client, err := scram.NewClient(scram.ClientConfig{Hash: h, Username: user, Password: password})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	r := bufio.NewReader(conn)
+
+	msg, err := client.Start()
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "%s %s\n", scram.MechanismName(h), msg); err != nil {
+		return err
+	}
+	for !client.Done() {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return errorf("reading from server: %w", err)
+		}
+		if msg, err = client.Next([]byte(strings.TrimRight(line, "\r\n"))); err != nil {
+			return err
+		}
+		if msg != nil {
+			if _, err := fmt.Fprintf(conn, "%s\n", msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
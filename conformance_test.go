@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+// fakePostgres is a PostgreSQL server reduced to TLS and SCRAM-SHA-256
+// authentication. It offers SCRAM-SHA-256-PLUS when plus is set.
+type fakePostgres struct {
+	cert tls.Certificate
+	plus bool
+}
+
+func newFakePostgres(t *testing.T, plus bool) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakePostgres{cert: tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, plus: plus}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func (s *fakePostgres) serve(raw net.Conn) {
+	defer raw.Close()
+	raw.SetDeadline(time.Now().Add(10 * time.Second))
+
+	var conn net.Conn = raw
+	r := bufio.NewReader(conn)
+	startup, err := readStartup(r)
+	if err != nil {
+		return
+	}
+	if binary.BigEndian.Uint32(startup) == 80877103 { // SSLRequest
+		raw.Write([]byte{'S'})
+		tc := tls.Server(raw, &tls.Config{Certificates: []tls.Certificate{s.cert}})
+		conn, r = tc, bufio.NewReader(tc)
+		if startup, err = readStartup(r); err != nil {
+			return
+		}
+	}
+	user := ""
+	fields := strings.Split(string(startup[4:]), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "user" {
+			user = fields[i+1]
+		}
+	}
+
+	send := func(typ byte, body []byte) {
+		msg := append([]byte{typ}, binary.BigEndian.AppendUint32(nil, uint32(4+len(body)))...)
+		conn.Write(append(msg, body...))
+	}
+	reject := func(err error) {
+		send('E', []byte("SFATAL\x00C28P01\x00M"+err.Error()+"\x00\x00"))
+	}
+	auth := func(code uint32, data []byte) {
+		send('R', append(binary.BigEndian.AppendUint32(nil, code), data...))
+	}
+
+	offer := []byte("SCRAM-SHA-256\x00")
+	if s.plus {
+		offer = append([]byte("SCRAM-SHA-256-PLUS\x00"), offer...)
+	}
+	auth(10, append(offer, 0))
+
+	body, err := readPasswordMessage(r)
+	if err != nil {
+		return
+	}
+	mech, rest, _ := strings.Cut(string(body), "\x00")
+	if len(rest) < 4 {
+		return
+	}
+	cb, err := sasl.TLSServerEndPoint(s.cert.Leaf)
+	if err != nil {
+		reject(err)
+		return
+	}
+	server, err := scram.NewServer(scram.ServerConfig{
+		Hash: scramtest.Alice.Hash,
+		// As in PostgreSQL, the role comes from the startup message and
+		// the SCRAM username is ignored.
+		Lookup: scram.CredentialLookupFunc(func(string) (scram.StoredCredentials, error) {
+			return scramtest.Store(scramtest.Alice).Lookup(user)
+		}),
+		Plus:           mech == "SCRAM-SHA-256-PLUS",
+		ChannelBinding: cb,
+		PlusAdvertised: s.plus,
+	})
+	if err != nil {
+		reject(err)
+		return
+	}
+
+	response := []byte(rest[4:])
+	for {
+		challenge, done, err := server.Next(response)
+		if err != nil {
+			reject(err)
+			return
+		}
+		if done {
+			auth(12, challenge)
+			auth(0, nil)
+			send('Z', []byte{'I'})
+			readPasswordMessage(r) // Terminate
+			return
+		}
+		auth(11, challenge)
+		if response, err = readPasswordMessage(r); err != nil {
+			return
+		}
+	}
+}
+
+func readStartup(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	body := make([]byte, n-4)
+	_, err := io.ReadFull(r, body)
+	return body, err
+}
+
+func readPasswordMessage(r io.Reader) ([]byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	body := make([]byte, binary.BigEndian.Uint32(hdr[1:])-4)
+	_, err := io.ReadFull(r, body)
+	return body, err
+}
+
+func TestPostgresConformance(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		plus bool
+		want map[string]string
+	}{
+		{"plus offered", true, map[string]string{
+			"authenticate":                      "PASS",
+			"wrong password rejected":           "PASS",
+			"channel binding":                   "PASS",
+			"channel binding mismatch rejected": "PASS",
+			"downgrade rejected":                "PASS",
+		}},
+		{"plus not offered", false, map[string]string{
+			"authenticate":                      "PASS",
+			"wrong password rejected":           "PASS",
+			"channel binding":                   "SKIP",
+			"channel binding mismatch rejected": "SKIP",
+			"downgrade flag accepted":           "PASS",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := newFakePostgres(t, tc.plus)
+			config := conformanceConfig{Host: addr, User: scramtest.Alice.Username, SSLMode: "require"}
+			results, err := postgresConformance(config, scramtest.Alice.Password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(tc.want) {
+				t.Errorf("got %d rows, want %d: %v", len(results), len(tc.want), results)
+			}
+			for _, r := range results {
+				if want := tc.want[r.scenario]; r.status != want {
+					t.Errorf("%s: got %s (%s), want %s", r.scenario, r.status, r.detail, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readCredential reads a password passed by systemd with LoadCredential=
+// or SetCredentialEncrypted=, which it decrypts into the directory named
+// by $CREDENTIALS_DIRECTORY. Only the first line is used, as with
+// -password-file.
+func readCredential(name string) (string, error) {
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return "", errorf("$CREDENTIALS_DIRECTORY is not set; run under systemd with LoadCredential=%s", name)
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsRune(name, '/') {
+		return "", errorf("invalid credential name %q", name)
+	}
+	return readPasswordFile(filepath.Join(dir, name), false)
+}
+
+// credentialUserFlags collects repeated -user-credential user[:credential]
+// flags, mapping each user to the credential holding its password.
+type credentialUserFlags map[string]string
+
+func (c credentialUserFlags) String() string {
+	return userFlags(c).String()
+}
+
+func (c credentialUserFlags) Set(value string) error {
+	name, credential, ok := strings.Cut(value, ":")
+	if name == "" {
+		return errorf("expected user or user:credential")
+	}
+	if !ok {
+		credential = name
+	}
+	c[name] = credential
+	return nil
+}
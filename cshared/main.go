@@ -0,0 +1,96 @@
+//go:build cgo
+
+// Command cshared builds a C shared library exposing verifier generation
+// and verification, so scripts in other languages can call the same code
+// over FFI:
+//
+//	go build -buildmode=c-shared -o libscram.so ./cshared
+//
+// The build also writes libscram.h declaring the exported functions.
+// Strings returned by the library are allocated with malloc and must be
+// released with scram_free.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"crypto"
+	"time"
+	"unsafe"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+func main() {}
+
+// scram_generate returns a SCRAM-SHA-256 verifier for password, or NULL
+// with *err set to a message describing the failure.
+//
+//export scram_generate
+func scram_generate(password *C.char, iterations C.int, err **C.char) *C.char {
+	pw := C.GoString(password)
+	if pw == "" {
+		setError(err, "password cannot be empty")
+		return nil
+	}
+
+	verifier, genErr := scram.NewVerifier(crypto.SHA256, pw, int(iterations))
+	if genErr != nil {
+		setError(err, genErr.Error())
+		return nil
+	}
+	return C.CString(verifier)
+}
+
+// scram_verify returns 1 if password matches verifier, 0 if it does not,
+// and -1 with *err set if the verifier cannot be parsed.
+//
+//export scram_verify
+func scram_verify(password, verifier *C.char, err **C.char) C.int {
+	ok, verifyErr := scram.Verify(C.GoString(password), C.GoString(verifier))
+	if verifyErr != nil {
+		setError(err, verifyErr.Error())
+		return -1
+	}
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// scram_set_limits bounds the PBKDF2 derivations that scram_generate and
+// scram_verify run at once across all threads; see scram.Limiter. Calls
+// beyond the limits fail with *err set to a "too many concurrent
+// derivations" message, which a service can answer with HTTP 503. A
+// max_concurrent of zero means one per CPU, and zero max_queue or
+// queue_timeout_ms means no limit. It must be called before the first
+// derivation, and returns -1 if any argument is negative.
+//
+//export scram_set_limits
+func scram_set_limits(maxConcurrent, maxQueue, queueTimeoutMs C.int) C.int {
+	if maxConcurrent < 0 || maxQueue < 0 || queueTimeoutMs < 0 {
+		return -1
+	}
+	scram.DefaultLimiter = &scram.Limiter{
+		MaxConcurrent: int(maxConcurrent),
+		MaxQueue:      int(maxQueue),
+		QueueTimeout:  time.Duration(queueTimeoutMs) * time.Millisecond,
+	}
+	return 0
+}
+
+// scram_free releases a string returned by the library.
+//
+//export scram_free
+func scram_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+func setError(err **C.char, msg string) {
+	if err != nil {
+		*err = C.CString(msg)
+	}
+}
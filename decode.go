@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// proofHashes maps proof and signature lengths to the mechanism producing them.
+var proofHashes = map[int]string{20: "SCRAM-SHA-1", 32: "SCRAM-SHA-256", 64: "SCRAM-SHA-512"}
+
+type decodeConfig struct {
+	Base64 bool
+}
+
+// messageDecoder remembers nonces across messages so a whole exchange can
+// be checked for consistency.
+type messageDecoder struct {
+	w           io.Writer
+	kind        string
+	clientNonce string
+	serverNonce string
+	problems    int
+}
+
+func decodeFlags(config *decodeConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	fs.BoolVar(&config.Base64, "base64", false, "Messages are base64-encoded, as in SMTP, IMAP or driver logs")
+	return fs
+}
+
+func runDecode(args []string) int {
+	config := decodeConfig{}
+
+	fs := decodeFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	messages := fs.Args()
+	if len(messages) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				messages = append(messages, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading messages from stdin: %v\n", err)
+			return 1
+		}
+	}
+
+	d := &messageDecoder{w: os.Stdout}
+	for i, msg := range messages {
+		if i > 0 {
+			fmt.Fprintln(d.w)
+		}
+		if config.Base64 {
+			raw, err := base64.StdEncoding.DecodeString(msg)
+			if err != nil {
+				fmt.Fprintf(d.w, "%q\n", msg)
+				d.problem("message is not valid base64: %v", err)
+				continue
+			}
+			msg = string(raw)
+		}
+		d.decode(msg)
+	}
+
+	if d.problems > 0 {
+		fmt.Fprintf(os.Stderr, "%d problems found\n", d.problems)
+		return 1
+	}
+	return 0
+}
+
+func (d *messageDecoder) decode(msg string) {
+	switch {
+	case strings.HasPrefix(msg, "n,") || strings.HasPrefix(msg, "y,") || strings.HasPrefix(msg, "p="):
+		d.start("client-first")
+		d.decodeClientFirst(msg)
+	case strings.HasPrefix(msg, "r=") || strings.HasPrefix(msg, "m="):
+		d.start("server-first")
+		d.decodeAttributes(msg, "rsi")
+	case strings.HasPrefix(msg, "c="):
+		d.start("client-final")
+		d.decodeAttributes(msg, "cr")
+	case strings.HasPrefix(msg, "v=") || strings.HasPrefix(msg, "e="):
+		d.start("server-final")
+		d.decodeAttributes(msg, "")
+	default:
+		fmt.Fprintf(d.w, "%q\n", msg)
+		d.problem("unrecognised message type")
+	}
+}
+
+func (d *messageDecoder) start(kind string) {
+	d.kind = kind
+	fmt.Fprintf(d.w, "%s message\n", kind)
+}
+
+func (d *messageDecoder) decodeClientFirst(msg string) {
+	parts := strings.SplitN(msg, ",", 3)
+	if len(parts) < 3 {
+		d.problem("gs2 header is incomplete")
+		return
+	}
+
+	d.describeGS2(parts[0], parts[1])
+	d.decodeAttributes(parts[2], "nr")
+}
+
+func (d *messageDecoder) describeGS2(flag, authz string) {
+	switch {
+	case flag == "n":
+		d.field("gs2", "channel binding: n (client does not support it)")
+	case flag == "y":
+		d.field("gs2", "channel binding: y (client supports it, thinks the server does not)")
+	case strings.HasPrefix(flag, "p=") && len(flag) > 2:
+		d.field("gs2", "channel binding: p (required, type %s)", flag[2:])
+	default:
+		d.problem("invalid gs2 channel binding flag %q", flag)
+	}
+
+	switch {
+	case authz == "":
+		d.field("gs2", "authzid: (none)")
+	case strings.HasPrefix(authz, "a="):
+		d.field("gs2", "authzid: %s", d.saslname(authz[2:]))
+	default:
+		d.problem("invalid gs2 authzid %q", authz)
+	}
+}
+
+// decodeAttributes prints every attribute of msg, checking that it starts
+// with the attributes listed in required.
+func (d *messageDecoder) decodeAttributes(msg, required string) {
+	for i, part := range strings.Split(msg, ",") {
+		if len(part) < 2 || part[1] != '=' {
+			d.problem("malformed attribute %q", part)
+			continue
+		}
+		if i < len(required) && part[0] != required[i] {
+			d.problem("expected attribute %q at position %d, got %q", required[i], i+1, part[0])
+		}
+		d.describe(part[0], part[2:])
+	}
+}
+
+func (d *messageDecoder) describe(key byte, value string) {
+	k := string(key)
+	switch key {
+	case 'n':
+		d.field(k, "username: %s", d.saslname(value))
+	case 'r':
+		d.describeNonce(value)
+	case 's':
+		if salt, err := base64.StdEncoding.DecodeString(value); err != nil {
+			d.problem("salt is not valid base64: %v", err)
+		} else {
+			d.field(k, "salt: %s (%d bytes)", value, len(salt))
+		}
+	case 'i':
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			d.problem("iteration count %q is not a positive integer", value)
+		} else {
+			d.field(k, "iterations: %d", n)
+		}
+	case 'c':
+		cbind, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			d.problem("channel binding is not valid base64: %v", err)
+			return
+		}
+		header, data := splitCBind(cbind)
+		d.field(k, "channel binding: gs2 header %q, %d bytes of binding data", header, len(data))
+	case 'p':
+		d.describeDigest(k, "client proof", value)
+	case 'v':
+		d.describeDigest(k, "server signature", value)
+	case 'e':
+		d.field(k, "server error: %s", value)
+	case 'm':
+		d.field(k, "mandatory extension: %s", value)
+		d.problem("reserved m= extension is not supported by any known implementation")
+	default:
+		d.field(k, "extension: %s", value)
+	}
+}
+
+func (d *messageDecoder) describeNonce(nonce string) {
+	if nonce == "" {
+		d.problem("nonce is empty")
+		return
+	}
+	for _, c := range nonce {
+		if c < 0x21 || c > 0x7e || c == ',' {
+			d.problem("nonce contains non-printable character %q", c)
+			return
+		}
+	}
+
+	switch d.kind {
+	case "client-first":
+		d.clientNonce = nonce
+		d.field("r", "nonce: %s (%d chars)", nonce, len(nonce))
+	case "server-first":
+		d.serverNonce = nonce
+		if d.clientNonce == "" {
+			d.field("r", "nonce: %s (%d chars)", nonce, len(nonce))
+			return
+		}
+		if !strings.HasPrefix(nonce, d.clientNonce) || nonce == d.clientNonce {
+			d.problem("server nonce does not extend the client nonce")
+		}
+		d.field("r", "nonce: %s (client nonce + %d chars)", nonce, len(nonce)-len(d.clientNonce))
+	default:
+		if d.serverNonce != "" && nonce != d.serverNonce {
+			d.problem("nonce does not match the server-first nonce")
+		}
+		d.field("r", "nonce: %s", nonce)
+	}
+}
+
+func (d *messageDecoder) describeDigest(key, name, value string) {
+	digest, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		d.problem("%s is not valid base64: %v", name, err)
+		return
+	}
+	mech, ok := proofHashes[len(digest)]
+	if !ok {
+		d.problem("%s length %d matches no SCRAM hash", name, len(digest))
+		mech = "unknown hash"
+	}
+	d.field(key, "%s: %s (%d bytes, %s)", name, value, len(digest), mech)
+}
+
+func (d *messageDecoder) saslname(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch {
+		case strings.HasPrefix(name[i:], "=2C"):
+			b.WriteByte(',')
+			i += 2
+		case strings.HasPrefix(name[i:], "=3D"):
+			b.WriteByte('=')
+			i += 2
+		case name[i] == '=':
+			d.problem("name %q contains an invalid escape at byte %d", name, i)
+			b.WriteByte('=')
+		default:
+			b.WriteByte(name[i])
+		}
+	}
+	return b.String()
+}
+
+func (d *messageDecoder) field(key, format string, args ...any) {
+	fmt.Fprintf(d.w, "  %s  %s\n", key, fmt.Sprintf(format, args...))
+}
+
+func (d *messageDecoder) problem(format string, args ...any) {
+	d.problems++
+	fmt.Fprintf(d.w, "  !  %s\n", fmt.Sprintf(format, args...))
+}
+
+// splitCBind separates the echoed gs2 header from the channel binding data
+// in a decoded c= attribute.
+func splitCBind(cbind []byte) (string, []byte) {
+	commas := 0
+	for i, b := range cbind {
+		if b == ',' {
+			commas++
+			if commas == 2 {
+				return string(cbind[:i+1]), cbind[i+1:]
+			}
+		}
+	}
+	return string(cbind), nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// commandDoc describes a subcommand for docs man. Flags returns the same
+// FlagSet the command parses, so the pages cannot drift from the code.
+type commandDoc struct {
+	Name    string
+	Summary string
+	Flags   func() *flag.FlagSet
+}
+
+var commandDocs = []commandDoc{
+	{"migrate", "Re-hash PostgreSQL roles still using md5 to SCRAM-SHA-256", func() *flag.FlagSet { return migrateFlags(&migrateConfig{}) }},
+	{"audit", "Report PostgreSQL roles with md5, missing or weak passwords", func() *flag.FlagSet { return auditFlags(&auditConfig{}) }},
+	{"decode", "Parse and explain SCRAM handshake messages", func() *flag.FlagSet { return decodeFlags(&decodeConfig{}) }},
+	{"lint", "Check stored verifiers against format and policy rules", func() *flag.FlagSet { return lintFlags(&lintConfig{}) }},
+	{"serve", "Run a mock SCRAM server for testing client implementations", func() *flag.FlagSet {
+		return serveFlags(&serveConfig{Users: userFlags{}, CredentialUsers: credentialUserFlags{}})
+	}},
+	{"prove", "Recompute ClientProof and ServerSignature from a captured exchange", func() *flag.FlagSet { return proveFlags(&proveConfig{}) }},
+	{"conformance", "Run SCRAM handshake scenarios against a live server", func() *flag.FlagSet { return conformanceFlags(&conformanceConfig{}) }},
+	{"bootstrap", "Write SQL, userlist and Kubernetes secrets for a manifest of users", func() *flag.FlagSet { return bootstrapFlags(&bootstrapConfig{}) }},
+	{"rotate", "Check a password against its verifier and re-hash it with a fresh salt", func() *flag.FlagSet { return rotateFlags(&rotateConfig{}) }},
+	{"verify", "Check a password against its verifier, offering to upgrade low iteration counts", func() *flag.FlagSet { return verifyFlags(&verifyConfig{}) }},
+	{"docs", "Generate documentation such as man pages", func() *flag.FlagSet { return docsFlags(&docsConfig{}) }},
+	{"self-update", "Replace this binary with the latest signed release", func() *flag.FlagSet { return selfUpdateFlags(&selfUpdateConfig{}) }},
+	{"version", "Show version and FIPS 140-3 status", func() *flag.FlagSet { return flag.NewFlagSet("version", flag.ExitOnError) }},
+}
+
+type docsConfig struct {
+	OutDir string
+}
+
+func docsFlags(config *docsConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	fs.StringVar(&config.OutDir, "out-dir", ".", "Directory to write the pages to")
+	return fs
+}
+
+func runDocs(args []string) int {
+	if len(args) == 0 || args[0] != "man" {
+		fmt.Fprintln(os.Stderr, msg.Text("Usage: scram-sha-256 docs man [-out-dir DIR]"))
+		return exitError
+	}
+	config := docsConfig{}
+
+	fs := docsFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args[1:])
+
+	pages := map[string][]byte{"scram-sha-256.1": mainManPage()}
+	for _, cmd := range commandDocs {
+		pages["scram-sha-256-"+cmd.Name+".1"] = commandManPage(cmd)
+	}
+	for _, name := range sortedKeys(pages) {
+		path := filepath.Join(config.OutDir, name)
+		if err := os.WriteFile(path, pages[name], 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitError
+		}
+		fmt.Fprintln(os.Stderr, path)
+	}
+	return 0
+}
+
+func mainManPage() []byte {
+	fs := flag.NewFlagSet("scram-sha-256", flag.ContinueOnError)
+	defineFlags(fs, &Config{Iterations: defaultIterations})
+
+	var b bytes.Buffer
+	manHeader(&b, "scram-sha-256", "generate and manage SCRAM password verifiers")
+	b.WriteString(".SH SYNOPSIS\n.B scram\\-sha\\-256\n[\\fIOPTIONS\\fR]\n.br\n.B scram\\-sha\\-256\n\\fICOMMAND\\fR [\\fIOPTIONS\\fR]\n")
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString(roffEscape("Reads a password and prints its SCRAM-SHA-256 verifier, as stored by PostgreSQL, or the credentials of another supported format.") + "\n")
+	b.WriteString(".SH COMMANDS\n")
+	for _, cmd := range commandDocs {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s. See\n.BR scram\\-sha\\-256\\-%s (1).\n", cmd.Name, roffEscape(cmd.Summary), cmd.Name)
+	}
+	manOptions(&b, fs)
+	b.WriteString(".SH EXIT STATUS\n")
+	for _, status := range []struct {
+		code int
+		text string
+	}{
+		{0, "Success."},
+		{exitError, "An error, such as invalid options or an unreadable file."},
+		{exitMismatch, "Passwords entered with -confirm, or given to rotate, did not match."},
+		{exitPolicy, "The password or iteration count was rejected by policy."},
+	} {
+		fmt.Fprintf(&b, ".TP\n.B %d\n%s\n", status.code, roffEscape(status.text))
+	}
+	b.WriteString(".SH ENVIRONMENT\n")
+	for _, env := range []struct{ name, text string }{
+		{"DATABASE_URL", "Default connection URL for -apply, migrate and audit."},
+		{"CREDENTIALS_DIRECTORY", "Directory of systemd credentials read by -credential."},
+		{"LC_ALL, LC_MESSAGES, LANG", "Language for prompts, help and messages."},
+	} {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", env.name, roffEscape(env.text))
+	}
+	b.WriteString(".SH SEE ALSO\n")
+	for i, cmd := range commandDocs {
+		sep := ","
+		if i == len(commandDocs)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(&b, ".BR scram\\-sha\\-256\\-%s (1)%s\n", cmd.Name, sep)
+	}
+	return b.Bytes()
+}
+
+func commandManPage(cmd commandDoc) []byte {
+	var b bytes.Buffer
+	manHeader(&b, "scram-sha-256-"+cmd.Name, strings.ToLower(cmd.Summary[:1])+cmd.Summary[1:])
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n.B scram\\-sha\\-256 %s\n[\\fIOPTIONS\\fR]\n", cmd.Name)
+	b.WriteString(".SH DESCRIPTION\n" + roffEscape(cmd.Summary) + ".\n")
+	manOptions(&b, cmd.Flags())
+	b.WriteString(".SH SEE ALSO\n.BR scram\\-sha\\-256 (1)\n")
+	return b.Bytes()
+}
+
+func manHeader(b *bytes.Buffer, name, description string) {
+	fmt.Fprintf(b, ".TH %s 1 \"\" \"scram-sha-256 %s\" \"User Commands\"\n", strings.ToUpper(roffEscape(name)), toolVersion())
+	fmt.Fprintf(b, ".SH NAME\n%s \\- %s\n", roffEscape(name), roffEscape(description))
+}
+
+// manOptions writes an OPTIONS section for fs. Aliases such as -i and
+// -iterations share their usage text and are listed together.
+func manOptions(b *bytes.Buffer, fs *flag.FlagSet) {
+	var usages []string
+	names := make(map[string][]string)
+	flags := make(map[string]*flag.Flag)
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := names[f.Usage]; !ok {
+			usages = append(usages, f.Usage)
+			flags[f.Usage] = f
+		}
+		names[f.Usage] = append(names[f.Usage], f.Name)
+	})
+	if len(usages) == 0 {
+		return
+	}
+
+	b.WriteString(".SH OPTIONS\n")
+	sort.Slice(usages, func(i, j int) bool {
+		return shortestName(names[usages[i]]) < shortestName(names[usages[j]])
+	})
+	for _, usage := range usages {
+		f := flags[usage]
+		aliases := names[usage]
+		sort.Slice(aliases, func(i, j int) bool { return len(aliases[i]) < len(aliases[j]) })
+		quoted := make([]string, len(aliases))
+		for i, name := range aliases {
+			quoted[i] = "\\fB\\-" + roffEscape(name) + "\\fR"
+		}
+		arg, text := flag.UnquoteUsage(f)
+		b.WriteString(".TP\n" + strings.Join(quoted, ", "))
+		if arg != "" {
+			b.WriteString(" \\fI" + roffEscape(arg) + "\\fR")
+		}
+		b.WriteString("\n" + roffEscape(text))
+		// Usage that already explains its default, such as one taken from
+		// the environment, must not show the value it had here.
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && !strings.Contains(text, "(default") {
+			b.WriteString(" (default: " + roffEscape(f.DefValue) + ")")
+		}
+		b.WriteString("\n")
+	}
+}
+
+func shortestName(names []string) string {
+	shortest := names[0]
+	for _, name := range names[1:] {
+		if len(name) < len(shortest) {
+			shortest = name
+		}
+	}
+	return shortest
+}
+
+// roffEscape makes s safe as roff text: backslashes and hyphens are
+// escaped, and a leading period or quote is not read as a request.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Echo modes for -echo.
+const (
+	echoNone       = "none"
+	echoMask       = "mask"
+	echoRevealLast = "reveal-last"
+)
+
+// revealDuration is how long -echo reveal-last shows the last character
+// typed before masking it.
+const revealDuration = time.Second
+
+// passwordPrompt replaces the "Password: " prompt and echoMode selects
+// what is shown while typing. They are set from -prompt and -echo.
+var (
+	passwordPrompt string
+	echoMode       = echoNone
+)
+
+func validateEcho(mode string) error {
+	switch mode {
+	case echoNone, echoMask, echoRevealLast:
+		return nil
+	}
+	return errorf("unknown -echo mode %q (want none, mask or reveal-last)", mode)
+}
+
+// promptText returns the -prompt text, ending in a space so that input
+// does not run into it.
+func promptText(prompt string) string {
+	if r, _ := utf8.DecodeLastRuneInString(prompt); !unicode.IsSpace(r) {
+		prompt += " "
+	}
+	return prompt
+}
+
+// readMaskedPassword reads a password on the terminal in raw mode,
+// echoing an asterisk for each character. With reveal the last character
+// is shown instead until the next key or for revealDuration. Input over
+// maxPasswordLength is a *PasswordTooLongError, as from the stdin reader.
+func readMaskedPassword(prompt string, reveal bool) (string, error) {
+	in, out := promptTerminal(os.Stderr)
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errorf("-echo %s requires an interactive terminal", echoMode)
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	t := &tui{r: bufio.NewReader(in), out: out}
+
+	// The reveal timer redraws from its own goroutine; keystrokes bump
+	// generation so a stale timer leaves a newer reveal alone.
+	var mu sync.Mutex
+	var password []rune
+	generation := 0
+	done := false
+	draw := func(showLast bool) {
+		masked := strings.Repeat("*", len(password))
+		if showLast && len(password) > 0 {
+			masked = masked[:len(masked)-1] + string(password[len(password)-1])
+		}
+		t.printf("\r\033[K%s%s", prompt, masked)
+	}
+
+	mu.Lock()
+	draw(false)
+	mu.Unlock()
+	for {
+		k, r, err := t.readKey()
+
+		mu.Lock()
+		generation++
+		if err != nil || k == keyAbort || k == keyEnter {
+			done = true
+			draw(false)
+			t.printf("\r\n")
+			mu.Unlock()
+			if err != nil {
+				return "", err
+			}
+			if k == keyAbort {
+				return "", errTUIAborted
+			}
+			if len(string(password)) > maxPasswordLength {
+				return "", &PasswordTooLongError{Max: maxPasswordLength}
+			}
+			return string(password), nil
+		}
+
+		switch k {
+		case keyRune:
+			password = append(password, r)
+		case keyBackspace:
+			if len(password) > 0 {
+				password = password[:len(password)-1]
+			}
+		}
+		showLast := reveal && k == keyRune
+		draw(showLast)
+		if showLast {
+			current := generation
+			time.AfterFunc(revealDuration, func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if !done && generation == current {
+					draw(false)
+				}
+			})
+		}
+		mu.Unlock()
+	}
+}
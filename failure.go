@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// jsonErrors is set when the output is JSON, with -format json or
+// -formats, so that failures and warnings on stderr are JSON too and
+// orchestration tools can tell exactly what failed without parsing text.
+var jsonErrors bool
+
+// Codes of the failures reported as JSON.
+const (
+	codeInvalidOptions   = "invalid_options"
+	codeInvalidPassword  = "invalid_password"
+	codePasswordMismatch = "password_mismatch"
+	codeInvalidRecord    = "invalid_record"
+	codeReadError        = "read_error"
+	codeOutputError      = "output_error"
+	codeError            = "error"
+	codeWarning          = "password_warning"
+)
+
+// failure is a failure or warning as reported with JSON errors. Field is
+// the option or record field at fault, when there is one, and Record the
+// 1-based index of the -batch record.
+type failure struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+	Record  int    `json:"record,omitempty"`
+}
+
+// fail reports a failure and exits with exit. text is what is printed
+// normally; with JSON errors f is printed instead as {"error": f}, with
+// text as its message unless f has one.
+func fail(exit int, f failure, text string) {
+	reportFailure(f, text)
+	os.Exit(exit)
+}
+
+// reportFailure reports a failure like fail, for callers that return an
+// exit code instead of exiting.
+func reportFailure(f failure, text string) {
+	report("error", f, text)
+}
+
+// failOptions reports invalid options and exits. The field is the option
+// err starts with, as in "-role is required".
+func failOptions(err error) {
+	fail(exitError, failure{Code: codeInvalidOptions, Message: err.Error(), Field: optionField(err.Error())},
+		msg.Sprintf("Invalid options: %v\n", err))
+}
+
+// warn reports a password warning, printed as {"warning": f} with JSON
+// errors.
+func warn(f failure, text string) {
+	f.Code = codeWarning
+	report("warning", f, text)
+}
+
+func report(kind string, f failure, text string) {
+	if f.Message == "" {
+		f.Message = strings.TrimSpace(text)
+	}
+	logFailure(kind, f)
+	if !jsonErrors {
+		fmt.Fprint(os.Stderr, text)
+		return
+	}
+	line, _ := json.Marshal(map[string]failure{kind: f})
+	fmt.Fprintf(os.Stderr, "%s\n", line)
+}
+
+// logFailure records a failure or warning in the audit log.
+func logFailure(kind string, f failure) {
+	level := slog.LevelError
+	if kind == "warning" {
+		level = slog.LevelWarn
+	}
+	attrs := []slog.Attr{slog.String("code", f.Code)}
+	if f.Field != "" {
+		attrs = append(attrs, slog.String("field", f.Field))
+	}
+	if f.Record > 0 {
+		attrs = append(attrs, slog.Int("record", f.Record))
+	}
+	auditLog.LogAttrs(context.Background(), level, f.Message, attrs...)
+}
+
+// fieldError is an error in one field of a -json-records record.
+type fieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *fieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.Err
+}
+
+// optionField returns the option a message starts with, without its
+// dash, or "" when it does not start with one.
+func optionField(message string) string {
+	if !strings.HasPrefix(message, "-") {
+		return ""
+	}
+	name, _, _ := strings.Cut(message[1:], " ")
+	return strings.TrimRight(name, ":,")
+}
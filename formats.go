@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// rabbitMQSaltLength is the salt size used by RabbitMQ's
+// rabbit_password_hashing_sha256 module.
+const rabbitMQSaltLength = 4
+
+// fieldSaltLength matches the salt size of the verifier format.
+const fieldSaltLength = 16
+
+// generateRabbitMQ returns a RabbitMQ password_hash value: base64 of a
+// 4-byte salt followed by SHA-256(salt || password), as accepted by the
+// definitions file and the HTTP API.
+func generateRabbitMQ(password string) (string, error) {
+	salt := make([]byte, rabbitMQSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(salt, password...))
+	return base64.StdEncoding.EncodeToString(append(salt, sum[:]...)), nil
+}
+
+// generateEjabberd returns the SCRAM-SHA-256 columns of ejabberd's SQL
+// users table. ejabberd marks non-SHA-1 credentials by prefixing the salt
+// with the hash name.
+func generateEjabberd(password string, iterations int) ([]string, error) {
+	salt := make([]byte, fieldSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	creds, err := scram.NewStoredCredentials(crypto.SHA256, password, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	return ejabberdFields(creds), nil
+}
+
+// ejabberdFields returns the columns of ejabberd's users table for creds.
+func ejabberdFields(creds scram.StoredCredentials) []string {
+	return []string{
+		"password=" + base64.StdEncoding.EncodeToString(creds.StoredKey),
+		"serverkey=" + base64.StdEncoding.EncodeToString(creds.ServerKey),
+		"salt=sha256:" + base64.StdEncoding.EncodeToString(creds.Salt),
+		fmt.Sprintf("iterationcount=%d", creds.Iterations),
+	}
+}
+
+// generateDovecot returns a password in Dovecot's SCRAM-SHA-256 scheme:
+// iterations, base64 salt and hex stored and server keys.
+func generateDovecot(password string, iterations int) (string, error) {
+	salt := make([]byte, fieldSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	creds, err := scram.NewStoredCredentials(crypto.SHA256, password, salt, iterations)
+	if err != nil {
+		return "", err
+	}
+	return dovecotPassword(creds), nil
+}
+
+// dovecotPassword formats creds in Dovecot's SCRAM-SHA-256 scheme.
+func dovecotPassword(creds scram.StoredCredentials) string {
+	return fmt.Sprintf("{SCRAM-SHA-256}%d,%s,%s,%s",
+		creds.Iterations,
+		base64.StdEncoding.EncodeToString(creds.Salt),
+		hex.EncodeToString(creds.StoredKey),
+		hex.EncodeToString(creds.ServerKey))
+}
+
+// generateProsody returns an account record for Prosody's
+// internal_hashed storage (data/<host>/accounts/<user>.dat), which holds
+// SCRAM-SHA-1 keys in hex. Like Prosody, it uses a printable salt so the
+// record needs no escaping.
+func generateProsody(password string, iterations int) ([]string, error) {
+	creds, err := prosodyCredentials(password, iterations)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		"return {",
+		fmt.Sprintf("\t[\"iteration_count\"] = %d;", creds.Iterations),
+		fmt.Sprintf("\t[\"salt\"] = %q;", creds.Salt),
+		fmt.Sprintf("\t[\"stored_key\"] = %q;", hex.EncodeToString(creds.StoredKey)),
+		fmt.Sprintf("\t[\"server_key\"] = %q;", hex.EncodeToString(creds.ServerKey)),
+		"};",
+	}, nil
+}
+
+// prosodyCredentials derives SCRAM-SHA-1 credentials with a fresh
+// printable salt.
+func prosodyCredentials(password string, iterations int) (scram.StoredCredentials, error) {
+	raw := make([]byte, fieldSaltLength)
+	if _, err := rand.Read(raw); err != nil {
+		return scram.StoredCredentials{}, err
+	}
+	salt := base64.RawURLEncoding.EncodeToString(raw)
+	return scram.NewStoredCredentials(crypto.SHA1, password, []byte(salt), iterations)
+}
+
+// verifierDocument is the SCRAM-SHA-256 verifier as -format yaml and json
+// print it: the verifier, its components, -role when given, and when and
+// by which version it was generated.
+type verifierDocument struct {
+	Verifier   string `json:"verifier"`
+	Mechanism  string `json:"mechanism"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+	StoredKey  string `json:"stored_key"`
+	ServerKey  string `json:"server_key"`
+	Role       string `json:"role,omitempty"`
+	Metadata   struct {
+		GeneratedAt string `json:"generated_at"`
+		ToolVersion string `json:"tool_version"`
+	} `json:"metadata"`
+}
+
+// newVerifierDocument derives the verifier for password and describes it.
+// The salt is random unless salt is non-nil.
+func newVerifierDocument(ctx context.Context, config Config, password string, salt []byte) (verifierDocument, error) {
+	var doc verifierDocument
+	if salt == nil {
+		salt = make([]byte, fieldSaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return doc, err
+		}
+	}
+	creds, err := scram.NewStoredCredentialsContext(ctx, crypto.SHA256, password, salt, config.Iterations)
+	if err != nil {
+		return doc, err
+	}
+
+	b64 := base64.StdEncoding.EncodeToString
+	doc.Verifier = scram.EncodeVerifier(crypto.SHA256, creds)
+	doc.Mechanism = "SCRAM-SHA-256"
+	doc.Iterations = creds.Iterations
+	doc.Salt = b64(creds.Salt)
+	doc.StoredKey = b64(creds.StoredKey)
+	doc.ServerKey = b64(creds.ServerKey)
+	doc.Role = config.Role
+	doc.Metadata.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	doc.Metadata.ToolVersion = toolVersion()
+	return doc, nil
+}
+
+// generateYAML returns the verifier document for Ansible vars or Helm
+// values. It starts with "---" so that -batch output is a valid stream of
+// documents.
+func generateYAML(ctx context.Context, config Config, password string, salt []byte) (string, error) {
+	doc, err := newVerifierDocument(ctx, config, password, salt)
+	if err != nil {
+		return "", err
+	}
+
+	// Strings are double-quoted, which Go quoting produces validly for
+	// YAML, so no value is read back as another type.
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "verifier: %s\n", strconv.Quote(doc.Verifier))
+	fmt.Fprintf(&b, "mechanism: %s\n", doc.Mechanism)
+	fmt.Fprintf(&b, "iterations: %d\n", doc.Iterations)
+	fmt.Fprintf(&b, "salt: %s\n", strconv.Quote(doc.Salt))
+	fmt.Fprintf(&b, "stored_key: %s\n", strconv.Quote(doc.StoredKey))
+	fmt.Fprintf(&b, "server_key: %s\n", strconv.Quote(doc.ServerKey))
+	if doc.Role != "" {
+		fmt.Fprintf(&b, "role: %s\n", strconv.Quote(doc.Role))
+	}
+	b.WriteString("metadata:\n")
+	fmt.Fprintf(&b, "  generated_at: %s\n", strconv.Quote(doc.Metadata.GeneratedAt))
+	fmt.Fprintf(&b, "  tool_version: %s", strconv.Quote(doc.Metadata.ToolVersion))
+	return b.String(), nil
+}
+
+// generateJSON returns the verifier document as JSON, indented, or on
+// one line with -batch so that the output is JSON Lines.
+func generateJSON(ctx context.Context, config Config, password string, salt []byte) (string, error) {
+	doc, err := newVerifierDocument(ctx, config, password, salt)
+	if err != nil {
+		return "", err
+	}
+	var data []byte
+	if config.Batch {
+		data, err = json.Marshal(doc)
+	} else {
+		data, err = json.MarshalIndent(doc, "", "  ")
+	}
+	return string(data), err
+}
@@ -1,12 +1,11 @@
 module github.com/SonOfBytes/scram-sha-256
 
-go 1.23.0
+go 1.24.0
 
 toolchain go1.24.3
 
 require (
 	golang.org/x/crypto v0.38.0
+	golang.org/x/sys v0.33.0
 	golang.org/x/term v0.32.0
 )
-
-require golang.org/x/sys v0.33.0 // indirect
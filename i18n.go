@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// catalogs maps a language to its translations, keyed by the English
+// format string as in golang.org/x/text/message catalogs. Messages missing
+// from a catalog are printed in English.
+var catalogs = map[string]map[string]string{
+	"de": catalogDE,
+	"es": catalogES,
+	"fr": catalogFR,
+}
+
+// printer localizes prompts, help, and the errors and status messages
+// printed to stderr.
+type printer struct {
+	messages map[string]string
+}
+
+// msg is the printer for the selected language; English until
+// setLanguage is called.
+var msg printer
+
+func (p printer) Text(s string) string {
+	if t, ok := p.messages[s]; ok {
+		return t
+	}
+	return s
+}
+
+func (p printer) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(p.Text(format), args...)
+}
+
+func (p printer) Printf(format string, args ...any) {
+	fmt.Print(p.Sprintf(format, args...))
+}
+
+func (p printer) Println(s string) {
+	fmt.Println(p.Text(s))
+}
+
+func (p printer) Eprintf(format string, args ...any) {
+	fmt.Fprint(os.Stderr, p.Sprintf(format, args...))
+}
+
+// Error localizes err when its whole message is in the catalog, which
+// covers fixed-text errors such as scram.ErrEmptyPassword.
+func (p printer) Error(err error) string {
+	return p.Text(err.Error())
+}
+
+// errorf is fmt.Errorf with the format localized, so that errors reaching
+// the user are printed in the selected language.
+func errorf(format string, args ...any) error {
+	return fmt.Errorf(msg.Text(format), args...)
+}
+
+// localizeFlags translates the usage of each flag in fs and the heading of
+// its -h output. The xxxFlags functions stay in English for the man pages,
+// so subcommands call this after building their flag set.
+func localizeFlags(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		f.Usage = msg.Text(f.Usage)
+	})
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), msg.Sprintf("Usage of %s:\n", fs.Name()))
+		fs.PrintDefaults()
+	}
+}
+
+// languageFromEnv returns the language of the message locale, taken from
+// $LC_ALL, $LC_MESSAGES or $LANG in that order as POSIX does.
+func languageFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// setLanguage selects the catalog for lang, which may be a bare language
+// such as "de" or a locale such as "de_DE.UTF-8". English, "C" and
+// "POSIX" select the built-in English messages.
+func setLanguage(lang string) error {
+	base, _, _ := strings.Cut(lang, ".")
+	base, _, _ = strings.Cut(base, "@")
+	base = strings.ToLower(base)
+	if i := strings.IndexAny(base, "_-"); i >= 0 {
+		base = base[:i]
+	}
+
+	switch base {
+	case "", "en", "c", "posix":
+		msg = printer{}
+		return nil
+	}
+	messages, ok := catalogs[base]
+	if !ok {
+		return errorf("unsupported language %q (available: %s)", lang, strings.Join(languages(), ", "))
+	}
+	msg = printer{messages: messages}
+	return nil
+}
+
+func languages() []string {
+	names := []string{"en"}
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
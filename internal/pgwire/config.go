@@ -0,0 +1,95 @@
+package pgwire
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl/pgauth"
+)
+
+// Config describes how to reach and authenticate to a server.
+type Config struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+	// SSLMode is one of disable, prefer, require or verify-full.
+	SSLMode string
+	// Auth, when set, adjusts the SASL authenticator before the exchange
+	// starts, for example to limit the mechanisms it will use.
+	Auth func(*pgauth.Authenticator)
+}
+
+// Addr returns the host:port to dial.
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
+// ParseURL builds a Config from a postgres:// connection URL. Settings the
+// URL leaves out fall back to the libpq environment variables (PGHOST,
+// PGPORT, PGUSER, PGPASSWORD, PGDATABASE, PGSSLMODE) and then to libpq's
+// defaults. An empty URL uses the environment alone.
+func ParseURL(rawURL string) (Config, error) {
+	cfg := Config{
+		Host:     envOr("PGHOST", "localhost"),
+		Port:     envOr("PGPORT", "5432"),
+		User:     envOr("PGUSER", os.Getenv("USER")),
+		Password: os.Getenv("PGPASSWORD"),
+		Database: os.Getenv("PGDATABASE"),
+		SSLMode:  envOr("PGSSLMODE", "prefer"),
+	}
+	if rawURL == "" {
+		return cfg, cfg.validate()
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid connection URL: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return Config{}, fmt.Errorf("unsupported connection URL scheme %q", u.Scheme)
+	}
+
+	if host := u.Hostname(); host != "" {
+		cfg.Host = host
+	}
+	if port := u.Port(); port != "" {
+		cfg.Port = port
+	}
+	if u.User != nil {
+		cfg.User = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			cfg.Password = password
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		cfg.Database = db
+	}
+	if mode := u.Query().Get("sslmode"); mode != "" {
+		cfg.SSLMode = mode
+	}
+	return cfg, cfg.validate()
+}
+
+func (c Config) validate() error {
+	switch c.SSLMode {
+	case "disable", "prefer", "require", "verify-full":
+	default:
+		return fmt.Errorf("unsupported sslmode %q", c.SSLMode)
+	}
+	if c.User == "" {
+		return fmt.Errorf("no user given")
+	}
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,331 @@
+// Package pgwire is a minimal PostgreSQL frontend/backend protocol client:
+// just enough of startup, authentication and the simple query protocol for
+// the administrative subcommands of the CLI.
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl/pgauth"
+	_ "github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+const (
+	protocolVersion = 196608
+	sslRequestCode  = 80877103
+)
+
+// Error is an ErrorResponse sent by the server.
+type Error struct {
+	Severity string
+	Code     string
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (SQLSTATE %s)", e.Severity, e.Message, e.Code)
+}
+
+// Conn is an established, authenticated connection.
+type Conn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tls  *tls.ConnectionState
+
+	// Mechanism is the SASL mechanism used to authenticate, if any.
+	Mechanism string
+}
+
+// Connect dials the server, negotiates TLS according to cfg.SSLMode and
+// authenticates.
+func Connect(ctx context.Context, cfg Config) (*Conn, error) {
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", cfg.Addr())
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Conn{conn: nc, r: bufio.NewReader(nc)}
+	if deadline, ok := ctx.Deadline(); ok {
+		nc.SetDeadline(deadline)
+	}
+
+	if err := c.startup(cfg); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	// ctx bounds connecting only; interactive callers may keep the
+	// connection open long after it expires.
+	c.conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// TLS returns the TLS connection state, or nil for a plaintext connection.
+func (c *Conn) TLS() *tls.ConnectionState {
+	return c.tls
+}
+
+// Close sends Terminate and closes the connection.
+func (c *Conn) Close() error {
+	c.writeMessage('X', nil)
+	return c.conn.Close()
+}
+
+func (c *Conn) startup(cfg Config) error {
+	if cfg.SSLMode != "disable" {
+		if err := c.negotiateTLS(cfg); err != nil {
+			return err
+		}
+	}
+
+	var params []byte
+	params = appendCString(appendCString(params, "user"), cfg.User)
+	if cfg.Database != "" {
+		params = appendCString(appendCString(params, "database"), cfg.Database)
+	}
+	params = append(params, 0)
+
+	msg := binary.BigEndian.AppendUint32(nil, uint32(8+len(params)))
+	msg = binary.BigEndian.AppendUint32(msg, protocolVersion)
+	if _, err := c.conn.Write(append(msg, params...)); err != nil {
+		return err
+	}
+
+	if err := c.authenticate(cfg); err != nil {
+		return err
+	}
+
+	for {
+		typ, body, err := c.readMessage()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case 'Z':
+			return nil
+		case 'E':
+			return parseError(body)
+		}
+	}
+}
+
+func (c *Conn) negotiateTLS(cfg Config) error {
+	req := binary.BigEndian.AppendUint32(nil, 8)
+	req = binary.BigEndian.AppendUint32(req, sslRequestCode)
+	if _, err := c.conn.Write(req); err != nil {
+		return err
+	}
+
+	resp, err := c.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if resp != 'S' {
+		if cfg.SSLMode == "prefer" {
+			return nil
+		}
+		return fmt.Errorf("server does not support TLS")
+	}
+
+	tlsCfg := &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: cfg.SSLMode != "verify-full"}
+	tc := tls.Client(c.conn, tlsCfg)
+	if err := tc.Handshake(); err != nil {
+		return fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	state := tc.ConnectionState()
+	c.conn, c.r, c.tls = tc, bufio.NewReader(tc), &state
+	return nil
+}
+
+func (c *Conn) authenticate(cfg Config) error {
+	auth := &pgauth.Authenticator{Password: cfg.Password, TLS: c.tls}
+	if cfg.Auth != nil {
+		cfg.Auth(auth)
+	}
+	for {
+		typ, body, err := c.readMessage()
+		if err != nil {
+			return err
+		}
+		if typ == 'E' {
+			return parseError(body)
+		}
+		if typ != 'R' || len(body) < 4 {
+			return fmt.Errorf("unexpected message %q during authentication", typ)
+		}
+
+		code, data := binary.BigEndian.Uint32(body), body[4:]
+		switch code {
+		case 0: // AuthenticationOk
+			if auth.Mechanism() != "" && !auth.Done() {
+				return fmt.Errorf("server accepted authentication before proving its identity")
+			}
+			c.Mechanism = auth.Mechanism()
+			return nil
+		case 3: // AuthenticationCleartextPassword
+			err = c.writeMessage('p', appendCString(nil, cfg.Password))
+		case 5: // AuthenticationMD5Password
+			if len(data) != 4 {
+				return fmt.Errorf("malformed md5 authentication request")
+			}
+			err = c.writeMessage('p', appendCString(nil, md5Password(cfg.User, cfg.Password, data)))
+		case 10: // AuthenticationSASL
+			var mech string
+			var initial []byte
+			if mech, initial, err = auth.Start(pgauth.ParseMechanisms(data)); err == nil {
+				err = c.writeMessage('p', pgauth.InitialResponse(mech, initial))
+			}
+		case 11: // AuthenticationSASLContinue
+			var resp []byte
+			if resp, err = auth.Continue(data); err == nil {
+				err = c.writeMessage('p', resp)
+			}
+		case 12: // AuthenticationSASLFinal
+			err = auth.Final(data)
+		default:
+			return fmt.Errorf("unsupported authentication method %d", code)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Exec runs statements using the simple query protocol, discarding rows.
+func (c *Conn) Exec(query string) error {
+	_, err := c.Query(query)
+	return err
+}
+
+// Query runs query using the simple query protocol and returns the rows of
+// the last result set. NULL values are returned as nil.
+func (c *Conn) Query(query string) ([][]*string, error) {
+	if err := c.writeMessage('Q', appendCString(nil, query)); err != nil {
+		return nil, err
+	}
+
+	var rows [][]*string
+	var queryErr error
+	for {
+		typ, body, err := c.readMessage()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case 'T':
+			rows = nil
+		case 'D':
+			row, err := parseDataRow(body)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		case 'E':
+			queryErr = parseError(body)
+		case 'Z':
+			return rows, queryErr
+		}
+	}
+}
+
+func (c *Conn) writeMessage(typ byte, body []byte) error {
+	msg := append([]byte{typ}, binary.BigEndian.AppendUint32(nil, uint32(4+len(body)))...)
+	_, err := c.conn.Write(append(msg, body...))
+	return err
+}
+
+func (c *Conn) readMessage() (byte, []byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n < 4 || n > 1<<24 {
+		return 0, nil, fmt.Errorf("invalid message length %d", n)
+	}
+	body := make([]byte, n-4)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+	return hdr[0], body, nil
+}
+
+func parseDataRow(body []byte) ([]*string, error) {
+	if len(body) < 2 {
+		return nil, errors.New("malformed data row")
+	}
+	n := int(binary.BigEndian.Uint16(body))
+	body = body[2:]
+
+	row := make([]*string, n)
+	for i := range row {
+		if len(body) < 4 {
+			return nil, errors.New("malformed data row")
+		}
+		size := int32(binary.BigEndian.Uint32(body))
+		body = body[4:]
+		if size < 0 {
+			continue
+		}
+		if int(size) > len(body) {
+			return nil, errors.New("malformed data row")
+		}
+		v := string(body[:size])
+		row[i] = &v
+		body = body[size:]
+	}
+	return row, nil
+}
+
+func parseError(body []byte) error {
+	e := &Error{}
+	for _, field := range strings.Split(string(body), "\x00") {
+		if field == "" {
+			continue
+		}
+		switch field[0] {
+		case 'S':
+			e.Severity = field[1:]
+		case 'C':
+			e.Code = field[1:]
+		case 'M':
+			e.Message = field[1:]
+		}
+	}
+	return e
+}
+
+func md5Password(user, password string, salt []byte) string {
+	inner := md5.Sum([]byte(password + user))
+	outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), salt...))
+	return "md5" + hex.EncodeToString(outer[:])
+}
+
+func appendCString(b []byte, s string) []byte {
+	return append(append(b, s...), 0)
+}
+
+// QuoteIdentifier quotes name for use as an SQL identifier.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteLiteral quotes s for use as an SQL string literal.
+func QuoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, `'`, `''`)
+	if strings.Contains(s, `\`) {
+		return `E'` + strings.ReplaceAll(s, `\`, `\\`) + `'`
+	}
+	return `'` + s + `'`
+}
@@ -0,0 +1,84 @@
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeServer accepts one connection, trusts the startup message and
+// answers every simple query with an empty result.
+func fakeServer(t *testing.T) net.Listener {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+
+		var n uint32
+		if binary.Read(r, binary.BigEndian, &n) != nil {
+			return
+		}
+		if _, err := io.CopyN(io.Discard, r, int64(n-4)); err != nil {
+			return
+		}
+		send := func(typ byte, body []byte) {
+			msg := append([]byte{typ}, binary.BigEndian.AppendUint32(nil, uint32(4+len(body)))...)
+			conn.Write(append(msg, body...))
+		}
+		send('R', []byte{0, 0, 0, 0}) // AuthenticationOk
+		send('Z', []byte{'I'})
+
+		for {
+			typ, err := r.ReadByte()
+			if err != nil {
+				return
+			}
+			if binary.Read(r, binary.BigEndian, &n) != nil {
+				return
+			}
+			if _, err := io.CopyN(io.Discard, r, int64(n-4)); err != nil {
+				return
+			}
+			if typ == 'Q' {
+				send('C', appendCString(nil, "SELECT 0"))
+				send('Z', []byte{'I'})
+			}
+		}
+	}()
+	return l
+}
+
+// TestConnectDeadlineDoesNotOutliveConnect runs a statement after the
+// connect context has expired, as an interactive migrate does.
+func TestConnectDeadlineDoesNotOutliveConnect(t *testing.T) {
+	l := fakeServer(t)
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	conn, err := Connect(ctx, Config{Host: host, Port: port, User: "postgres", SSLMode: "disable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+	if err := conn.Exec("SELECT 1"); err != nil {
+		t.Fatalf("Exec after the connect timeout: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// readPasswordFromKeychain reads a generic password item from the macOS
+// login keychain by service name.
+func readPasswordFromKeychain(item string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", item, "-w").Output()
+	if err != nil {
+		return "", errorf("keychain item %q not found: %w", item, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
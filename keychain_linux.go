@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// readPasswordFromKeychain looks item up with libsecret (by its service
+// attribute) and falls back to KWallet's default wallet.
+func readPasswordFromKeychain(item string) (string, error) {
+	if _, err := exec.LookPath("secret-tool"); err == nil {
+		out, err := exec.Command("secret-tool", "lookup", "service", item).Output()
+		if err == nil && len(out) > 0 {
+			return strings.TrimRight(string(out), "\n"), nil
+		}
+	}
+
+	if _, err := exec.LookPath("kwallet-query"); err == nil {
+		out, err := exec.Command("kwallet-query", "-r", item, "kdewallet").Output()
+		if err == nil && len(out) > 0 {
+			return strings.TrimRight(string(out), "\n"), nil
+		}
+	}
+
+	return "", errorf("keychain item %q not found in libsecret or KWallet", item)
+}
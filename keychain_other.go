@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package main
+
+func readPasswordFromKeychain(item string) (string, error) {
+	return "", errorf("keychain access is not supported on this platform")
+}
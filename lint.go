@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+type lintConfig struct {
+	File          string
+	MinIterations int
+	MaxIterations int
+	MinSaltLength int
+	Mechanisms    string
+}
+
+// lintEntry is one verifier to check, labelled with the role name when
+// the source provides one.
+type lintEntry struct {
+	label    string
+	verifier string
+}
+
+var (
+	verifierPattern = regexp.MustCompile(`SCRAM-SHA-[0-9A-Z-]+\$[^\s|,"']*`)
+	md5Pattern      = regexp.MustCompile(`\bmd5[0-9a-f]{32}\b`)
+)
+
+func lintFlags(config *lintConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.StringVar(&config.File, "file", "", "Read verifiers from this file (- for stdin), e.g. a pg_authid dump")
+	fs.IntVar(&config.MinIterations, "min-iterations", defaultIterations, "Smallest acceptable iteration count")
+	fs.IntVar(&config.MaxIterations, "max-iterations", 0, "Largest acceptable iteration count (0 for no limit)")
+	fs.IntVar(&config.MinSaltLength, "min-salt-length", 16, "Smallest acceptable salt length in bytes")
+	fs.StringVar(&config.Mechanisms, "mechanisms", "SCRAM-SHA-1,SCRAM-SHA-256,SCRAM-SHA-512", "Comma-separated list of acceptable mechanisms")
+	return fs
+}
+
+func runLint(args []string) int {
+	config := lintConfig{}
+
+	fs := lintFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	var entries []lintEntry
+	for i, arg := range fs.Args() {
+		entries = append(entries, lintEntry{label: fmt.Sprintf("argument %d", i+1), verifier: arg})
+	}
+
+	if config.File != "" || len(entries) == 0 {
+		var r io.Reader = os.Stdin
+		if config.File != "" && config.File != "-" {
+			f, err := os.Open(config.File)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening verifier file: %v\n", err)
+				return 1
+			}
+			defer f.Close()
+			r = f
+		}
+		fileEntries, err := readLintEntries(r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading verifiers: %v\n", err)
+			return 1
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no verifiers found")
+		return 1
+	}
+
+	allowed := make(map[string]bool)
+	for _, name := range strings.Split(config.Mechanisms, ",") {
+		allowed[strings.TrimSpace(name)] = true
+	}
+
+	problems := 0
+	for _, entry := range entries {
+		found := lintVerifier(entry.verifier, config, allowed)
+		if len(found) == 0 {
+			fmt.Printf("%s: OK\n", entry.label)
+			continue
+		}
+		for _, problem := range found {
+			fmt.Printf("%s: %s\n", entry.label, problem)
+		}
+		problems += len(found)
+	}
+
+	if problems > 0 {
+		fmt.Fprintf(os.Stderr, "%d problems found\n", problems)
+		return 1
+	}
+	return 0
+}
+
+// readLintEntries extracts verifiers from free-form lines such as psql
+// output, CSV or "role:verifier" lists. Text before the verifier is used
+// as its label; lines holding neither a SCRAM verifier nor an md5 hash,
+// such as headers, are skipped.
+func readLintEntries(r io.Reader) ([]lintEntry, error) {
+	var entries []lintEntry
+	scanner := bufio.NewScanner(io.LimitReader(r, maxDocumentSize))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		loc := verifierPattern.FindStringIndex(text)
+		if loc == nil {
+			loc = md5Pattern.FindStringIndex(text)
+		}
+		if loc == nil {
+			continue
+		}
+
+		label := strings.Trim(text[:loc[0]], " \t|,:;\"'")
+		if label == "" {
+			label = fmt.Sprintf("line %d", line)
+		}
+		entries = append(entries, lintEntry{label: label, verifier: text[loc[0]:loc[1]]})
+	}
+	return entries, scanner.Err()
+}
+
+// lintVerifier checks every field of verifier rather than stopping at the
+// first error, so one run reports everything that needs fixing.
+func lintVerifier(verifier string, config lintConfig, allowed map[string]bool) []string {
+	if md5Pattern.MatchString(verifier) {
+		return []string{"md5 hash, not a SCRAM verifier"}
+	}
+
+	var problems []string
+	name, rest, ok := strings.Cut(verifier, "$")
+	if !ok {
+		return []string{"not in MECHANISM$iterations:salt$storedkey:serverkey form"}
+	}
+	params, keys, ok1 := strings.Cut(rest, "$")
+	itersStr, saltB64, ok2 := strings.Cut(params, ":")
+	storedB64, serverB64, ok3 := strings.Cut(keys, ":")
+	if !ok1 || !ok2 || !ok3 {
+		return []string{"not in MECHANISM$iterations:salt$storedkey:serverkey form"}
+	}
+
+	h, ok := scram.MechanismHash(name)
+	switch {
+	case !ok:
+		problems = append(problems, fmt.Sprintf("unknown mechanism %q", name))
+	case strings.HasSuffix(name, "-PLUS"):
+		problems = append(problems, fmt.Sprintf("mechanism %q is a negotiation name; stored verifiers omit -PLUS", name))
+	case !allowed[name]:
+		problems = append(problems, fmt.Sprintf("mechanism %s is not allowed by policy", name))
+	}
+
+	iterations, err := strconv.Atoi(itersStr)
+	switch {
+	case err != nil || iterations < 1:
+		problems = append(problems, fmt.Sprintf("invalid iteration count %q", itersStr))
+	case iterations < config.MinIterations:
+		problems = append(problems, fmt.Sprintf("iteration count %d is below the minimum of %d", iterations, config.MinIterations))
+	case config.MaxIterations > 0 && iterations > config.MaxIterations:
+		problems = append(problems, fmt.Sprintf("iteration count %d is above the maximum of %d", iterations, config.MaxIterations))
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(saltB64)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("salt is not valid base64: %v", err))
+	} else if len(salt) < config.MinSaltLength {
+		problems = append(problems, fmt.Sprintf("salt is %d bytes, below the minimum of %d", len(salt), config.MinSaltLength))
+	}
+
+	for _, key := range []struct{ name, value string }{{"stored key", storedB64}, {"server key", serverB64}} {
+		raw, err := base64.StdEncoding.DecodeString(key.value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s is not valid base64: %v", key.name, err))
+		} else if ok && len(raw) != h.Size() {
+			problems = append(problems, fmt.Sprintf("%s is %d bytes, %s needs %d", key.name, len(raw), name, h.Size()))
+		}
+	}
+	return problems
+}
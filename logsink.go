@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// auditLog receives a record of every credential operation and failure,
+// with structured fields, when -log-sink is given. It discards them
+// otherwise.
+var auditLog = slog.New(slog.DiscardHandler)
+
+// logKeyCacheStats records how effective cache has been. It does nothing
+// when cache is nil.
+func logKeyCacheStats(cache *scram.KeyCache) {
+	if cache == nil {
+		return
+	}
+	stats := cache.Stats()
+	auditLog.Info("key cache", "hits", stats.Hits, "misses", stats.Misses, "entries", stats.Entries, "hit_rate", stats.HitRate())
+}
+
+// logIdentifier is the syslog APP-NAME and journal SYSLOG_IDENTIFIER.
+const logIdentifier = "scram-sha-256"
+
+// syslogFacility is authpriv, for messages about credentials.
+const syslogFacility = 10
+
+// syslogSDID names the structured data element holding the fields. 32473
+// is the private enterprise number RFC 5612 reserves for documentation,
+// as there is no registered one for this tool.
+const syslogSDID = "scram@32473"
+
+// logSinkSpec is a parsed -log-sink value.
+type logSinkSpec struct {
+	journal bool
+	network string // "" for the local syslog daemon
+	addr    string
+}
+
+// parseLogSink parses a -log-sink value: journald, syslog for the local
+// syslog daemon, or syslog://host:port (UDP) or syslog+tcp://host:port
+// for a remote one.
+func parseLogSink(value string) (logSinkSpec, error) {
+	switch value {
+	case "journald":
+		return logSinkSpec{journal: true}, nil
+	case "syslog":
+		return logSinkSpec{}, nil
+	}
+	for scheme, network := range map[string]string{"syslog://": "udp", "syslog+udp://": "udp", "syslog+tcp://": "tcp"} {
+		if addr, ok := strings.CutPrefix(value, scheme); ok {
+			if _, _, err := net.SplitHostPort(addr); err != nil {
+				return logSinkSpec{}, errorf("-log-sink %s: %w", value, err)
+			}
+			return logSinkSpec{network: network, addr: addr}, nil
+		}
+	}
+	return logSinkSpec{}, errorf("-log-sink must be journald, syslog, syslog://host:port or syslog+tcp://host:port")
+}
+
+// openLogSink connects to the -log-sink destination and returns a logger
+// writing to it. The connection is made once, up front, so that it
+// survives the sandbox.
+func openLogSink(value string) (*slog.Logger, error) {
+	spec, err := parseLogSink(value)
+	if err != nil {
+		return nil, err
+	}
+	s := &logSink{journal: spec.journal, octets: spec.network == "tcp"}
+	switch {
+	case spec.journal:
+		s.conn, err = net.Dial("unixgram", "/run/systemd/journal/socket")
+	case spec.network != "":
+		s.conn, err = net.DialTimeout(spec.network, spec.addr, 10*time.Second)
+	default:
+		s.conn, s.newline, err = dialLocalSyslog()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.hostname, err = os.Hostname(); err != nil || s.hostname == "" {
+		s.hostname = "-"
+	}
+	return slog.New(&sinkHandler{sink: s}), nil
+}
+
+// dialLocalSyslog connects to the local syslog daemon's socket, as
+// log/syslog does. Messages on a stream socket are newline-terminated.
+func dialLocalSyslog() (net.Conn, bool, error) {
+	for _, path := range []string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+		if conn, err := net.Dial("unixgram", path); err == nil {
+			return conn, false, nil
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			return conn, true, nil
+		}
+	}
+	return nil, false, errorf("no local syslog socket found")
+}
+
+// logSink writes log records to a syslog daemon as RFC 5424 messages, or
+// to the systemd journal using its native protocol. It is safe for
+// concurrent use.
+type logSink struct {
+	journal  bool
+	octets   bool // RFC 6587 octet counting, for syslog over TCP
+	newline  bool
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// logField is a flattened attribute; groups are joined to their
+// attributes' keys with underscores.
+type logField struct {
+	key, value string
+}
+
+func (s *logSink) write(level slog.Level, t time.Time, message string, fields []logField) error {
+	var b []byte
+	if s.journal {
+		b = journalEntry(level, message, fields)
+	} else {
+		b = []byte(syslogMessage(s.hostname, level, t, message, fields))
+		if s.octets {
+			b = append([]byte(strconv.Itoa(len(b))+" "), b...)
+		} else if s.newline {
+			b = append(b, '\n')
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.conn.Write(b)
+	return err
+}
+
+// severity maps a level to a syslog severity.
+func severity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	}
+	return 7
+}
+
+// syslogMessage formats an RFC 5424 message, with the fields as the
+// parameters of one structured data element.
+func syslogMessage(hostname string, level slog.Level, t time.Time, message string, fields []logField) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	sd := "-"
+	if len(fields) > 0 {
+		var b strings.Builder
+		b.WriteString("[" + syslogSDID)
+		for _, f := range fields {
+			fmt.Fprintf(&b, " %s=\"%s\"", syslogParamName(f.key), syslogParamValue.Replace(f.value))
+		}
+		b.WriteString("]")
+		sd = b.String()
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - %s %s", syslogFacility*8+severity(level),
+		t.Format("2006-01-02T15:04:05.000000Z07:00"), hostname, logIdentifier, os.Getpid(), sd, message)
+}
+
+// syslogParamValue escapes the characters RFC 5424 reserves in
+// PARAM-VALUE.
+var syslogParamValue = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// syslogParamName makes key a valid PARAM-NAME: at most 32 printable
+// ASCII characters other than '=', ' ', ']' and '"'.
+func syslogParamName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return string(name)
+}
+
+// journalEntry encodes an entry in the journal's native protocol. The
+// fields are prefixed with SCRAM_ so that they cannot clash with the
+// journal's own.
+func journalEntry(level slog.Level, message string, fields []logField) []byte {
+	var b []byte
+	add := func(key, value string) {
+		if !strings.Contains(value, "\n") {
+			b = append(b, key+"="+value+"\n"...)
+			return
+		}
+		b = append(b, key+"\n"...)
+		b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+		b = append(b, value+"\n"...)
+	}
+	add("MESSAGE", message)
+	add("PRIORITY", strconv.Itoa(severity(level)))
+	add("SYSLOG_FACILITY", strconv.Itoa(syslogFacility))
+	add("SYSLOG_IDENTIFIER", logIdentifier)
+	for _, f := range fields {
+		add(journalFieldName(f.key), f.value)
+	}
+	return b
+}
+
+// journalFieldName makes key a valid journal field name: uppercase
+// letters, digits and underscores, at most 64 of them.
+func journalFieldName(key string) string {
+	name := []byte("SCRAM_" + strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
+
+// sinkHandler is the slog.Handler writing to a logSink.
+type sinkHandler struct {
+	sink   *logSink
+	fields []logField
+	prefix string
+}
+
+func (h *sinkHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *sinkHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := h.fields[:len(h.fields):len(h.fields)]
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendField(fields, h.prefix, a)
+		return true
+	})
+	return h.sink.write(r.Level, r.Time, r.Message, fields)
+}
+
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, a := range attrs {
+		h2.fields = appendField(h2.fields, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *sinkHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.prefix += name + "_"
+	return &h2
+}
+
+func appendField(fields []logField, prefix string, a slog.Attr) []logField {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "_"
+		}
+		for _, g := range a.Value.Group() {
+			fields = appendField(fields, prefix, g)
+		}
+		return fields
+	}
+	return append(fields, logField{prefix + a.Key, a.Value.String()})
+}
+
+// logGenerated records a result that was delivered. record is the -batch
+// record number, or 0.
+func logGenerated(config Config, record int) {
+	format := config.Format
+	if config.Formats != "" {
+		format = config.Formats
+	}
+	attrs := []slog.Attr{slog.String("format", format), slog.Int("iterations", config.Iterations)}
+	if config.User != "" {
+		attrs = append(attrs, slog.String("user", config.User))
+	}
+	if config.Role != "" {
+		attrs = append(attrs, slog.String("role", config.Role))
+	}
+	if config.Mechanism != "" {
+		attrs = append(attrs, slog.String("mechanism", config.Mechanism))
+	}
+	switch {
+	case config.Apply:
+		attrs = append(attrs, slog.String("destination", "database"), slog.String("target", config.Target))
+	case config.Copy:
+		attrs = append(attrs, slog.String("destination", "clipboard"))
+	case config.Output != "":
+		attrs = append(attrs, slog.String("destination", "file"), slog.String("output", config.Output))
+	default:
+		attrs = append(attrs, slog.String("destination", "stdout"))
+	}
+	if record > 0 {
+		attrs = append(attrs, slog.Int("record", record))
+	}
+	auditLog.LogAttrs(context.Background(), slog.LevelInfo, "verifier generated", attrs...)
+}
@@ -1,169 +1,822 @@
 package main
 
 import (
-	"bufio"
-	"crypto/hmac"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/base64"
+	"context"
+	"crypto"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
-	"golang.org/x/crypto/pbkdf2"
 	"io"
 	"os"
 	"strings"
-	"syscall"
+	"time"
 	"unicode/utf8"
 
+	"github.com/SonOfBytes/scram-sha-256/scram"
 	"golang.org/x/term"
 )
 
 const (
 	defaultIterations = 4096
-	saltLength       = 16
-	keyLength        = 32
+	defaultAttempts   = 3
+	defaultMaxLength  = 1024
+	// maxDocumentSize bounds JSON and argument files read by the
+	// integration modes.
+	maxDocumentSize = 1 << 20
 )
 
+// Exit codes, so wrappers can tell a typo from a rejected password.
+const (
+	exitError    = 1
+	exitMismatch = 2
+	exitPolicy   = 3
+)
+
+var errPasswordMismatch = errors.New("passwords do not match")
+
+// maxPasswordLength is the limit, in bytes, applied by validatePassword
+// and the stdin reader. It is set from -max-length.
+var maxPasswordLength = defaultMaxLength
+
+// PasswordTooLongError reports input over the configured maximum length.
+type PasswordTooLongError struct {
+	Max int
+}
+
+func (e *PasswordTooLongError) Error() string {
+	return msg.Sprintf("password exceeds maximum length of %d bytes", e.Max)
+}
+
 type Config struct {
-	UseStdin   bool
-	ShowHelp   bool
-	Iterations int
+	UseStdin       bool
+	ShowHelp       bool
+	Iterations     int
+	Terraform      bool
+	Ansible        bool
+	Format         string
+	Role           string
+	FIPS           bool
+	Keychain       string
+	Copy           bool
+	CopyTimeout    time.Duration
+	TUI            bool
+	Confirm        bool
+	Attempts       int
+	MaxLength      int
+	Strict         bool
+	PasswordFile   string
+	GPG            bool
+	Credential     string
+	SaltedPassword string
+	Salt           string
+	Passphrase     string
+	Wordlist       string
+	SaltFromKey    bool
+	KeyFile        string
+	User           string
+	Batch          bool
+	Null           bool
+	Print0         bool
+	TTY            bool
+	JSONRecords    bool
+	Report         bool
+	Formats        string
+	// Mechanism is the SCRAM mechanism of scram verifiers, set by
+	// -json-records; empty means SCRAM-SHA-256.
+	Mechanism string
+	Output    string
+	Force     bool
+	Target    string
+	Apply     bool
+	DSN       string
+	Lang      string
+	Prompt    string
+	Echo      string
+	LogSink   string
+	// IterationsSet records whether -i was given, so targets can change
+	// the default.
+	IterationsSet bool
+	// IterationsBudget is set by -i auto[:duration]; Iterations is then
+	// calibrated to fit it.
+	IterationsBudget time.Duration
+}
+
+var subcommands = map[string]func(args []string) int{
+	"migrate":     runMigrate,
+	"audit":       runAudit,
+	"decode":      runDecode,
+	"lint":        runLint,
+	"serve":       runServe,
+	"prove":       runProve,
+	"conformance": runConformance,
+	"bootstrap":   runBootstrap,
+	"rotate":      runRotate,
+	"verify":      runVerify,
+	"docs":        runDocs,
+	"self-update": runSelfUpdate,
+	"version":     runVersion,
 }
 
 func main() {
+	// An unsupported locale falls back to English, as with other programs.
+	setLanguage(languageFromEnv())
+
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
 	config := parseFlags()
+	jsonErrors = config.Format == "json" || config.Formats != ""
+
+	if config.Lang != "" {
+		if err := setLanguage(config.Lang); err != nil {
+			failOptions(err)
+		}
+	}
 
 	if config.ShowHelp {
 		showHelp()
 		os.Exit(0)
 	}
 
+	if config.MaxLength < 1 {
+		failOptions(errorf("-max-length must be at least 1"))
+	}
+	maxPasswordLength = config.MaxLength
+
+	if err := validateEcho(config.Echo); err != nil {
+		failOptions(err)
+	}
+	passwordPrompt = config.Prompt
+	echoMode = config.Echo
+
+	if config.FIPS {
+		if err := checkFIPS(); err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: %v\n", err))
+		}
+	}
+
+	// Several formats build their output without going through
+	// scram.NewVerifier, so the count is checked once for all of them.
+	if config.IterationsBudget == 0 && config.Iterations < 1 {
+		failOptions(errorf("-i must be at least 1"))
+	}
+
+	if err := validateFormat(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validateTarget(&config, config.IterationsSet); err != nil {
+		failOptions(err)
+	}
+
+	if config.Output != "" {
+		if config.Copy {
+			failOptions(errorf("-output and -copy cannot be combined"))
+		}
+		if err := checkOutputFile(config.Output, config.Force); err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: %v\n", err))
+		}
+	}
+
+	if config.GPG && config.PasswordFile == "" {
+		failOptions(errorf("-gpg requires -password-file"))
+	}
+
+	if err := validateSaltedPassword(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validatePassphrase(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validateSaltFromKey(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validateFormats(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validateBatch(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validateTTY(config); err != nil {
+		failOptions(err)
+	}
+
+	if err := validateReport(config); err != nil {
+		failOptions(err)
+	}
+
+	if config.LogSink != "" {
+		if _, err := parseLogSink(config.LogSink); err != nil {
+			failOptions(err)
+		}
+	}
+
+	// The key is read before the sandbox is set up and before prompting,
+	// so a bad key file fails without asking for the password. In batch
+	// mode each record's salt is derived as the record is read.
+	var saltKey, derivedSalt []byte
+	if config.SaltFromKey {
+		key, err := readSaltKey(config.KeyFile)
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error deriving salt: %v\n", err))
+		}
+		saltKey = key
+		if !config.Batch {
+			if derivedSalt, err = deriveSalt(saltKey, saltUser(config)); err != nil {
+				fail(exitError, failure{Code: codeError}, msg.Sprintf("Error deriving salt: %v\n", err))
+			}
+		}
+	}
+
+	if config.TTY {
+		if err := openTTY(); err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error opening the controlling terminal: %v\n", err))
+		}
+	}
+
+	if config.LogSink != "" {
+		logger, err := openLogSink(config.LogSink)
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error opening log sink: %v\n", err))
+		}
+		auditLog = logger
+	}
+
+	if config.IterationsBudget > 0 {
+		iterations, err := calibrateIterations(formatHash(config.Format), config.IterationsBudget)
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error calibrating iterations: %v\n", err))
+		}
+		config.Iterations = iterations
+		msg.Eprintf("Calibrated iterations: %d (%v budget)\n", iterations, config.IterationsBudget)
+	}
+
+	if err := sandbox(config); err != nil {
+		fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: failed to restrict process: %v\n", err))
+	}
+
+	if config.Terraform {
+		if err := runTerraform(os.Stdin, os.Stdout, config.Iterations); err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: %v\n", err))
+		}
+		return
+	}
+
+	if config.Ansible {
+		os.Exit(runAnsible(flag.Arg(0), os.Stdout, config.Iterations))
+	}
+
+	if config.SaltedPassword != "" {
+		verifier, err := verifierFromSaltedPassword(config.SaltedPassword, config.Salt, config.Iterations)
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: %v\n", err))
+		}
+		writeResult(config, []string{verifier}, "")
+		return
+	}
+
+	if config.Batch {
+		os.Exit(runBatch(config, os.Stdin, saltKey))
+	}
+
 	var password string
 	var err error
+	// passphraseBits is the exact entropy of a generated passphrase, for
+	// -report; typed passwords are estimated instead.
+	var passphraseBits float64
 
-	if config.UseStdin {
+	if config.Passphrase != "" {
+		password, passphraseBits, err = passphraseFromConfig(config)
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error generating passphrase: %v\n", err))
+		}
+	} else if config.TUI {
+		password, err = runTUI(&config)
+		if isInvalidPassword(err) {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+		}
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: %v\n", err))
+		}
+	} else if config.Keychain != "" {
+		password, err = readPasswordFromKeychain(config.Keychain)
+		if err != nil {
+			fail(exitError, failure{Code: codeReadError}, msg.Sprintf("Error reading password from keychain: %v\n", err))
+		}
+	} else if config.PasswordFile != "" {
+		password, err = readPasswordFile(config.PasswordFile, config.GPG)
+		if isInvalidPassword(err) {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+		}
+		if err != nil {
+			fail(exitError, failure{Code: codeReadError}, msg.Sprintf("Error reading password file: %v\n", err))
+		}
+	} else if config.Credential != "" {
+		password, err = readCredential(config.Credential)
+		if isInvalidPassword(err) {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+		}
+		if err != nil {
+			fail(exitError, failure{Code: codeReadError}, msg.Sprintf("Error reading credential: %v\n", err))
+		}
+	} else if config.UseStdin {
 		password, err = readPasswordFromStdin()
+		if isInvalidPassword(err) {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading password from stdin: %v\n", err)
-			os.Exit(1)
+			fail(exitError, failure{Code: codeReadError}, msg.Sprintf("Error reading password from stdin: %v\n", err))
+		}
+	} else if config.Confirm {
+		password, err = promptConfirmedPassword(passwordPromptText(), config.Attempts)
+		if errors.Is(err, errPasswordMismatch) {
+			fail(exitMismatch, failure{Code: codePasswordMismatch, Field: "password"},
+				msg.Sprintf("Error: %v after %d attempts\n", msg.Error(err), max(config.Attempts, 1)))
+		}
+		if isInvalidPassword(err) {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+		}
+		if err != nil {
+			fail(exitError, failure{Code: codeReadError}, msg.Sprintf("Error reading password: %v\n", err))
 		}
 	} else {
 		password, err = promptPassword()
+		if isInvalidPassword(err) {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
-			os.Exit(1)
+			fail(exitError, failure{Code: codeReadError}, msg.Sprintf("Error reading password: %v\n", err))
 		}
 	}
 
 	if err := validatePassword(password); err != nil {
-		fmt.Fprintf(os.Stderr, "Invalid password: %v\n", err)
-		os.Exit(1)
+		fail(exitPolicy, failure{Code: codeInvalidPassword, Message: msg.Error(err), Field: "password"}, msg.Sprintf("Invalid password: %v\n", msg.Error(err)))
+	}
+
+	for _, warning := range passwordWarnings(password) {
+		if config.Strict {
+			fail(exitPolicy, failure{Code: codeInvalidPassword, Message: warning, Field: "password"}, msg.Sprintf("Invalid password: %s\n", warning))
+		}
+		warn(failure{Message: warning, Field: "password"}, msg.Sprintf("Warning: %s\n", warning))
+	}
+
+	if config.Formats != "" {
+		doc, err := generateFormats(derivationContext(config.Iterations), config, password, derivedSalt)
+		if errors.Is(err, scram.ErrIterationsTooLow) {
+			failOptions(scram.ErrIterationsTooLow)
+		}
+		if err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error %v\n", err))
+		}
+		writeResult(config, []string{string(doc)}, "")
+		if config.Report {
+			writeReport(os.Stderr, config, estimateEntropy(password), false)
+		}
+		return
 	}
 
-	hash, err := generateSCRAMSHA256(password, config.Iterations)
+	output, err := generateOutput(config, password, derivedSalt)
+	if errors.Is(err, scram.ErrIterationsTooLow) {
+		failOptions(scram.ErrIterationsTooLow)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating SCRAM-SHA-256: %v\n", err)
-		os.Exit(1)
+		fail(exitError, failure{Code: codeError}, msg.Sprintf("Error %v\n", err))
+	}
+
+	passphrase := ""
+	if config.Passphrase != "" {
+		passphrase = password
+	}
+	writeResult(config, output, passphrase)
+
+	if config.Report {
+		if passphrase != "" {
+			writeReport(os.Stderr, config, passphraseBits, true)
+		} else {
+			writeReport(os.Stderr, config, estimateEntropy(password), false)
+		}
+	}
+}
+
+// generateOutput returns the lines -format asks for. The SCRAM verifier
+// and the yaml and json documents use salt when it is non-nil and a
+// random salt otherwise.
+func generateOutput(config Config, password string, salt []byte) ([]string, error) {
+	var output []string
+
+	if config.Format == "scram" || config.Format == "both" {
+		h, mechanism := crypto.SHA256, "SCRAM-SHA-256"
+		if config.Mechanism != "" {
+			h, _ = scram.MechanismHash(config.Mechanism)
+			mechanism = config.Mechanism
+		}
+		var hash string
+		var err error
+		if salt != nil {
+			hash, err = verifierWithSalt(derivationContext(config.Iterations), h, password, salt, config.Iterations)
+		} else {
+			hash, err = scram.NewVerifierContext(derivationContext(config.Iterations), h, password, config.Iterations)
+		}
+		if err != nil {
+			return nil, errorf("generating %s: %w", mechanism, err)
+		}
+		output = append(output, hash)
+	}
+
+	if config.Format == "pg-md5" || config.Format == "both" {
+		output = append(output, generatePGMD5(password, config.Role))
+	}
+
+	if config.Format == "ejabberd" || config.Format == "prosody" {
+		generate := generateEjabberd
+		if config.Format == "prosody" {
+			generate = generateProsody
+		}
+		fields, err := generate(password, config.Iterations)
+		if err != nil {
+			return nil, errorf("generating %s credentials: %w", config.Format, err)
+		}
+		output = append(output, fields...)
+	}
+
+	if config.Format == "yaml" || config.Format == "json" {
+		generate := generateYAML
+		if config.Format == "json" {
+			generate = generateJSON
+		}
+		doc, err := generate(derivationContext(config.Iterations), config, password, salt)
+		if err != nil {
+			return nil, errorf("generating SCRAM-SHA-256: %w", err)
+		}
+		output = append(output, doc)
+	}
+
+	if config.Format == "dovecot" {
+		hash, err := generateDovecot(password, config.Iterations)
+		if err != nil {
+			return nil, errorf("generating Dovecot password: %w", err)
+		}
+		output = append(output, hash)
+	}
+
+	if config.Format == "rabbitmq" {
+		hash, err := generateRabbitMQ(password)
+		if err != nil {
+			return nil, errorf("generating RabbitMQ hash: %w", err)
+		}
+		output = append(output, hash)
 	}
 
-	fmt.Println(hash)
+	return output, nil
+}
+
+// writeResult applies, copies, writes or prints the generated output as
+// the options ask. A generated passphrase is emitted ahead of the output,
+// since nothing else records it.
+func writeResult(config Config, output []string, passphrase string) {
+	// fail exits without running deferred calls, so only results that
+	// were delivered are logged.
+	defer logGenerated(config, 0)
+
+	if config.Apply {
+		if err := applyPassword(config.DSN, config.Target, config.Role, output[0]); err != nil {
+			fail(exitError, failure{Code: codeError}, msg.Sprintf("Error: %v\n", err))
+		}
+		msg.Eprintf("Password updated for %s\n", config.Role)
+		if passphrase != "" {
+			fmt.Println(passphrase)
+		}
+		return
+	}
+
+	if config.Target == "cockroach" {
+		output = []string{passwordStatement(config.Target, config.Role, output[0])}
+	}
+	if passphrase != "" {
+		output = append([]string{passphrase}, output...)
+	}
+
+	if config.Copy {
+		if err := copyToClipboard(strings.Join(output, "\n"), config.CopyTimeout); err != nil {
+			fail(exitError, failure{Code: codeOutputError}, msg.Sprintf("Error copying to clipboard: %v\n", err))
+		}
+		return
+	}
+
+	if config.Output != "" {
+		data := []byte(strings.Join(output, "\n") + "\n")
+		if err := writeOutputFile(config.Output, data, config.Force); err != nil {
+			fail(exitError, failure{Code: codeOutputError}, msg.Sprintf("Error writing output: %v\n", err))
+		}
+		return
+	}
+
+	for _, line := range output {
+		fmt.Println(line)
+	}
 }
 
 func parseFlags() Config {
-	config := Config{}
-	
-	flag.BoolVar(&config.UseStdin, "stdin", false, "Read password from stdin instead of prompting")
-	flag.BoolVar(&config.ShowHelp, "help", false, "Show help message")
-	flag.BoolVar(&config.ShowHelp, "h", false, "Show help message")
-	flag.IntVar(&config.Iterations, "iterations", defaultIterations, "Number of PBKDF2 iterations")
-	flag.IntVar(&config.Iterations, "i", defaultIterations, "Number of PBKDF2 iterations")
-	
+	config := Config{Iterations: defaultIterations}
+
+	defineFlags(flag.CommandLine, &config)
+
 	flag.Parse()
-	
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "i" || f.Name == "iterations" {
+			config.IterationsSet = true
+		}
+	})
+
 	return config
 }
 
+// defineFlags defines the main command's flags on fs, which is
+// flag.CommandLine except when docs man describes them.
+func defineFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.UseStdin, "stdin", false, "Read password from stdin instead of prompting")
+	fs.BoolVar(&config.Batch, "batch", false, "Read one password per line from stdin and print a result for each")
+	fs.BoolVar(&config.Null, "0", false, "With -batch, read NUL-terminated passwords instead of lines; control characters such as newline are still rejected")
+	fs.BoolVar(&config.Print0, "print0", false, "With -batch, end each result with NUL instead of a newline")
+	fs.BoolVar(&config.JSONRecords, "json-records", false, "With -batch, read each record as a JSON object whose fields override the flags")
+	fs.BoolVar(&config.TTY, "tty", false, "Prompt on the controlling terminal, leaving stdin for -batch user names")
+	fs.BoolVar(&config.ShowHelp, "help", false, "Show help message")
+	fs.BoolVar(&config.ShowHelp, "h", false, "Show help message")
+	fs.StringVar(&config.Lang, "lang", "", "Language for prompts, help and messages (default: $LC_ALL, $LC_MESSAGES or $LANG)")
+	iterations := iterationsValue{&config.Iterations, &config.IterationsBudget}
+	fs.Var(iterations, "iterations", "Number of PBKDF2 iterations, or auto[:duration] to calibrate")
+	fs.Var(iterations, "i", "Number of PBKDF2 iterations, or auto[:duration] to calibrate")
+	fs.BoolVar(&config.Terraform, "terraform", false, "Run as a Terraform external data source")
+	fs.BoolVar(&config.Ansible, "ansible", false, "Run as an Ansible module reading the given args file")
+	fs.StringVar(&config.Format, "format", "scram", "Output format: scram, pg-md5, both, rabbitmq, ejabberd, prosody, dovecot, yaml or json")
+	fs.StringVar(&config.Formats, "formats", "", "Comma-separated formats to print together as JSON: "+strings.Join(multiFormats, ", "))
+	fs.StringVar(&config.Role, "role", "", "PostgreSQL role name, required for pg-md5 output")
+	fs.StringVar(&config.Target, "target", "postgres", "Target database: postgres or cockroach")
+	fs.BoolVar(&config.Apply, "apply", false, "Set the password of -role on the database at -dsn")
+	fs.StringVar(&config.DSN, "dsn", os.Getenv("DATABASE_URL"), "Connection URL for -apply (default: $DATABASE_URL, then PG* variables)")
+	fs.BoolVar(&config.FIPS, "fips", false, "Refuse to run unless FIPS 140-3 mode is enabled")
+	fs.StringVar(&config.Keychain, "keychain", "", "Read password from the OS keychain item with this name")
+	fs.StringVar(&config.PasswordFile, "password-file", "", "Read password from the first line of this file")
+	fs.BoolVar(&config.GPG, "gpg", false, "Decrypt -password-file with gpg before reading it")
+	fs.StringVar(&config.Credential, "credential", "", "Read password from this systemd credential in $CREDENTIALS_DIRECTORY")
+	fs.StringVar(&config.SaltedPassword, "salted-password", "", "Build the verifier from this hex SaltedPassword instead of a password")
+	fs.StringVar(&config.Salt, "salt", "", "Base64 salt the -salted-password was derived with")
+	fs.StringVar(&config.Passphrase, "passphrase", "", "Generate a diceware passphrase instead of reading a password, e.g. words=6")
+	fs.StringVar(&config.Wordlist, "wordlist", "", "Diceware wordlist for -passphrase (default: EFF's large wordlist, built in)")
+	fs.BoolVar(&config.SaltFromKey, "salt-from-key", false, "Derive the salt from -key-file and -user so re-runs give the same verifier")
+	fs.StringVar(&config.KeyFile, "key-file", "", "Provisioning key file for -salt-from-key")
+	fs.StringVar(&config.User, "user", "", "User name the -salt-from-key salt is derived for (default: -role)")
+	fs.BoolVar(&config.Confirm, "confirm", false, "Prompt twice and require both entries to match")
+	fs.IntVar(&config.Attempts, "attempts", defaultAttempts, "Number of tries allowed when -confirm entries differ")
+	fs.BoolVar(&config.Report, "report", false, "Print an estimate of the cost of cracking the result offline to stderr")
+	fs.BoolVar(&config.Strict, "strict", false, "Treat password warnings (bidi controls, confusable scripts) as errors")
+	fs.IntVar(&config.MaxLength, "max-length", defaultMaxLength, "Maximum password length in bytes")
+	fs.StringVar(&config.Prompt, "prompt", "", "Text shown when prompting for the password (default \"Password: \")")
+	fs.StringVar(&config.Echo, "echo", echoNone, "What to show while the password is typed: none, mask or reveal-last")
+	fs.BoolVar(&config.TUI, "tui", false, "Interactive mode with strength meter and guided choices")
+	fs.StringVar(&config.Output, "output", "", "Write the result to this file (mode 0600) instead of stdout")
+	fs.BoolVar(&config.Force, "force", false, "Allow -output to replace an existing file")
+	fs.BoolVar(&config.Copy, "copy", false, "Copy the result to the clipboard instead of printing it")
+	fs.DurationVar(&config.CopyTimeout, "copy-timeout", 30*time.Second, "Clear the clipboard after this long (0 to keep)")
+	fs.StringVar(&config.LogSink, "log-sink", "", "Also log credential operations to journald, syslog or syslog[+tcp]://host:port")
+}
+
 func showHelp() {
-	fmt.Println("SCRAM-SHA-256 Password Generator")
+	msg.Println("SCRAM-SHA-256 Password Generator")
 	fmt.Println()
-	fmt.Println("USAGE:")
-	fmt.Printf("  %s [OPTIONS]\n", os.Args[0])
+	msg.Println("USAGE:")
+	msg.Printf("  %s [OPTIONS]\n", os.Args[0])
+	msg.Printf("  %s COMMAND [OPTIONS]\n", os.Args[0])
 	fmt.Println()
-	fmt.Println("OPTIONS:")
-	fmt.Println("  -stdin           Read password from stdin instead of prompting")
-	fmt.Println("  -h, -help        Show this help message")
-	fmt.Println("  -i, -iterations  Number of PBKDF2 iterations (default: 4096)")
+	msg.Println("COMMANDS:")
+	msg.Println("  migrate          Re-hash PostgreSQL roles still using md5 to SCRAM-SHA-256")
+	msg.Println("  audit            Report PostgreSQL roles with md5, missing or weak passwords")
+	msg.Println("  decode           Parse and explain SCRAM handshake messages")
+	msg.Println("  lint             Check stored verifiers against format and policy rules")
+	msg.Println("  serve -mock      Run a mock SCRAM server for testing client implementations")
+	msg.Println("  prove            Recompute ClientProof and ServerSignature from a captured exchange")
+	msg.Println("  conformance      Run SCRAM handshake scenarios against a live server")
+	msg.Println("  bootstrap        Write SQL, userlist and Kubernetes secrets for a manifest of users")
+	msg.Println("  rotate           Check a password against its verifier and re-hash it with a fresh salt")
+	msg.Println("  verify           Check a password against its verifier, offering to upgrade low iteration counts")
+	msg.Println("  docs man         Write roff man pages for the tool and every command")
+	msg.Println("  self-update      Replace this binary with the latest signed release")
+	msg.Println("  version          Show version and FIPS 140-3 status")
 	fmt.Println()
-	fmt.Println("EXAMPLES:")
-	fmt.Printf("  %s                    # Prompt for password\n", os.Args[0])
-	fmt.Printf("  echo 'mypass' | %s -stdin  # Read from stdin\n", os.Args[0])
-	fmt.Printf("  %s -i 8192               # Custom iterations\n", os.Args[0])
+	msg.Println("OPTIONS:")
+	msg.Println("  -stdin           Read password from stdin instead of prompting")
+	msg.Println("  -batch           Read one password per line from stdin and print a result for each")
+	msg.Println("  -0               With -batch, read NUL-terminated passwords (as from find -print0);")
+	msg.Println("                   newlines and other control characters are still rejected")
+	msg.Println("  -print0          With -batch, end each result with NUL instead of a newline")
+	msg.Println("  -json-records    With -batch, read JSON records that override -i, -format and -role")
+	msg.Println("  -tty             Prompt on the controlling terminal; with -batch, stdin lists users")
+	msg.Println("  -confirm         Prompt twice and require both entries to match")
+	msg.Println("  -attempts N      Number of tries allowed when -confirm entries differ (default: 3)")
+	msg.Println("  -tui             Interactive mode with strength meter and guided choices")
+	msg.Println("  -max-length N    Maximum password length in bytes (default: 1024)")
+	msg.Println("  -prompt TEXT     Text shown when prompting for the password (default: \"Password: \")")
+	msg.Println("  -echo MODE       Show nothing (none), an asterisk per character (mask), or briefly")
+	msg.Println("                   the last character typed (reveal-last) (default: none)")
+	msg.Println("  -report          Print the estimated cost of cracking the result offline to stderr")
+	msg.Println("  -strict          Treat password warnings (bidi controls, confusable scripts) as errors")
+	msg.Println("  -keychain ITEM   Read password from the OS keychain (macOS Keychain, Windows")
+	msg.Println("                   Credential Manager, libsecret or KWallet)")
+	msg.Println("  -password-file F Read password from the first line of file F")
+	msg.Println("  -gpg             Decrypt -password-file with gpg (e.g. a pass/gopass entry)")
+	msg.Println("  -credential NAME Read password from systemd credential NAME ($CREDENTIALS_DIRECTORY)")
+	msg.Println("  -salted-password HEX")
+	msg.Println("                   Build the verifier from a precomputed SaltedPassword; needs -salt and -i")
+	msg.Println("  -salt B64        Base64 salt the -salted-password was derived with")
+	msg.Println("  -passphrase SPEC Generate a diceware passphrase and print it before its verifier;")
+	msg.Println("                   SPEC is words=N[,sep=S] (default: words=6, space separated)")
+	msg.Println("  -wordlist FILE   Wordlist for -passphrase (default: EFF's large wordlist, built in)")
+	msg.Println("  -salt-from-key   Derive the salt from -key-file and -user with HKDF, so re-runs")
+	msg.Println("                   give the same verifier")
+	msg.Println("  -key-file FILE   Provisioning key for -salt-from-key (at least 32 bytes)")
+	msg.Println("  -user NAME       User the derived salt belongs to (default: -role)")
+	msg.Println("  -h, -help        Show this help message")
+	msg.Println("  -lang LANG       Language for prompts, help and messages: en, de, es or fr")
+	msg.Println("                   (default: from $LC_ALL, $LC_MESSAGES or $LANG)")
+	msg.Println("  -i, -iterations  Number of PBKDF2 iterations (default: 4096), or auto[:duration]")
+	msg.Println("                   to fit a time budget (default duration: 100ms)")
+	msg.Println("  -terraform       Run as a Terraform external data source (JSON on stdin)")
+	msg.Println("  -ansible FILE    Run as an Ansible module reading JSON arguments from FILE")
+	msg.Println("  -format FORMAT   Output format: scram, pg-md5, both, rabbitmq, ejabberd,")
+	msg.Println("                   prosody, dovecot, yaml or json (default: scram)")
+	msg.Println("  -formats LIST    Print several formats from one password as a JSON document; LIST is")
+	msg.Println("                   comma-separated from postgres, pg-md5, kafka, mongodb, dovecot,")
+	msg.Println("                   ejabberd, prosody and rabbitmq")
+	msg.Println("  -role NAME       PostgreSQL role name, required for pg-md5 output")
+	msg.Println("  -target TARGET   Target database: postgres or cockroach (default: postgres)")
+	msg.Println("  -apply           Set the password of -role on the database at -dsn")
+	msg.Println("  -dsn URL         Connection URL for -apply (default: $DATABASE_URL)")
+	msg.Println("  -fips            Refuse to run unless FIPS 140-3 mode is enabled")
+	msg.Println("  -output FILE     Write the result to FILE (mode 0600) instead of stdout")
+	msg.Println("  -force           Allow -output to replace an existing file")
+	msg.Println("  -copy            Copy the result to the clipboard instead of printing it")
+	msg.Println("  -copy-timeout D  Clear the clipboard after D (default: 30s, 0 to keep)")
+	msg.Println("  -log-sink SINK   Also log credential operations to journald, syslog (local),")
+	msg.Println("                   syslog://host:port (UDP) or syslog+tcp://host:port")
 	fmt.Println()
-	fmt.Println("INSTALLATION:")
-	fmt.Println("  go install github.com/SonOfBytes/scram-sha-256@latest")
+	msg.Println("EXAMPLES:")
+	msg.Printf("  %s                    # Prompt for password\n", os.Args[0])
+	msg.Printf("  echo 'mypass' | %s -stdin  # Read from stdin\n", os.Args[0])
+	msg.Printf("  %s -i 8192               # Custom iterations\n", os.Args[0])
+	msg.Printf("  %s -format both -role app  # SCRAM and legacy md5 hashes\n", os.Args[0])
+	fmt.Println()
+	msg.Println("INSTALLATION:")
+	msg.Println("  go install github.com/SonOfBytes/scram-sha-256@latest")
+}
+
+func validateFormat(config Config) error {
+	switch config.Format {
+	case "scram", "rabbitmq", "ejabberd", "prosody", "dovecot":
+	case "yaml", "json":
+		// A passphrase would be printed ahead of the document.
+		if config.Passphrase != "" {
+			return errorf("-format %s cannot be combined with -passphrase", config.Format)
+		}
+	case "pg-md5", "both":
+		// With -batch -tty each record's user is the role.
+		if config.Role == "" && !(config.Batch && config.TTY) {
+			return errorf("-format %s requires -role", config.Format)
+		}
+	default:
+		return errorf("unknown format %q", config.Format)
+	}
+	// Also reached for each -json-records format override.
+	if config.FIPS {
+		return checkFIPSFormat(config.Format)
+	}
+	return nil
 }
 
 func promptPassword() (string, error) {
-	fmt.Print("Password: ")
-	
-	passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
+	return promptPasswordWithText(passwordPromptText())
+}
+
+// passwordPromptText is the -prompt text, or else "Password: ".
+func passwordPromptText() string {
+	if passwordPrompt != "" {
+		return promptText(passwordPrompt)
+	}
+	return msg.Text("Password: ")
+}
+
+// promptConfirmedPassword asks for the password twice, showing prompt
+// first, and re-prompts up to attempts times while the entries differ.
+func promptConfirmedPassword(prompt string, attempts int) (string, error) {
+	attempts = max(attempts, 1)
+	for i := 0; i < attempts; i++ {
+		password, err := promptPasswordWithText(prompt)
+		if err != nil {
+			return "", err
+		}
+		confirmation, err := promptPasswordWithText(msg.Text("Confirm password: "))
+		if err != nil {
+			return "", err
+		}
+		if password == confirmation {
+			return password, nil
+		}
+		if i < attempts-1 {
+			fmt.Fprintln(os.Stderr, msg.Text("Passwords do not match, try again."))
+		}
+	}
+	return "", errPasswordMismatch
+}
+
+func promptPasswordWithText(prompt string) (string, error) {
+	if echoMode != echoNone {
+		return readMaskedPassword(prompt, echoMode == echoRevealLast)
+	}
+
+	in, out := promptTerminal(os.Stdout)
+	fmt.Fprint(out, prompt)
+
+	passwordBytes, err := term.ReadPassword(int(in.Fd()))
 	if err != nil {
-		return "", fmt.Errorf("failed to read password: %w", err)
+		return "", errorf("failed to read password: %w", err)
 	}
-	
-	fmt.Println()
+
+	fmt.Fprintln(out)
 	return string(passwordBytes), nil
 }
 
 func readPasswordFromStdin() (string, error) {
-	reader := bufio.NewReader(os.Stdin)
-	password, err := reader.ReadString('\n')
-	if err != nil && err != io.EOF {
-		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	return readPasswordLine(os.Stdin)
+}
+
+// readPasswordLine returns the first line of r without its line ending.
+// Oversize input is a *PasswordTooLongError and invalid UTF-8 is
+// scram.ErrInvalidUTF8, so that isInvalidPassword can tell them from
+// read errors.
+func readPasswordLine(r io.Reader) (string, error) {
+	password, err := scram.ReadPassword(r, scram.WithMaxLength(maxPasswordLength))
+	if errors.Is(err, scram.ErrPasswordTooLong) {
+		return "", &PasswordTooLongError{Max: maxPasswordLength}
 	}
-	
-	return strings.TrimRight(password, "\r\n"), nil
+	if errors.Is(err, scram.ErrInvalidUTF8) {
+		return "", scram.ErrInvalidUTF8
+	}
+	if err != nil {
+		return "", errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
+
+// isInvalidPassword reports whether err from reading a password rejects
+// the password itself rather than reporting a failure to read it.
+func isInvalidPassword(err error) bool {
+	var tooLong *PasswordTooLongError
+	return errors.As(err, &tooLong) || errors.Is(err, scram.ErrInvalidUTF8)
 }
 
 func validatePassword(password string) error {
 	if len(password) == 0 {
-		return fmt.Errorf("password cannot be empty")
+		return scram.ErrEmptyPassword
 	}
-	
+
+	if len(password) > maxPasswordLength {
+		return &PasswordTooLongError{Max: maxPasswordLength}
+	}
+
 	if !utf8.ValidString(password) {
-		return fmt.Errorf("password must be valid UTF-8")
+		return scram.ErrInvalidUTF8
 	}
-	
+
+	if err := checkSASLprep(password); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func generateSCRAMSHA256(password string, iterations int) (string, error) {
-	if iterations < 1 {
-		return "", fmt.Errorf("iterations must be at least 1")
-	}
-	
-	salt := make([]byte, saltLength)
-	if _, err := rand.Read(salt); err != nil {
-		return "", fmt.Errorf("failed to generate salt: %w", err)
-	}
-	
-	saltedPassword := pbkdf2.Key([]byte(password), salt, iterations, keyLength, sha256.New)
-	
-	clientKey := hmac.New(sha256.New, saltedPassword)
-	clientKey.Write([]byte("Client Key"))
-	clientKeyBytes := clientKey.Sum(nil)
-	
-	storedKey := sha256.Sum256(clientKeyBytes)
-	
-	serverKey := hmac.New(sha256.New, saltedPassword)
-	serverKey.Write([]byte("Server Key"))
-	serverKeyBytes := serverKey.Sum(nil)
-	
-	saltB64 := base64.StdEncoding.EncodeToString(salt)
-	storedKeyB64 := base64.StdEncoding.EncodeToString(storedKey[:])
-	serverKeyB64 := base64.StdEncoding.EncodeToString(serverKeyBytes)
-	
-	result := fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s", iterations, saltB64, storedKeyB64, serverKeyB64)
-	
-	return result, nil
-}
\ No newline at end of file
+	return generateSCRAMSHA256Context(context.Background(), password, iterations)
+}
+
+func generateSCRAMSHA256Context(ctx context.Context, password string, iterations int) (string, error) {
+	return scram.NewVerifierContext(ctx, crypto.SHA256, password, iterations)
+}
+
+func generatePGMD5(password, role string) string {
+	sum := md5.Sum([]byte(password + role))
+	return "md5" + hex.EncodeToString(sum[:])
+}
+
+func verifySCRAMSHA256(password, verifier string) (bool, error) {
+	return scram.Verify(password, verifier)
+}
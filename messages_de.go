@@ -0,0 +1,417 @@
+package main
+
+// catalogDE holds the German translations.
+var catalogDE = map[string]string{
+	// Prompts and password validation.
+	"Password: ":                                  "Passwort: ",
+	"Password for %s: ":                           "Passwort für %s: ",
+	"Confirm password: ":                          "Passwort bestätigen: ",
+	"Passwords do not match, try again.":          "Die Passwörter stimmen nicht überein, bitte erneut versuchen.",
+	"passwords do not match":                      "Passwörter stimmen nicht überein",
+	"password cannot be empty":                    "Passwort darf nicht leer sein",
+	"password must be valid UTF-8":                "Passwort muss gültiges UTF-8 sein",
+	"password exceeds maximum length of %d bytes": "Passwort überschreitet die maximale Länge von %d Bytes",
+	"password contains %s U+%04X at position %d":  "Passwort enthält %s U+%04X an Position %d",
+	"control character":                           "Steuerzeichen",
+	"unassigned code point":                       "nicht zugewiesenen Codepunkt",
+	"non-ASCII control character":                 "Nicht-ASCII-Steuerzeichen",
+	"private use character":                       "Zeichen zur privaten Nutzung",
+	"non-character code point":                    "Nichtzeichen-Codepunkt",
+	"surrogate code point":                        "Surrogat-Codepunkt",
+	"character inappropriate for plain text":      "für Klartext ungeeignetes Zeichen",
+	"ideographic description character":           "ideografisches Beschreibungszeichen",
+	"display-changing or deprecated character":    "anzeigeveränderndes oder veraltetes Zeichen",
+	"tagging character":                           "Tag-Zeichen",
+	"password contains invisible bidirectional control U+%04X at position %d":                                      "Passwort enthält unsichtbares bidirektionales Steuerzeichen U+%04X an Position %d",
+	"password mixes right-to-left and left-to-right letters, which SASLprep rejects and editors display reordered": "Passwort mischt Buchstaben von rechts nach links und von links nach rechts, was SASLprep ablehnt und Editoren umgeordnet anzeigen",
+	"password mixes visually confusable %s letters":                                                                "Passwort mischt leicht verwechselbare Buchstaben der Schriften %s",
+	" and ":                                      " und ",
+	"Invalid password: %v\n":                     "Ungültiges Passwort: %v\n",
+	"Invalid password: %s\n":                     "Ungültiges Passwort: %s\n",
+	"Warning: %s\n":                              "Warnung: %s\n",
+	"Invalid password in record %d: %v\n":        "Ungültiges Passwort in Datensatz %d: %v\n",
+	"Warning in record %d: %s\n":                 "Warnung in Datensatz %d: %s\n",
+	"Error: %v after %d attempts\n":              "Fehler: %v nach %d Versuchen\n",
+	"Error reading password from keychain: %v\n": "Fehler beim Lesen des Passworts aus dem Schlüsselbund: %v\n",
+	"Error reading password file: %v\n":          "Fehler beim Lesen der Passwortdatei: %v\n",
+	"Error reading credential: %v\n":             "Fehler beim Lesen des Credentials: %v\n",
+	"Error reading password from stdin: %v\n":    "Fehler beim Lesen des Passworts von stdin: %v\n",
+	"Error reading password: %v\n":               "Fehler beim Lesen des Passworts: %v\n",
+
+	// Errors and subcommand messages.
+	"Calibrated iterations: %d (%v budget)\n": "Kalibrierte Iterationen: %d (Budget %v)\n",
+	"Error %v\n":                                                                        "Fehler %v\n",
+	"Error: %v\n":                                                                       "Fehler: %v\n",
+	"Error calibrating iterations: %v\n":                                                "Fehler beim Kalibrieren der Iterationen: %v\n",
+	"Error copying to clipboard: %v\n":                                                  "Fehler beim Kopieren in die Zwischenablage: %v\n",
+	"Error deriving salt: %v\n":                                                         "Fehler beim Ableiten des Salts: %v\n",
+	"Error generating passphrase: %v\n":                                                 "Fehler beim Erzeugen der Passphrase: %v\n",
+	"Error generating verifier: %v\n":                                                   "Fehler beim Erzeugen des Verifiers: %v\n",
+	"Error in record %d: %v\n":                                                          "Fehler in Datensatz %d: %v\n",
+	"Error in record %d: empty user name\n":                                             "Fehler in Datensatz %d: leerer Benutzername\n",
+	"Error in record %d: record is longer than %d bytes\n":                              "Fehler in Datensatz %d: Datensatz ist länger als %d Bytes\n",
+	"Error opening log sink: %v\n":                                                      "Fehler beim Öffnen des Protokollziels: %v\n",
+	"Error opening the controlling terminal: %v\n":                                      "Fehler beim Öffnen des steuernden Terminals: %v\n",
+	"Error reading records from stdin: %v\n":                                            "Fehler beim Lesen der Datensätze von stdin: %v\n",
+	"Error writing output: %v\n":                                                        "Fehler beim Schreiben der Ausgabe: %v\n",
+	"Error: -verifier is required":                                                      "Fehler: -verifier ist erforderlich",
+	"Error: failed to restrict process: %v\n":                                           "Fehler: Prozess konnte nicht eingeschränkt werden: %v\n",
+	"Error: password does not match the existing verifier":                              "Fehler: Passwort passt nicht zum vorhandenen Verifier",
+	"Error: password does not match the verifier":                                       "Fehler: Passwort passt nicht zum Verifier",
+	"Invalid options: %v\n":                                                             "Ungültige Optionen: %v\n",
+	"Invalid options: -apply needs a SCRAM-SHA-256 verifier, not %s\n":                  "Ungültige Optionen: -apply benötigt einen SCRAM-SHA-256-Verifier, nicht %s\n",
+	"Invalid options: -apply requires -role":                                            "Ungültige Optionen: -apply erfordert -role",
+	"Invalid options: -apply requires -upgrade":                                         "Ungültige Optionen: -apply erfordert -upgrade",
+	"Invalid options: -key-cache-size must not be negative":                             "Ungültige Optionen: -key-cache-size darf nicht negativ sein",
+	"Invalid options: refusing to lower iterations from %d to %d\n":                     "Ungültige Optionen: Iterationen werden nicht von %d auf %d gesenkt\n",
+	"Invalid options: refusing to upgrade to %d iterations, below -min-iterations %d\n": "Ungültige Optionen: keine Anhebung auf %d Iterationen, unter -min-iterations %d\n",
+	"Password matches":                                                                  "Passwort stimmt überein",
+	"Password updated for %s\n":                                                         "Passwort für %s aktualisiert\n",
+	"Re-hash it now? [y/N] ":                                                            "Jetzt neu hashen? [y/N] ",
+	"Run with -upgrade to re-hash it":                                                   "Mit -upgrade ausführen, um es neu zu hashen",
+	"The verifier uses %d iterations, below the recommended %d\n":                       "Der Verifier verwendet %d Iterationen, weniger als die empfohlenen %d\n",
+
+	// Help.
+	"SCRAM-SHA-256 Password Generator": "SCRAM-SHA-256-Passwortgenerator",
+	"USAGE:":                           "AUFRUF:",
+	"COMMANDS:":                        "BEFEHLE:",
+	"  migrate          Re-hash PostgreSQL roles still using md5 to SCRAM-SHA-256":                       "  migrate          PostgreSQL-Rollen, die noch md5 verwenden, auf SCRAM-SHA-256 umstellen",
+	"  audit            Report PostgreSQL roles with md5, missing or weak passwords":                     "  audit            PostgreSQL-Rollen mit md5-, fehlenden oder schwachen Passwörtern melden",
+	"  decode           Parse and explain SCRAM handshake messages":                                      "  decode           SCRAM-Handshake-Nachrichten zerlegen und erklären",
+	"  lint             Check stored verifiers against format and policy rules":                          "  lint             Gespeicherte Verifier gegen Format- und Richtlinienregeln prüfen",
+	"  serve -mock      Run a mock SCRAM server for testing client implementations":                      "  serve -mock      Mock-SCRAM-Server zum Testen von Client-Implementierungen starten",
+	"  prove            Recompute ClientProof and ServerSignature from a captured exchange":              "  prove            ClientProof und ServerSignature aus einem mitgeschnittenen Austausch neu berechnen",
+	"  conformance      Run SCRAM handshake scenarios against a live server":                             "  conformance      SCRAM-Handshake-Szenarien gegen einen laufenden Server ausführen",
+	"  bootstrap        Write SQL, userlist and Kubernetes secrets for a manifest of users":              "  bootstrap        SQL, userlist und Kubernetes-Secrets für ein Benutzermanifest schreiben",
+	"  rotate           Check a password against its verifier and re-hash it with a fresh salt":          "  rotate           Passwort gegen seinen Verifier prüfen und mit neuem Salt neu hashen",
+	"  verify           Check a password against its verifier, offering to upgrade low iteration counts": "  verify           Passwort gegen seinen Verifier prüfen und niedrige Iterationszahlen anheben",
+	"  docs man         Write roff man pages for the tool and every command":                             "  docs man         Roff-Manpages für das Werkzeug und alle Befehle schreiben",
+	"  self-update      Replace this binary with the latest signed release":                              "  self-update      Dieses Programm durch das neueste signierte Release ersetzen",
+	"  version          Show version and FIPS 140-3 status":                                              "  version          Version und FIPS-140-3-Status anzeigen",
+	"OPTIONS:": "OPTIONEN:",
+	"  -stdin           Read password from stdin instead of prompting":                            "  -stdin           Passwort von stdin lesen statt nachzufragen",
+	"  -batch           Read one password per line from stdin and print a result for each":        "  -batch           Ein Passwort pro Zeile von stdin lesen und für jedes ein Ergebnis ausgeben",
+	"  -0               With -batch, read NUL-terminated passwords (as from find -print0);":       "  -0               Mit -batch NUL-terminierte Passwörter lesen (wie von find -print0);",
+	"                   newlines and other control characters are still rejected":                 "                   Zeilenumbrüche und andere Steuerzeichen werden weiterhin abgelehnt",
+	"  -print0          With -batch, end each result with NUL instead of a newline":               "  -print0          Mit -batch jedes Ergebnis mit NUL statt Zeilenumbruch beenden",
+	"  -json-records    With -batch, read JSON records that override -i, -format and -role":       "  -json-records    Mit -batch JSON-Datensätze lesen, die -i, -format und -role überschreiben",
+	"  -tty             Prompt on the controlling terminal; with -batch, stdin lists users":       "  -tty             Auf dem steuernden Terminal abfragen; mit -batch listet stdin Benutzer",
+	"  -confirm         Prompt twice and require both entries to match":                           "  -confirm         Zweimal nachfragen und übereinstimmende Eingaben verlangen",
+	"  -attempts N      Number of tries allowed when -confirm entries differ (default: 3)":        "  -attempts N      Anzahl der Versuche, wenn die -confirm-Eingaben abweichen (Standard: 3)",
+	"  -tui             Interactive mode with strength meter and guided choices":                  "  -tui             Interaktiver Modus mit Stärkeanzeige und geführter Auswahl",
+	"  -max-length N    Maximum password length in bytes (default: 1024)":                         "  -max-length N    Maximale Passwortlänge in Bytes (Standard: 1024)",
+	"  -prompt TEXT     Text shown when prompting for the password (default: \"Password: \")":     "  -prompt TEXT     Text für die Passwortabfrage (Standard: \"Password: \")",
+	"  -echo MODE       Show nothing (none), an asterisk per character (mask), or briefly":        "  -echo MODE       Nichts anzeigen (none), ein Sternchen je Zeichen (mask) oder kurz",
+	"                   the last character typed (reveal-last) (default: none)":                   "                   das zuletzt getippte Zeichen (reveal-last) (Standard: none)",
+	"  -report          Print the estimated cost of cracking the result offline to stderr":        "  -report          Geschätzte Kosten eines Offline-Angriffs auf das Ergebnis auf stderr ausgeben",
+	"  -strict          Treat password warnings (bidi controls, confusable scripts) as errors":    "  -strict          Passwortwarnungen (Bidi-Steuerzeichen, verwechselbare Schriften) als Fehler behandeln",
+	"  -keychain ITEM   Read password from the OS keychain (macOS Keychain, Windows":              "  -keychain ITEM   Passwort aus dem Schlüsselbund des Systems lesen (macOS-Schlüsselbund,",
+	"                   Credential Manager, libsecret or KWallet)":                                "                   Windows-Anmeldeinformationsverwaltung, libsecret oder KWallet)",
+	"  -password-file F Read password from the first line of file F":                              "  -password-file F Passwort aus der ersten Zeile der Datei F lesen",
+	"  -gpg             Decrypt -password-file with gpg (e.g. a pass/gopass entry)":               "  -gpg             -password-file mit gpg entschlüsseln (z. B. ein pass/gopass-Eintrag)",
+	"  -credential NAME Read password from systemd credential NAME ($CREDENTIALS_DIRECTORY)":      "  -credential NAME Passwort aus dem systemd-Credential NAME lesen ($CREDENTIALS_DIRECTORY)",
+	"                   Build the verifier from a precomputed SaltedPassword; needs -salt and -i": "                   Verifier aus einem vorab berechneten SaltedPassword bilden; erfordert -salt und -i",
+	"  -salt B64        Base64 salt the -salted-password was derived with":                        "  -salt B64        Base64-Salt, mit dem -salted-password abgeleitet wurde",
+	"  -passphrase SPEC Generate a diceware passphrase and print it before its verifier;":         "  -passphrase SPEC Diceware-Passphrase erzeugen und vor ihrem Verifier ausgeben;",
+	"                   SPEC is words=N[,sep=S] (default: words=6, space separated)":              "                   SPEC ist words=N[,sep=S] (Standard: words=6, durch Leerzeichen getrennt)",
+	"  -wordlist FILE   Wordlist for -passphrase (default: EFF's large wordlist, built in)":       "  -wordlist FILE   Wortliste für -passphrase (Standard: die eingebaute große Wortliste der EFF)",
+	"  -salt-from-key   Derive the salt from -key-file and -user with HKDF, so re-runs":           "  -salt-from-key   Salt mit HKDF aus -key-file und -user ableiten, sodass erneute Läufe",
+	"                   give the same verifier":                                                   "                   denselben Verifier ergeben",
+	"  -key-file FILE   Provisioning key for -salt-from-key (at least 32 bytes)":                  "  -key-file FILE   Provisionierungsschlüssel für -salt-from-key (mindestens 32 Bytes)",
+	"  -user NAME       User the derived salt belongs to (default: -role)":                        "  -user NAME       Benutzer, zu dem das abgeleitete Salt gehört (Standard: -role)",
+	"  -h, -help        Show this help message":                                                   "  -h, -help        Diese Hilfe anzeigen",
+	"  -lang LANG       Language for prompts, help and messages: en, de, es or fr":                "  -lang LANG       Sprache für Eingabeaufforderungen, Hilfe und Meldungen: en, de, es oder fr",
+	"                   (default: from $LC_ALL, $LC_MESSAGES or $LANG)":                           "                   (Standard: aus $LC_ALL, $LC_MESSAGES oder $LANG)",
+	"  -i, -iterations  Number of PBKDF2 iterations (default: 4096), or auto[:duration]":          "  -i, -iterations  Anzahl der PBKDF2-Iterationen (Standard: 4096) oder auto[:Dauer],",
+	"                   to fit a time budget (default duration: 100ms)":                           "                   um ein Zeitbudget auszuschöpfen (Standarddauer: 100ms)",
+	"  -terraform       Run as a Terraform external data source (JSON on stdin)":                  "  -terraform       Als externe Terraform-Datenquelle laufen (JSON auf stdin)",
+	"  -ansible FILE    Run as an Ansible module reading JSON arguments from FILE":                "  -ansible FILE    Als Ansible-Modul laufen und JSON-Argumente aus FILE lesen",
+	"  -format FORMAT   Output format: scram, pg-md5, both, rabbitmq, ejabberd,":                  "  -format FORMAT   Ausgabeformat: scram, pg-md5, both, rabbitmq, ejabberd,",
+	"                   prosody, dovecot, yaml or json (default: scram)":                          "                   prosody, dovecot, yaml oder json (Standard: scram)",
+	"  -formats LIST    Print several formats from one password as a JSON document; LIST is":      "  -formats LIST    Mehrere Formate aus einem Passwort als JSON-Dokument ausgeben; LIST ist",
+	"                   comma-separated from postgres, pg-md5, kafka, mongodb, dovecot,":          "                   eine kommagetrennte Auswahl aus postgres, pg-md5, kafka, mongodb, dovecot,",
+	"                   ejabberd, prosody and rabbitmq":                                           "                   ejabberd, prosody und rabbitmq",
+	"  -role NAME       PostgreSQL role name, required for pg-md5 output":                         "  -role NAME       Name der PostgreSQL-Rolle, für pg-md5-Ausgabe erforderlich",
+	"  -target TARGET   Target database: postgres or cockroach (default: postgres)":               "  -target TARGET   Zieldatenbank: postgres oder cockroach (Standard: postgres)",
+	"  -apply           Set the password of -role on the database at -dsn":                        "  -apply           Passwort von -role in der Datenbank unter -dsn setzen",
+	"  -dsn URL         Connection URL for -apply (default: $DATABASE_URL)":                       "  -dsn URL         Verbindungs-URL für -apply (Standard: $DATABASE_URL)",
+	"  -fips            Refuse to run unless FIPS 140-3 mode is enabled":                          "  -fips            Nur im FIPS-140-3-Modus laufen",
+	"  -output FILE     Write the result to FILE (mode 0600) instead of stdout":                   "  -output FILE     Ergebnis in FILE (Modus 0600) statt auf stdout schreiben",
+	"  -force           Allow -output to replace an existing file":                                "  -force           Erlauben, dass -output eine vorhandene Datei ersetzt",
+	"  -copy            Copy the result to the clipboard instead of printing it":                  "  -copy            Ergebnis in die Zwischenablage kopieren statt es auszugeben",
+	"  -copy-timeout D  Clear the clipboard after D (default: 30s, 0 to keep)":                    "  -copy-timeout D  Zwischenablage nach D leeren (Standard: 30s, 0 zum Behalten)",
+	"  -log-sink SINK   Also log credential operations to journald, syslog (local),":              "  -log-sink SINK   Anmeldedaten-Vorgänge zusätzlich an journald, syslog (lokal),",
+	"                   syslog://host:port (UDP) or syslog+tcp://host:port":                       "                   syslog://host:port (UDP) oder syslog+tcp://host:port protokollieren",
+	"EXAMPLES:": "BEISPIELE:",
+	"  %s                    # Prompt for password\n":              "  %s                    # Passwort abfragen\n",
+	"  echo 'mypass' | %s -stdin  # Read from stdin\n":             "  echo 'mypass' | %s -stdin  # Von stdin lesen\n",
+	"  %s -i 8192               # Custom iterations\n":             "  %s -i 8192               # Eigene Iterationszahl\n",
+	"  %s -format both -role app  # SCRAM and legacy md5 hashes\n": "  %s -format both -role app  # SCRAM- und alte md5-Hashes\n",
+	"INSTALLATION:": "INSTALLATION:",
+
+	// Errors.
+	"no arguments file given":         "keine Argumentdatei angegeben",
+	"failed to read arguments: %w":    "Argumente konnten nicht gelesen werden: %w",
+	"arguments file exceeds %d bytes": "Argumentdatei überschreitet %d Bytes",
+	"failed to decode arguments: %w":  "Argumente konnten nicht dekodiert werden: %w",
+	"invalid iterations %q: %w":       "ungültige Iterationszahl %q: %w",
+	"invalid password: %w":            "ungültiges Passwort: %w",
+	"failed to list roles: %w":        "Rollen konnten nicht aufgelistet werden: %w",
+	"-json-records requires -batch":   "-json-records erfordert -batch",
+	"-0 requires -batch":              "-0 erfordert -batch",
+	"-print0 requires -batch":         "-print0 erfordert -batch",
+	"-batch reads passwords from stdin and cannot be combined with another password source":                          "-batch liest Passwörter von stdin und kann nicht mit einer anderen Passwortquelle kombiniert werden",
+	"-batch cannot be combined with -apply, -copy or -target cockroach":                                              "-batch kann nicht mit -apply, -copy oder -target cockroach kombiniert werden",
+	"-batch -salt-from-key requires -tty or -json-records, so that each record names the user a salt is derived for": "-batch -salt-from-key erfordert -tty oder -json-records, damit jeder Datensatz den Benutzer nennt, für den ein Salt abgeleitet wird",
+	"invalid JSON record: %w":                                                          "ungültiger JSON-Datensatz: %w",
+	"invalid JSON record: unexpected data after the object":                            "ungültiger JSON-Datensatz: unerwartete Daten nach dem Objekt",
+	"the password field cannot be used with -tty, which prompts for it":                "das Feld password kann nicht mit -tty verwendet werden, das danach fragt",
+	"the user field is required with -tty":                                             "das Feld user ist mit -tty erforderlich",
+	"the password field is required":                                                   "das Feld password ist erforderlich",
+	"the user field is required with -salt-from-key":                                   "das Feld user ist mit -salt-from-key erforderlich",
+	"iterations must be at least 1":                                                    "die Iterationszahl muss mindestens 1 sein",
+	"-salt-from-key only supports -format scram, both, yaml or json":                   "-salt-from-key unterstützt nur -format scram, both, yaml oder json",
+	"unknown mechanism %q (want SCRAM-SHA-1, SCRAM-SHA-256 or SCRAM-SHA-512)":          "unbekannter Mechanismus %q (erwartet SCRAM-SHA-1, SCRAM-SHA-256 oder SCRAM-SHA-512)",
+	"mechanism %s only applies to -format scram or both":                               "Mechanismus %s gilt nur für -format scram oder both",
+	"-manifest is required":                                                            "-manifest ist erforderlich",
+	"user %s: %w":                                                                      "Benutzer %s: %w",
+	"failed to write %s: %w":                                                           "%s konnte nicht geschrieben werden: %w",
+	"failed to read manifest: %w":                                                      "Manifest konnte nicht gelesen werden: %w",
+	"manifest exceeds %d bytes":                                                        "Manifest überschreitet %d Bytes",
+	"failed to parse manifest: %w":                                                     "Manifest konnte nicht geparst werden: %w",
+	"failed to decode manifest: %w":                                                    "Manifest konnte nicht dekodiert werden: %w",
+	"manifest lists no users":                                                          "Manifest enthält keine Benutzer",
+	"%q is not a valid Kubernetes namespace":                                           "%q ist kein gültiger Kubernetes-Namespace",
+	"user %d has no name":                                                              "Benutzer %d hat keinen Namen",
+	"user %s is listed twice":                                                          "Benutzer %s ist doppelt aufgeführt",
+	"user %s sets both password and generate":                                          "Benutzer %s setzt sowohl password als auch generate",
+	"user %s: invalid password: %w":                                                    "Benutzer %s: ungültiges Passwort: %w",
+	"user %s: iterations must be at least 1":                                           "Benutzer %s: die Iterationszahl muss mindestens 1 sein",
+	"user %s has no targets":                                                           "Benutzer %s hat keine Ziele",
+	"user %s: unknown target %q (use postgres, cockroach, pgbouncer or kubernetes)":    "Benutzer %s: unbekanntes Ziel %q (postgres, cockroach, pgbouncer oder kubernetes verwenden)",
+	"user %s: target %s is listed twice":                                               "Benutzer %s: Ziel %s ist doppelt aufgeführt",
+	"user %s: %q is not a valid Kubernetes secret name":                                "Benutzer %s: %q ist kein gültiger Name für ein Kubernetes-Secret",
+	"user %s: secret %s is used twice":                                                 "Benutzer %s: Secret %s wird doppelt verwendet",
+	"failed to generate password: %w":                                                  "Passwort konnte nicht erzeugt werden: %w",
+	"auto duration must be positive":                                                   "die Dauer für auto muss positiv sein",
+	"expected a number or auto[:duration]":                                             "erwartet eine Zahl oder auto[:Dauer]",
+	"no clipboard tool found for %s":                                                   "kein Zwischenablage-Werkzeug für %s gefunden",
+	"failed to write clipboard: %w":                                                    "Zwischenablage konnte nicht beschrieben werden: %w",
+	"invalid -host: %w":                                                                "ungültiger -host: %w",
+	"failed to connect to %s: %w":                                                      "Verbindung zu %s fehlgeschlagen: %w",
+	"reading from server: %w":                                                          "Lesen vom Server: %w",
+	"$CREDENTIALS_DIRECTORY is not set; run under systemd with LoadCredential=%s":      "$CREDENTIALS_DIRECTORY ist nicht gesetzt; unter systemd mit LoadCredential=%s ausführen",
+	"invalid credential name %q":                                                       "ungültiger Credential-Name %q",
+	"expected user or user:credential":                                                 "erwartet Benutzer oder Benutzer:Credential",
+	"unknown -echo mode %q (want none, mask or reveal-last)":                           "unbekannter -echo-Modus %q (erwartet none, mask oder reveal-last)",
+	"-echo %s requires an interactive terminal":                                        "-echo %s erfordert ein interaktives Terminal",
+	"failed to set up terminal: %w":                                                    "Terminal konnte nicht eingerichtet werden: %w",
+	"unsupported language %q (available: %s)":                                          "nicht unterstützte Sprache %q (verfügbar: %s)",
+	"keychain item %q not found: %w":                                                   "Schlüsselbund-Eintrag %q nicht gefunden: %w",
+	"keychain item %q not found in libsecret or KWallet":                               "Schlüsselbund-Eintrag %q weder in libsecret noch in KWallet gefunden",
+	"keychain access is not supported on this platform":                                "Schlüsselbund-Zugriff wird auf dieser Plattform nicht unterstützt",
+	"-log-sink %s: %w":                                                                 "-log-sink %s: %w",
+	"-log-sink must be journald, syslog, syslog://host:port or syslog+tcp://host:port": "-log-sink muss journald, syslog, syslog://host:port oder syslog+tcp://host:port sein",
+	"no local syslog socket found":                                                     "kein lokaler Syslog-Socket gefunden",
+	"-max-length must be at least 1":                                                   "-max-length muss mindestens 1 sein",
+	"-i must be at least 1":                                                            "-i muss mindestens 1 sein",
+	"-output and -copy cannot be combined":                                             "-output und -copy können nicht kombiniert werden",
+	"-gpg requires -password-file":                                                     "-gpg erfordert -password-file",
+	"generating %s: %w":                                                                "Erzeugen von %s: %w",
+	"generating %s credentials: %w":                                                    "Erzeugen der %s-Zugangsdaten: %w",
+	"generating SCRAM-SHA-256: %w":                                                     "Erzeugen von SCRAM-SHA-256: %w",
+	"generating Dovecot password: %w":                                                  "Erzeugen des Dovecot-Passworts: %w",
+	"generating RabbitMQ hash: %w":                                                     "Erzeugen des RabbitMQ-Hashes: %w",
+	"-format %s cannot be combined with -passphrase":                                   "-format %s kann nicht mit -passphrase kombiniert werden",
+	"-format %s requires -role":                                                        "-format %s erfordert -role",
+	"unknown format %q":                                                                "unbekanntes Format %q",
+	"failed to read password: %w":                                                      "Passwort konnte nicht gelesen werden: %w",
+	"%d roles could not be migrated":                                                   "%d Rollen konnten nicht migriert werden",
+	"failed to read password_encryption: %w":                                           "password_encryption konnte nicht gelesen werden: %w",
+	"failed to read role settings: %w":                                                 "Rolleneinstellungen konnten nicht gelesen werden: %w",
+	"failed to open CSV: %w":                                                           "CSV konnte nicht geöffnet werden: %w",
+	"failed to read CSV: %w":                                                           "CSV konnte nicht gelesen werden: %w",
+	"unknown format %q in -formats (want %s)":                                          "unbekanntes Format %q in -formats (erwartet %s)",
+	"-formats cannot be combined with -format":                                         "-formats kann nicht mit -format kombiniert werden",
+	"-formats pg-md5 requires -role":                                                   "-formats pg-md5 erfordert -role",
+	"-formats cannot be combined with -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform or -ansible": "-formats kann nicht mit -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform oder -ansible kombiniert werden",
+	"generating prosody credentials: %w":                                                           "Erzeugen der Prosody-Zugangsdaten: %w",
+	"%s already exists (use -force to overwrite)":                                                  "%s existiert bereits (-force zum Überschreiben verwenden)",
+	"-wordlist requires -passphrase":                                                               "-wordlist erfordert -passphrase",
+	"-passphrase cannot be combined with another password source":                                  "-passphrase kann nicht mit einer anderen Passwortquelle kombiniert werden",
+	"-passphrase: expected key=value, got %q":                                                      "-passphrase: erwartet Schlüssel=Wert, erhalten %q",
+	"-passphrase: words must be a positive number":                                                 "-passphrase: words muss eine positive Zahl sein",
+	"-passphrase: unknown setting %q":                                                              "-passphrase: unbekannte Einstellung %q",
+	"this build has no built-in wordlist; pass -wordlist, or run go generate and rebuild":          "dieser Build hat keine eingebaute Wortliste; -wordlist angeben oder go generate ausführen und neu bauen",
+	"built-in wordlist: %w":                                                                        "eingebaute Wortliste: %w",
+	"has %d lines, want %d":                                                                        "hat %d Zeilen, erwartet %d",
+	"line %d is not %s followed by a tab and a new word":                                           "Zeile %d ist nicht %s gefolgt von einem Tabulator und einem neuen Wort",
+	"%s has %d distinct words, want at least %d":                                                   "%s hat %d verschiedene Wörter, erwartet mindestens %d",
+	"-gpg requires gpg on PATH":                                                                    "-gpg erfordert gpg im PATH",
+	"failed to run gpg: %w":                                                                        "gpg konnte nicht ausgeführt werden: %w",
+	"gpg failed to decrypt %s: %w":                                                                 "gpg konnte %s nicht entschlüsseln: %w",
+	"unsupported mechanism %q":                                                                     "nicht unterstützter Mechanismus %q",
+	"-client-nonce and -server-nonce are required":                                                 "-client-nonce und -server-nonce sind erforderlich",
+	"-salt must be non-empty base64":                                                               "-salt muss nicht leeres Base64 sein",
+	"invalid -cbind-data: %w":                                                                      "ungültiges -cbind-data: %w",
+	"-report cannot be combined with -batch, -salted-password, -terraform or -ansible":             "-report kann nicht mit -batch, -salted-password, -terraform oder -ansible kombiniert werden",
+	"-salt requires -salted-password":                                                              "-salt erfordert -salted-password",
+	"-salted-password requires -salt":                                                              "-salted-password erfordert -salt",
+	"-salted-password requires the -i it was derived with":                                         "-salted-password erfordert das -i, mit dem es abgeleitet wurde",
+	"-salted-password only supports -format scram":                                                 "-salted-password unterstützt nur -format scram",
+	"-salted-password cannot be combined with another password source":                             "-salted-password kann nicht mit einer anderen Passwortquelle kombiniert werden",
+	"invalid -salted-password: %w":                                                                 "ungültiges -salted-password: %w",
+	"-key-file requires -salt-from-key":                                                            "-key-file erfordert -salt-from-key",
+	"-salt-from-key requires -key-file":                                                            "-salt-from-key erfordert -key-file",
+	"-salt-from-key requires -user":                                                                "-salt-from-key erfordert -user",
+	"-salt-from-key cannot be combined with -salted-password, -passphrase, -terraform or -ansible": "-salt-from-key kann nicht mit -salted-password, -passphrase, -terraform oder -ansible kombiniert werden",
+	"%s: key must be at least %d bytes":                                                            "%s: Schlüssel muss mindestens %d Bytes lang sein",
+	"release %s has no %s":                                                                         "Release %s enthält kein %s",
+	"%s does not match its checksum in %s":                                                         "%s stimmt nicht mit seiner Prüfsumme in %s überein",
+	"%s: larger than %d bytes":                                                                     "%s: größer als %d Bytes",
+	"malformed checksum for %s":                                                                    "fehlerhafte Prüfsumme für %s",
+	"%s lists no checksum for %s":                                                                  "%s enthält keine Prüfsumme für %s",
+	"invalid public key: %w":                                                                       "ungültiger öffentlicher Schlüssel: %w",
+	"invalid public key: unsupported algorithm %q":                                                 "ungültiger öffentlicher Schlüssel: nicht unterstützter Algorithmus %q",
+	"malformed signature":                                                                          "fehlerhafte Signatur",
+	"malformed signature: %w":                                                                      "fehlerhafte Signatur: %w",
+	"signed with a different key":                                                                  "mit einem anderen Schlüssel signiert",
+	"unsupported signature algorithm %q":                                                           "nicht unterstützter Signaturalgorithmus %q",
+	"signature verification failed":                                                                "Signaturprüfung fehlgeschlagen",
+	"trusted comment signature verification failed":                                                "Signaturprüfung des vertrauenswürdigen Kommentars fehlgeschlagen",
+	"got %d bytes, want %d":                                                                        "%d Bytes erhalten, erwartet %d",
+	"expected name:password":                                                                       "erwartet Name:Passwort",
+	"serve requires -mock, the only server it runs":                                                "serve erfordert -mock, den einzigen Server, den es betreibt",
+	"-sandbox-write requires -sandbox":                                                             "-sandbox-write erfordert -sandbox",
+	"-socket-mode and -socket-owner require a unix: -listen address":                               "-socket-mode und -socket-owner erfordern eine unix:-Adresse für -listen",
+	"at least one -user or -user-credential is required":                                           "mindestens ein -user oder -user-credential ist erforderlich",
+	"-max-derivations, -max-queue, -queue-timeout and -key-cache-size must not be negative":        "-max-derivations, -max-queue, -queue-timeout und -key-cache-size dürfen nicht negativ sein",
+	"-key-cache-size cannot be used with -pkcs11-module":                                           "-key-cache-size kann nicht mit -pkcs11-module verwendet werden",
+	"-pkcs11-slot and -pkcs11-pin-file require -pkcs11-module":                                     "-pkcs11-slot und -pkcs11-pin-file erfordern -pkcs11-module",
+	"opening log sink: %w":                                                                         "Öffnen des Log-Ziels: %w",
+	"reading -pkcs11-pin-file: %w":                                                                 "Lesen von -pkcs11-pin-file: %w",
+	"failed to create Landlock ruleset: %w":                                                        "Landlock-Regelsatz konnte nicht erstellt werden: %w",
+	"failed to set no_new_privs: %w":                                                               "no_new_privs konnte nicht gesetzt werden: %w",
+	"failed to enter Landlock domain: %w":                                                          "Landlock-Domäne konnte nicht betreten werden: %w",
+	"-sandbox-write %s: %w":                                                                        "-sandbox-write %s: %w",
+	"seccomp filtering is not supported on %s":                                                     "seccomp-Filterung wird auf %s nicht unterstützt",
+	"failed to install seccomp filter: %w":                                                         "seccomp-Filter konnte nicht installiert werden: %w",
+	"-sandbox is only supported on Linux":                                                          "-sandbox wird nur unter Linux unterstützt",
+	"-socket-mode and -socket-owner are not supported on %s":                                       "-socket-mode und -socket-owner werden auf %s nicht unterstützt",
+	"-socket-mode and -socket-owner do not apply to abstract socket %s":                            "-socket-mode und -socket-owner gelten nicht für den abstrakten Socket %s",
+	"-socket-mode: expected octal permissions such as 0660, got %q":                                "-socket-mode: erwartet oktale Berechtigungen wie 0660, erhalten %q",
+	"-socket-owner: %w":                                                                            "-socket-owner: %w",
+	"user %s has non-numeric id %q":                                                                "Benutzer %s hat die nicht numerische ID %q",
+	"group %s has non-numeric id %q":                                                               "Gruppe %s hat die nicht numerische ID %q",
+	"-format %s is not supported by CockroachDB":                                                   "-format %s wird von CockroachDB nicht unterstützt",
+	"-target cockroach requires -role":                                                             "-target cockroach erfordert -role",
+	"unknown target %q":                                                                            "unbekanntes Ziel %q",
+	"-apply requires -role":                                                                        "-apply erfordert -role",
+	"-apply only supports -format scram":                                                           "-apply unterstützt nur -format scram",
+	"-apply cannot be combined with -copy or -output":                                              "-apply kann nicht mit -copy oder -output kombiniert werden",
+	"failed to set password for %s: %w":                                                            "Passwort für %s konnte nicht gesetzt werden: %w",
+	"failed to decode query: %w":                                                                   "Abfrage konnte nicht dekodiert werden: %w",
+	"salt must be base64 of at least %d bytes":                                                     "Salt muss Base64 von mindestens %d Bytes sein",
+	"-tty prompts for the password and cannot be combined with another password source":            "-tty fragt nach dem Passwort und kann nicht mit einer anderen Passwortquelle kombiniert werden",
+	"-user cannot be combined with -batch -tty, which reads user names from stdin":                 "-user kann nicht mit -batch -tty kombiniert werden, das Benutzernamen von stdin liest",
+	"-tui requires an interactive terminal":                                                        "-tui erfordert ein interaktives Terminal",
+	"FIPS 140-3 mode is not enabled; run with GODEBUG=fips140=on or build with GOFIPS140=v1.0.0":   "FIPS-140-3-Modus ist nicht aktiviert; mit GODEBUG=fips140=on ausführen oder mit GOFIPS140=v1.0.0 bauen",
+	"format %s is not permitted with -fips":                                                        "Format %s ist mit -fips nicht zulässig",
+	"line %d: tabs are not allowed for indentation":                                                "Zeile %d: Tabulatoren sind zur Einrückung nicht erlaubt",
+	"line %d: unexpected indentation":                                                              "Zeile %d: unerwartete Einrückung",
+	"line %d: expected a sequence item":                                                            "Zeile %d: Listeneintrag erwartet",
+	"line %d: expected key: value":                                                                 "Zeile %d: Schlüssel: Wert erwartet",
+	"line %d: duplicate key %q":                                                                    "Zeile %d: doppelter Schlüssel %q",
+	"line %d: invalid double-quoted string %s":                                                     "Zeile %d: ungültige Zeichenkette in doppelten Anführungszeichen %s",
+	"line %d: invalid single-quoted string %s":                                                     "Zeile %d: ungültige Zeichenkette in einfachen Anführungszeichen %s",
+	"line %d: unterminated flow sequence":                                                          "Zeile %d: nicht abgeschlossene Flow-Sequenz",
+	"line %d: unsupported YAML syntax %q":                                                          "Zeile %d: nicht unterstützte YAML-Syntax %q",
+	"Copied to clipboard.":                                                                         "In die Zwischenablage kopiert.",
+	"Copied to clipboard; clearing in %s (Ctrl-C to clear now).\n":                                 "In die Zwischenablage kopiert; wird in %s geleert (Strg-C leert sofort).\n",
+	"Clipboard cleared.":                                                                           "Zwischenablage geleert.",
+
+	// Subcommand options.
+	"Usage of %s:\n": "Verwendung von %s:\n",
+	"Usage: scram-sha-256 docs man [-out-dir DIR]":                                                           "Verwendung: scram-sha-256 docs man [-out-dir VERZEICHNIS]",
+	"PostgreSQL connection URL (default: $DATABASE_URL, then PG* variables)":                                 "PostgreSQL-Verbindungs-URL (Standard: $DATABASE_URL, dann PG*-Variablen)",
+	"Report SCRAM verifiers with fewer iterations than this":                                                 "SCRAM-Verifier mit weniger Iterationen als dieser Wert melden",
+	"Also audit roles that cannot log in":                                                                    "Auch Rollen prüfen, die sich nicht anmelden können",
+	"JSON or YAML manifest of users (- for stdin)":                                                           "JSON- oder YAML-Manifest der Benutzer (- für stdin)",
+	"Directory to write the artifacts to":                                                                    "Verzeichnis, in das die Artefakte geschrieben werden",
+	"Overwrite existing artifacts":                                                                           "Vorhandene Artefakte überschreiben",
+	"Read password from stdin instead of prompting":                                                          "Passwort von stdin lesen statt danach zu fragen",
+	"Server to test, as host:port":                                                                           "Zu testender Server, als host:port",
+	"User to authenticate as":                                                                                "Benutzer, als der authentifiziert wird",
+	"Wire protocol: postgres, or scram for the line protocol of serve -mock":                                 "Protokoll: postgres, oder scram für das Zeilenprotokoll von serve -mock",
+	"Database to connect to (postgres only)":                                                                 "Datenbank, mit der verbunden wird (nur postgres)",
+	"TLS mode: disable, prefer, require or verify-full (postgres only)":                                      "TLS-Modus: disable, prefer, require oder verify-full (nur postgres)",
+	"Messages are base64-encoded, as in SMTP, IMAP or driver logs":                                           "Nachrichten sind Base64-kodiert, wie in SMTP, IMAP oder Treiber-Logs",
+	"Read verifiers from this file (- for stdin), e.g. a pg_authid dump":                                     "Verifier aus dieser Datei lesen (- für stdin), z. B. ein pg_authid-Dump",
+	"Smallest acceptable iteration count":                                                                    "Kleinste zulässige Iterationszahl",
+	"Largest acceptable iteration count (0 for no limit)":                                                    "Größte zulässige Iterationszahl (0 für keine Grenze)",
+	"Smallest acceptable salt length in bytes":                                                               "Kleinste zulässige Salt-Länge in Bytes",
+	"Comma-separated list of acceptable mechanisms":                                                          "Kommagetrennte Liste zulässiger Mechanismen",
+	"CSV file of role,password rows instead of prompting":                                                    "CSV-Datei mit Zeilen role,password statt Abfrage",
+	"Number of PBKDF2 iterations":                                                                            "Anzahl der PBKDF2-Iterationen",
+	"Report what would change without altering any role":                                                     "Melden, was sich ändern würde, ohne eine Rolle zu verändern",
+	"SCRAM mechanism used in the exchange":                                                                   "Im Austausch verwendeter SCRAM-Mechanismus",
+	"Username sent in client-first (n=)":                                                                     "In client-first gesendeter Benutzername (n=)",
+	"Client nonce from client-first (r=)":                                                                    "Client-Nonce aus client-first (r=)",
+	"Nonce from server-first (r=), with or without the client nonce prefix":                                  "Nonce aus server-first (r=), mit oder ohne Präfix der Client-Nonce",
+	"Base64 salt from server-first (s=)":                                                                     "Base64-Salt aus server-first (s=)",
+	"Iteration count from server-first (i=)":                                                                 "Iterationszahl aus server-first (i=)",
+	"GS2 header the client sent":                                                                             "Vom Client gesendeter GS2-Header",
+	"Hex channel binding data for p= exchanges":                                                              "Hexadezimale Channel-Binding-Daten für p=-Austausche",
+	"Captured client proof (p=) to compare against":                                                          "Mitgeschnittener Client-Beweis (p=) zum Vergleich",
+	"Captured server signature (v=) to compare against":                                                      "Mitgeschnittene Server-Signatur (v=) zum Vergleich",
+	"Existing verifier the password must match":                                                              "Vorhandener Verifier, zu dem das Passwort passen muss",
+	"Iteration count for the new verifier, or auto[:duration] (default: the old count, at least 4096)":       "Iterationszahl für den neuen Verifier oder auto[:Dauer] (Standard: die alte Zahl, mindestens 4096)",
+	"Also log the rotation to journald, syslog or syslog[+tcp]://host:port":                                  "Die Rotation auch an journald, syslog oder syslog[+tcp]://host:port protokollieren",
+	"Only report whether a newer release is available":                                                       "Nur melden, ob ein neueres Release verfügbar ist",
+	"Minisign public key the release checksums must be signed with":                                          "Öffentlicher Minisign-Schlüssel, mit dem die Release-Prüfsummen signiert sein müssen",
+	"Address to listen on; prefix with unix: for a unix socket":                                              "Adresse, auf der gelauscht wird; mit unix: für einen Unix-Socket",
+	"Permissions of the unix socket in octal, such as 0660 (default 0600)":                                   "Berechtigungen des Unix-Sockets in oktal, etwa 0660 (Standard 0600)",
+	"Owner of the unix socket as user, user:group or :group":                                                 "Eigentümer des Unix-Sockets als Benutzer, Benutzer:Gruppe oder :Gruppe",
+	"Run the mock SCRAM server for driver testing":                                                           "Den SCRAM-Testserver für Treibertests starten",
+	"User accepted by the mock server as name:password (repeatable)":                                         "Vom Testserver akzeptierter Benutzer als Name:Passwort (wiederholbar)",
+	"User whose password is in a systemd credential, as name or name:credential (repeatable)":                "Benutzer, dessen Passwort in einem systemd-Credential liegt, als Name oder Name:Credential (wiederholbar)",
+	"Fault to inject: none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final": "Einzuschleusender Fehler: none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final",
+	"Maximum concurrent PBKDF2 derivations (default: number of CPUs)":                                        "Höchstzahl gleichzeitiger PBKDF2-Ableitungen (Standard: Anzahl der CPUs)",
+	"Maximum conversations waiting for a derivation before e=server-busy (0 for no limit)":                   "Höchstzahl auf eine Ableitung wartender Konversationen vor e=server-busy (0 für keine Grenze)",
+	"Answer e=server-busy after waiting this long for a derivation (0 to wait)":                              "Nach so langer Wartezeit auf eine Ableitung mit e=server-busy antworten (0 zum Warten)",
+	"Keep up to this many derived keys in memory and log hit rates to -log-sink (0 disables)":                "Bis zu so viele abgeleitete Schlüssel im Speicher halten und Trefferquoten an -log-sink protokollieren (0 deaktiviert)",
+	"Forbid exec and filesystem writes using seccomp and Landlock (Linux)":                                   "exec und Schreibzugriffe aufs Dateisystem mit seccomp und Landlock verbieten (Linux)",
+	"Path that stays writable under -sandbox (repeatable)":                                                   "Pfad, der unter -sandbox beschreibbar bleibt (wiederholbar)",
+	"Also log startup and each authentication to journald, syslog or syslog[+tcp]://host:port":               "Start und jede Authentifizierung auch an journald, syslog oder syslog[+tcp]://host:port protokollieren",
+	"PKCS#11 library that derives user keys on a token, so SaltedPassword never enters process memory":       "PKCS#11-Bibliothek, die Benutzerschlüssel auf einem Token ableitet, sodass SaltedPassword nie in den Prozessspeicher gelangt",
+	"Slot ID of the token for -pkcs11-module":                                                                "Slot-ID des Tokens für -pkcs11-module",
+	"File whose first line is the user PIN for -pkcs11-module":                                               "Datei, deren erste Zeile die Benutzer-PIN für -pkcs11-module ist",
+	"Verifier to check the password against":                                                                 "Verifier, gegen den das Passwort geprüft wird",
+	"Offer an upgrade for verifiers with fewer iterations than this":                                         "Ein Upgrade für Verifier mit weniger Iterationen als diesem Wert anbieten",
+	"Iteration count for the upgraded verifier, or auto[:duration] (default: -min-iterations)":               "Iterationszahl für den aktualisierten Verifier oder auto[:Dauer] (Standard: -min-iterations)",
+	"Print a re-hashed verifier when the password matches one below -min-iterations":                         "Einen neu gehashten Verifier ausgeben, wenn das Passwort zu einem unter -min-iterations passt",
+	"With -upgrade, set the re-hashed verifier as the password of -role at -dsn instead of printing it":      "Mit -upgrade den neu gehashten Verifier als Passwort von -role auf -dsn setzen, statt ihn auszugeben",
+	"PostgreSQL role whose password -apply sets":                                                             "PostgreSQL-Rolle, deren Passwort -apply setzt",
+	"Connection URL for -apply (default: $DATABASE_URL, then PG* variables)":                                 "Verbindungs-URL für -apply (Standard: $DATABASE_URL, dann PG*-Variablen)",
+	"Also log checks and upgrades to journald, syslog or syslog[+tcp]://host:port":                           "Prüfungen und Upgrades auch an journald, syslog oder syslog[+tcp]://host:port protokollieren",
+	"Directory to write the pages to":                                                                        "Verzeichnis, in das die Seiten geschrieben werden",
+
+	// Interactive mode.
+	"SCRAM-SHA-256 Password Generator\r\n\r\n": "SCRAM-SHA-256-Passwortgenerator\r\n\r\n",
+	"\r\033[KPassword: %s  [%s%s] %s":          "\r\033[KPasswort: %s  [%s%s] %s",
+	"PBKDF2 iterations":                        "PBKDF2-Iterationen",
+	"Output format":                            "Ausgabeformat",
+	"%s (arrow keys, Enter to select)\r\n":     "%s (Pfeiltasten, Eingabe zum Auswählen)\r\n",
+	"PostgreSQL role name: ":                   "PostgreSQL-Rollenname: ",
+	"\r\nSummary\r\n":                          "\r\nZusammenfassung\r\n",
+	"  Password strength: %s\r\n":              "  Passwortstärke:    %s\r\n",
+	"  Iterations:        %d\r\n":              "  Iterationen:       %d\r\n",
+	"  Format:            %s\r\n":              "  Format:            %s\r\n",
+	"  Role:              %s\r\n":              "  Rolle:             %s\r\n",
+	"Generate? [y/N] ":                         "Erzeugen? [y/N] ",
+	"very weak":                                "sehr schwach",
+	"weak":                                     "schwach",
+	"fair":                                     "mittel",
+	"strong":                                   "stark",
+	"very strong":                              "sehr stark",
+}
@@ -0,0 +1,417 @@
+package main
+
+// catalogES holds the Spanish translations.
+var catalogES = map[string]string{
+	// Prompts and password validation.
+	"Password: ":                                  "Contraseña: ",
+	"Password for %s: ":                           "Contraseña para %s: ",
+	"Confirm password: ":                          "Confirmar contraseña: ",
+	"Passwords do not match, try again.":          "Las contraseñas no coinciden, inténtelo de nuevo.",
+	"passwords do not match":                      "las contraseñas no coinciden",
+	"password cannot be empty":                    "la contraseña no puede estar vacía",
+	"password must be valid UTF-8":                "la contraseña debe ser UTF-8 válido",
+	"password exceeds maximum length of %d bytes": "la contraseña supera la longitud máxima de %d bytes",
+	"password contains %s U+%04X at position %d":  "la contraseña contiene %s U+%04X en la posición %d",
+	"control character":                           "un carácter de control",
+	"unassigned code point":                       "un punto de código no asignado",
+	"non-ASCII control character":                 "un carácter de control no ASCII",
+	"private use character":                       "un carácter de uso privado",
+	"non-character code point":                    "un punto de código no carácter",
+	"surrogate code point":                        "un punto de código sustituto",
+	"character inappropriate for plain text":      "un carácter inadecuado para texto plano",
+	"ideographic description character":           "un carácter de descripción ideográfica",
+	"display-changing or deprecated character":    "un carácter obsoleto o que altera la visualización",
+	"tagging character":                           "un carácter de etiquetado",
+	"password contains invisible bidirectional control U+%04X at position %d":                                      "la contraseña contiene el control bidireccional invisible U+%04X en la posición %d",
+	"password mixes right-to-left and left-to-right letters, which SASLprep rejects and editors display reordered": "la contraseña mezcla letras de derecha a izquierda y de izquierda a derecha, lo que SASLprep rechaza y los editores muestran reordenado",
+	"password mixes visually confusable %s letters":                                                                "la contraseña mezcla letras %s visualmente confundibles",
+	" and ":                                      " y ",
+	"Invalid password: %v\n":                     "Contraseña no válida: %v\n",
+	"Invalid password: %s\n":                     "Contraseña no válida: %s\n",
+	"Warning: %s\n":                              "Advertencia: %s\n",
+	"Invalid password in record %d: %v\n":        "Contraseña no válida en el registro %d: %v\n",
+	"Warning in record %d: %s\n":                 "Advertencia en el registro %d: %s\n",
+	"Error: %v after %d attempts\n":              "Error: %v tras %d intentos\n",
+	"Error reading password from keychain: %v\n": "Error al leer la contraseña del llavero: %v\n",
+	"Error reading password file: %v\n":          "Error al leer el archivo de contraseña: %v\n",
+	"Error reading credential: %v\n":             "Error al leer la credencial: %v\n",
+	"Error reading password from stdin: %v\n":    "Error al leer la contraseña de stdin: %v\n",
+	"Error reading password: %v\n":               "Error al leer la contraseña: %v\n",
+
+	// Errors and subcommand messages.
+	"Calibrated iterations: %d (%v budget)\n": "Iteraciones calibradas: %d (presupuesto de %v)\n",
+	"Error %v\n":                                                                        "Error %v\n",
+	"Error: %v\n":                                                                       "Error: %v\n",
+	"Error calibrating iterations: %v\n":                                                "Error al calibrar las iteraciones: %v\n",
+	"Error copying to clipboard: %v\n":                                                  "Error al copiar al portapapeles: %v\n",
+	"Error deriving salt: %v\n":                                                         "Error al derivar la sal: %v\n",
+	"Error generating passphrase: %v\n":                                                 "Error al generar la frase de contraseña: %v\n",
+	"Error generating verifier: %v\n":                                                   "Error al generar el verificador: %v\n",
+	"Error in record %d: %v\n":                                                          "Error en el registro %d: %v\n",
+	"Error in record %d: empty user name\n":                                             "Error en el registro %d: nombre de usuario vacío\n",
+	"Error in record %d: record is longer than %d bytes\n":                              "Error en el registro %d: el registro supera los %d bytes\n",
+	"Error opening log sink: %v\n":                                                      "Error al abrir el destino de registro: %v\n",
+	"Error opening the controlling terminal: %v\n":                                      "Error al abrir la terminal de control: %v\n",
+	"Error reading records from stdin: %v\n":                                            "Error al leer los registros de stdin: %v\n",
+	"Error writing output: %v\n":                                                        "Error al escribir la salida: %v\n",
+	"Error: -verifier is required":                                                      "Error: -verifier es obligatorio",
+	"Error: failed to restrict process: %v\n":                                           "Error: no se pudo restringir el proceso: %v\n",
+	"Error: password does not match the existing verifier":                              "Error: la contraseña no coincide con el verificador existente",
+	"Error: password does not match the verifier":                                       "Error: la contraseña no coincide con el verificador",
+	"Invalid options: %v\n":                                                             "Opciones no válidas: %v\n",
+	"Invalid options: -apply needs a SCRAM-SHA-256 verifier, not %s\n":                  "Opciones no válidas: -apply necesita un verificador SCRAM-SHA-256, no %s\n",
+	"Invalid options: -apply requires -role":                                            "Opciones no válidas: -apply requiere -role",
+	"Invalid options: -apply requires -upgrade":                                         "Opciones no válidas: -apply requiere -upgrade",
+	"Invalid options: -key-cache-size must not be negative":                             "Opciones no válidas: -key-cache-size no puede ser negativo",
+	"Invalid options: refusing to lower iterations from %d to %d\n":                     "Opciones no válidas: no se reducen las iteraciones de %d a %d\n",
+	"Invalid options: refusing to upgrade to %d iterations, below -min-iterations %d\n": "Opciones no válidas: no se actualiza a %d iteraciones, por debajo de -min-iterations %d\n",
+	"Password matches":                                                                  "La contraseña coincide",
+	"Password updated for %s\n":                                                         "Contraseña actualizada para %s\n",
+	"Re-hash it now? [y/N] ":                                                            "¿Volver a calcular el hash ahora? [y/N] ",
+	"Run with -upgrade to re-hash it":                                                   "Ejecute con -upgrade para volver a calcular el hash",
+	"The verifier uses %d iterations, below the recommended %d\n":                       "El verificador usa %d iteraciones, por debajo de las %d recomendadas\n",
+
+	// Help.
+	"SCRAM-SHA-256 Password Generator": "Generador de contraseñas SCRAM-SHA-256",
+	"USAGE:":                           "USO:",
+	"COMMANDS:":                        "COMANDOS:",
+	"  migrate          Re-hash PostgreSQL roles still using md5 to SCRAM-SHA-256":                       "  migrate          Regenerar con SCRAM-SHA-256 el hash de los roles de PostgreSQL que aún usan md5",
+	"  audit            Report PostgreSQL roles with md5, missing or weak passwords":                     "  audit            Informar de roles de PostgreSQL con contraseñas md5, ausentes o débiles",
+	"  decode           Parse and explain SCRAM handshake messages":                                      "  decode           Analizar y explicar mensajes de negociación SCRAM",
+	"  lint             Check stored verifiers against format and policy rules":                          "  lint             Comprobar verificadores almacenados con reglas de formato y de política",
+	"  serve -mock      Run a mock SCRAM server for testing client implementations":                      "  serve -mock      Ejecutar un servidor SCRAM simulado para probar implementaciones de cliente",
+	"  prove            Recompute ClientProof and ServerSignature from a captured exchange":              "  prove            Recalcular ClientProof y ServerSignature a partir de un intercambio capturado",
+	"  conformance      Run SCRAM handshake scenarios against a live server":                             "  conformance      Ejecutar escenarios de negociación SCRAM contra un servidor real",
+	"  bootstrap        Write SQL, userlist and Kubernetes secrets for a manifest of users":              "  bootstrap        Escribir SQL, userlist y secretos de Kubernetes para un manifiesto de usuarios",
+	"  rotate           Check a password against its verifier and re-hash it with a fresh salt":          "  rotate           Comprobar una contraseña con su verificador y regenerar su hash con una sal nueva",
+	"  verify           Check a password against its verifier, offering to upgrade low iteration counts": "  verify           Comprobar una contraseña con su verificador y ofrecer subir iteraciones bajas",
+	"  docs man         Write roff man pages for the tool and every command":                             "  docs man         Escribir páginas de manual roff de la herramienta y de cada comando",
+	"  self-update      Replace this binary with the latest signed release":                              "  self-update      Sustituir este binario por la última versión firmada",
+	"  version          Show version and FIPS 140-3 status":                                              "  version          Mostrar la versión y el estado de FIPS 140-3",
+	"OPTIONS:": "OPCIONES:",
+	"  -stdin           Read password from stdin instead of prompting":                            "  -stdin           Leer la contraseña de stdin en lugar de pedirla",
+	"  -batch           Read one password per line from stdin and print a result for each":        "  -batch           Leer una contraseña por línea de stdin e imprimir un resultado para cada una",
+	"  -0               With -batch, read NUL-terminated passwords (as from find -print0);":       "  -0               Con -batch, leer contraseñas terminadas en NUL (como las de find -print0);",
+	"                   newlines and other control characters are still rejected":                 "                   los saltos de línea y otros caracteres de control se siguen rechazando",
+	"  -print0          With -batch, end each result with NUL instead of a newline":               "  -print0          Con -batch, terminar cada resultado con NUL en lugar de un salto de línea",
+	"  -json-records    With -batch, read JSON records that override -i, -format and -role":       "  -json-records    Con -batch, leer registros JSON que sustituyen -i, -format y -role",
+	"  -tty             Prompt on the controlling terminal; with -batch, stdin lists users":       "  -tty             Preguntar en el terminal de control; con -batch, stdin lista usuarios",
+	"  -confirm         Prompt twice and require both entries to match":                           "  -confirm         Pedirla dos veces y exigir que ambas entradas coincidan",
+	"  -attempts N      Number of tries allowed when -confirm entries differ (default: 3)":        "  -attempts N      Número de intentos permitidos cuando las entradas de -confirm difieren (predeterminado: 3)",
+	"  -tui             Interactive mode with strength meter and guided choices":                  "  -tui             Modo interactivo con medidor de robustez y opciones guiadas",
+	"  -max-length N    Maximum password length in bytes (default: 1024)":                         "  -max-length N    Longitud máxima de la contraseña en bytes (predeterminado: 1024)",
+	"  -prompt TEXT     Text shown when prompting for the password (default: \"Password: \")":     "  -prompt TEXT     Texto mostrado al pedir la contraseña (predeterminado: \"Password: \")",
+	"  -echo MODE       Show nothing (none), an asterisk per character (mask), or briefly":        "  -echo MODE       No mostrar nada (none), un asterisco por carácter (mask) o brevemente",
+	"                   the last character typed (reveal-last) (default: none)":                   "                   el último carácter escrito (reveal-last) (predeterminado: none)",
+	"  -report          Print the estimated cost of cracking the result offline to stderr":        "  -report          Mostrar en stderr el coste estimado de romper el resultado sin conexión",
+	"  -strict          Treat password warnings (bidi controls, confusable scripts) as errors":    "  -strict          Tratar las advertencias (controles bidi, escrituras confundibles) como errores",
+	"  -keychain ITEM   Read password from the OS keychain (macOS Keychain, Windows":              "  -keychain ITEM   Leer la contraseña del llavero del sistema (Llavero de macOS,",
+	"                   Credential Manager, libsecret or KWallet)":                                "                   Administrador de credenciales de Windows, libsecret o KWallet)",
+	"  -password-file F Read password from the first line of file F":                              "  -password-file F Leer la contraseña de la primera línea del archivo F",
+	"  -gpg             Decrypt -password-file with gpg (e.g. a pass/gopass entry)":               "  -gpg             Descifrar -password-file con gpg (p. ej. una entrada de pass/gopass)",
+	"  -credential NAME Read password from systemd credential NAME ($CREDENTIALS_DIRECTORY)":      "  -credential NAME Leer la contraseña de la credencial de systemd NAME ($CREDENTIALS_DIRECTORY)",
+	"                   Build the verifier from a precomputed SaltedPassword; needs -salt and -i": "                   Construir el verificador a partir de un SaltedPassword precalculado; requiere -salt e -i",
+	"  -salt B64        Base64 salt the -salted-password was derived with":                        "  -salt B64        Sal en base64 con la que se derivó -salted-password",
+	"  -passphrase SPEC Generate a diceware passphrase and print it before its verifier;":         "  -passphrase SPEC Generar una frase de contraseña diceware e imprimirla antes de su verificador;",
+	"                   SPEC is words=N[,sep=S] (default: words=6, space separated)":              "                   SPEC es words=N[,sep=S] (predeterminado: words=6, separadas por espacios)",
+	"  -wordlist FILE   Wordlist for -passphrase (default: EFF's large wordlist, built in)":       "  -wordlist FILE   Lista de palabras para -passphrase (predeterminada: la lista grande de la EFF, incluida)",
+	"  -salt-from-key   Derive the salt from -key-file and -user with HKDF, so re-runs":           "  -salt-from-key   Derivar la sal de -key-file y -user con HKDF, para que cada ejecución",
+	"                   give the same verifier":                                                   "                   dé el mismo verificador",
+	"  -key-file FILE   Provisioning key for -salt-from-key (at least 32 bytes)":                  "  -key-file FILE   Clave de aprovisionamiento para -salt-from-key (al menos 32 bytes)",
+	"  -user NAME       User the derived salt belongs to (default: -role)":                        "  -user NAME       Usuario al que pertenece la sal derivada (por defecto: -role)",
+	"  -h, -help        Show this help message":                                                   "  -h, -help        Mostrar esta ayuda",
+	"  -lang LANG       Language for prompts, help and messages: en, de, es or fr":                "  -lang LANG       Idioma de las solicitudes, la ayuda y los mensajes: en, de, es o fr",
+	"                   (default: from $LC_ALL, $LC_MESSAGES or $LANG)":                           "                   (predeterminado: según $LC_ALL, $LC_MESSAGES o $LANG)",
+	"  -i, -iterations  Number of PBKDF2 iterations (default: 4096), or auto[:duration]":          "  -i, -iterations  Número de iteraciones de PBKDF2 (predeterminado: 4096), o auto[:duración]",
+	"                   to fit a time budget (default duration: 100ms)":                           "                   para ajustarse a un presupuesto de tiempo (duración predeterminada: 100ms)",
+	"  -terraform       Run as a Terraform external data source (JSON on stdin)":                  "  -terraform       Funcionar como fuente de datos externa de Terraform (JSON en stdin)",
+	"  -ansible FILE    Run as an Ansible module reading JSON arguments from FILE":                "  -ansible FILE    Funcionar como módulo de Ansible leyendo argumentos JSON de FILE",
+	"  -format FORMAT   Output format: scram, pg-md5, both, rabbitmq, ejabberd,":                  "  -format FORMAT   Formato de salida: scram, pg-md5, both, rabbitmq, ejabberd,",
+	"                   prosody, dovecot, yaml or json (default: scram)":                          "                   prosody, dovecot, yaml o json (predeterminado: scram)",
+	"  -formats LIST    Print several formats from one password as a JSON document; LIST is":      "  -formats LIST    Imprimir varios formatos de una contraseña como documento JSON; LIST es",
+	"                   comma-separated from postgres, pg-md5, kafka, mongodb, dovecot,":          "                   una lista separada por comas de postgres, pg-md5, kafka, mongodb, dovecot,",
+	"                   ejabberd, prosody and rabbitmq":                                           "                   ejabberd, prosody y rabbitmq",
+	"  -role NAME       PostgreSQL role name, required for pg-md5 output":                         "  -role NAME       Nombre del rol de PostgreSQL, obligatorio para la salida pg-md5",
+	"  -target TARGET   Target database: postgres or cockroach (default: postgres)":               "  -target TARGET   Base de datos de destino: postgres o cockroach (predeterminado: postgres)",
+	"  -apply           Set the password of -role on the database at -dsn":                        "  -apply           Establecer la contraseña de -role en la base de datos de -dsn",
+	"  -dsn URL         Connection URL for -apply (default: $DATABASE_URL)":                       "  -dsn URL         URL de conexión para -apply (predeterminado: $DATABASE_URL)",
+	"  -fips            Refuse to run unless FIPS 140-3 mode is enabled":                          "  -fips            Negarse a ejecutar si el modo FIPS 140-3 no está activado",
+	"  -output FILE     Write the result to FILE (mode 0600) instead of stdout":                   "  -output FILE     Escribir el resultado en FILE (modo 0600) en lugar de stdout",
+	"  -force           Allow -output to replace an existing file":                                "  -force           Permitir que -output sustituya un archivo existente",
+	"  -copy            Copy the result to the clipboard instead of printing it":                  "  -copy            Copiar el resultado al portapapeles en lugar de imprimirlo",
+	"  -copy-timeout D  Clear the clipboard after D (default: 30s, 0 to keep)":                    "  -copy-timeout D  Vaciar el portapapeles tras D (predeterminado: 30s, 0 para conservarlo)",
+	"  -log-sink SINK   Also log credential operations to journald, syslog (local),":              "  -log-sink SINK   Registrar además las operaciones con credenciales en journald, syslog (local),",
+	"                   syslog://host:port (UDP) or syslog+tcp://host:port":                       "                   syslog://host:port (UDP) o syslog+tcp://host:port",
+	"EXAMPLES:": "EJEMPLOS:",
+	"  %s                    # Prompt for password\n":              "  %s                    # Pedir la contraseña\n",
+	"  echo 'mypass' | %s -stdin  # Read from stdin\n":             "  echo 'mypass' | %s -stdin  # Leer de stdin\n",
+	"  %s -i 8192               # Custom iterations\n":             "  %s -i 8192               # Iteraciones personalizadas\n",
+	"  %s -format both -role app  # SCRAM and legacy md5 hashes\n": "  %s -format both -role app  # Hashes SCRAM y md5 heredado\n",
+	"INSTALLATION:": "INSTALACIÓN:",
+
+	// Errors.
+	"no arguments file given":         "no se indicó ningún archivo de argumentos",
+	"failed to read arguments: %w":    "no se pudieron leer los argumentos: %w",
+	"arguments file exceeds %d bytes": "el archivo de argumentos supera los %d bytes",
+	"failed to decode arguments: %w":  "no se pudieron decodificar los argumentos: %w",
+	"invalid iterations %q: %w":       "número de iteraciones no válido %q: %w",
+	"invalid password: %w":            "contraseña no válida: %w",
+	"failed to list roles: %w":        "no se pudieron listar los roles: %w",
+	"-json-records requires -batch":   "-json-records requiere -batch",
+	"-0 requires -batch":              "-0 requiere -batch",
+	"-print0 requires -batch":         "-print0 requiere -batch",
+	"-batch reads passwords from stdin and cannot be combined with another password source":                          "-batch lee las contraseñas de stdin y no se puede combinar con otra fuente de contraseñas",
+	"-batch cannot be combined with -apply, -copy or -target cockroach":                                              "-batch no se puede combinar con -apply, -copy ni -target cockroach",
+	"-batch -salt-from-key requires -tty or -json-records, so that each record names the user a salt is derived for": "-batch -salt-from-key requiere -tty o -json-records, para que cada registro indique el usuario para el que se deriva una sal",
+	"invalid JSON record: %w":                                                          "registro JSON no válido: %w",
+	"invalid JSON record: unexpected data after the object":                            "registro JSON no válido: datos inesperados después del objeto",
+	"the password field cannot be used with -tty, which prompts for it":                "el campo password no se puede usar con -tty, que lo solicita",
+	"the user field is required with -tty":                                             "el campo user es obligatorio con -tty",
+	"the password field is required":                                                   "el campo password es obligatorio",
+	"the user field is required with -salt-from-key":                                   "el campo user es obligatorio con -salt-from-key",
+	"iterations must be at least 1":                                                    "las iteraciones deben ser al menos 1",
+	"-salt-from-key only supports -format scram, both, yaml or json":                   "-salt-from-key solo admite -format scram, both, yaml o json",
+	"unknown mechanism %q (want SCRAM-SHA-1, SCRAM-SHA-256 or SCRAM-SHA-512)":          "mecanismo desconocido %q (se espera SCRAM-SHA-1, SCRAM-SHA-256 o SCRAM-SHA-512)",
+	"mechanism %s only applies to -format scram or both":                               "el mecanismo %s solo se aplica a -format scram o both",
+	"-manifest is required":                                                            "-manifest es obligatorio",
+	"user %s: %w":                                                                      "usuario %s: %w",
+	"failed to write %s: %w":                                                           "no se pudo escribir %s: %w",
+	"failed to read manifest: %w":                                                      "no se pudo leer el manifiesto: %w",
+	"manifest exceeds %d bytes":                                                        "el manifiesto supera los %d bytes",
+	"failed to parse manifest: %w":                                                     "no se pudo analizar el manifiesto: %w",
+	"failed to decode manifest: %w":                                                    "no se pudo decodificar el manifiesto: %w",
+	"manifest lists no users":                                                          "el manifiesto no contiene usuarios",
+	"%q is not a valid Kubernetes namespace":                                           "%q no es un espacio de nombres de Kubernetes válido",
+	"user %d has no name":                                                              "el usuario %d no tiene nombre",
+	"user %s is listed twice":                                                          "el usuario %s aparece dos veces",
+	"user %s sets both password and generate":                                          "el usuario %s define password y generate a la vez",
+	"user %s: invalid password: %w":                                                    "usuario %s: contraseña no válida: %w",
+	"user %s: iterations must be at least 1":                                           "usuario %s: las iteraciones deben ser al menos 1",
+	"user %s has no targets":                                                           "el usuario %s no tiene destinos",
+	"user %s: unknown target %q (use postgres, cockroach, pgbouncer or kubernetes)":    "usuario %s: destino desconocido %q (use postgres, cockroach, pgbouncer o kubernetes)",
+	"user %s: target %s is listed twice":                                               "usuario %s: el destino %s aparece dos veces",
+	"user %s: %q is not a valid Kubernetes secret name":                                "usuario %s: %q no es un nombre de secreto de Kubernetes válido",
+	"user %s: secret %s is used twice":                                                 "usuario %s: el secreto %s se usa dos veces",
+	"failed to generate password: %w":                                                  "no se pudo generar la contraseña: %w",
+	"auto duration must be positive":                                                   "la duración de auto debe ser positiva",
+	"expected a number or auto[:duration]":                                             "se espera un número o auto[:duración]",
+	"no clipboard tool found for %s":                                                   "no se encontró ninguna herramienta de portapapeles para %s",
+	"failed to write clipboard: %w":                                                    "no se pudo escribir en el portapapeles: %w",
+	"invalid -host: %w":                                                                "-host no válido: %w",
+	"failed to connect to %s: %w":                                                      "no se pudo conectar a %s: %w",
+	"reading from server: %w":                                                          "leyendo del servidor: %w",
+	"$CREDENTIALS_DIRECTORY is not set; run under systemd with LoadCredential=%s":      "$CREDENTIALS_DIRECTORY no está definido; ejecute bajo systemd con LoadCredential=%s",
+	"invalid credential name %q":                                                       "nombre de credencial no válido %q",
+	"expected user or user:credential":                                                 "se espera usuario o usuario:credencial",
+	"unknown -echo mode %q (want none, mask or reveal-last)":                           "modo de -echo desconocido %q (se espera none, mask o reveal-last)",
+	"-echo %s requires an interactive terminal":                                        "-echo %s requiere un terminal interactivo",
+	"failed to set up terminal: %w":                                                    "no se pudo configurar el terminal: %w",
+	"unsupported language %q (available: %s)":                                          "idioma no admitido %q (disponibles: %s)",
+	"keychain item %q not found: %w":                                                   "no se encontró el elemento del llavero %q: %w",
+	"keychain item %q not found in libsecret or KWallet":                               "no se encontró el elemento del llavero %q en libsecret ni en KWallet",
+	"keychain access is not supported on this platform":                                "el acceso al llavero no está admitido en esta plataforma",
+	"-log-sink %s: %w":                                                                 "-log-sink %s: %w",
+	"-log-sink must be journald, syslog, syslog://host:port or syslog+tcp://host:port": "-log-sink debe ser journald, syslog, syslog://host:port o syslog+tcp://host:port",
+	"no local syslog socket found":                                                     "no se encontró ningún socket de syslog local",
+	"-max-length must be at least 1":                                                   "-max-length debe ser al menos 1",
+	"-i must be at least 1":                                                            "-i debe ser al menos 1",
+	"-output and -copy cannot be combined":                                             "-output y -copy no se pueden combinar",
+	"-gpg requires -password-file":                                                     "-gpg requiere -password-file",
+	"generating %s: %w":                                                                "generando %s: %w",
+	"generating %s credentials: %w":                                                    "generando credenciales de %s: %w",
+	"generating SCRAM-SHA-256: %w":                                                     "generando SCRAM-SHA-256: %w",
+	"generating Dovecot password: %w":                                                  "generando la contraseña de Dovecot: %w",
+	"generating RabbitMQ hash: %w":                                                     "generando el hash de RabbitMQ: %w",
+	"-format %s cannot be combined with -passphrase":                                   "-format %s no se puede combinar con -passphrase",
+	"-format %s requires -role":                                                        "-format %s requiere -role",
+	"unknown format %q":                                                                "formato desconocido %q",
+	"failed to read password: %w":                                                      "no se pudo leer la contraseña: %w",
+	"%d roles could not be migrated":                                                   "no se pudieron migrar %d roles",
+	"failed to read password_encryption: %w":                                           "no se pudo leer password_encryption: %w",
+	"failed to read role settings: %w":                                                 "no se pudo leer la configuración de los roles: %w",
+	"failed to open CSV: %w":                                                           "no se pudo abrir el CSV: %w",
+	"failed to read CSV: %w":                                                           "no se pudo leer el CSV: %w",
+	"unknown format %q in -formats (want %s)":                                          "formato desconocido %q en -formats (se espera %s)",
+	"-formats cannot be combined with -format":                                         "-formats no se puede combinar con -format",
+	"-formats pg-md5 requires -role":                                                   "-formats pg-md5 requiere -role",
+	"-formats cannot be combined with -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform or -ansible": "-formats no se puede combinar con -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform ni -ansible",
+	"generating prosody credentials: %w":                                                           "generando credenciales de Prosody: %w",
+	"%s already exists (use -force to overwrite)":                                                  "%s ya existe (use -force para sobrescribir)",
+	"-wordlist requires -passphrase":                                                               "-wordlist requiere -passphrase",
+	"-passphrase cannot be combined with another password source":                                  "-passphrase no se puede combinar con otra fuente de contraseñas",
+	"-passphrase: expected key=value, got %q":                                                      "-passphrase: se espera clave=valor, se recibió %q",
+	"-passphrase: words must be a positive number":                                                 "-passphrase: words debe ser un número positivo",
+	"-passphrase: unknown setting %q":                                                              "-passphrase: ajuste desconocido %q",
+	"this build has no built-in wordlist; pass -wordlist, or run go generate and rebuild":          "esta compilación no tiene lista de palabras integrada; pase -wordlist o ejecute go generate y vuelva a compilar",
+	"built-in wordlist: %w":                                                                        "lista de palabras integrada: %w",
+	"has %d lines, want %d":                                                                        "tiene %d líneas, se esperan %d",
+	"line %d is not %s followed by a tab and a new word":                                           "la línea %d no es %s seguido de un tabulador y una palabra nueva",
+	"%s has %d distinct words, want at least %d":                                                   "%s tiene %d palabras distintas, se esperan al menos %d",
+	"-gpg requires gpg on PATH":                                                                    "-gpg requiere gpg en el PATH",
+	"failed to run gpg: %w":                                                                        "no se pudo ejecutar gpg: %w",
+	"gpg failed to decrypt %s: %w":                                                                 "gpg no pudo descifrar %s: %w",
+	"unsupported mechanism %q":                                                                     "mecanismo no admitido %q",
+	"-client-nonce and -server-nonce are required":                                                 "-client-nonce y -server-nonce son obligatorios",
+	"-salt must be non-empty base64":                                                               "-salt debe ser base64 no vacío",
+	"invalid -cbind-data: %w":                                                                      "-cbind-data no válido: %w",
+	"-report cannot be combined with -batch, -salted-password, -terraform or -ansible":             "-report no se puede combinar con -batch, -salted-password, -terraform ni -ansible",
+	"-salt requires -salted-password":                                                              "-salt requiere -salted-password",
+	"-salted-password requires -salt":                                                              "-salted-password requiere -salt",
+	"-salted-password requires the -i it was derived with":                                         "-salted-password requiere el -i con el que se derivó",
+	"-salted-password only supports -format scram":                                                 "-salted-password solo admite -format scram",
+	"-salted-password cannot be combined with another password source":                             "-salted-password no se puede combinar con otra fuente de contraseñas",
+	"invalid -salted-password: %w":                                                                 "-salted-password no válido: %w",
+	"-key-file requires -salt-from-key":                                                            "-key-file requiere -salt-from-key",
+	"-salt-from-key requires -key-file":                                                            "-salt-from-key requiere -key-file",
+	"-salt-from-key requires -user":                                                                "-salt-from-key requiere -user",
+	"-salt-from-key cannot be combined with -salted-password, -passphrase, -terraform or -ansible": "-salt-from-key no se puede combinar con -salted-password, -passphrase, -terraform ni -ansible",
+	"%s: key must be at least %d bytes":                                                            "%s: la clave debe tener al menos %d bytes",
+	"release %s has no %s":                                                                         "la versión %s no incluye %s",
+	"%s does not match its checksum in %s":                                                         "%s no coincide con su suma de comprobación en %s",
+	"%s: larger than %d bytes":                                                                     "%s: mayor de %d bytes",
+	"malformed checksum for %s":                                                                    "suma de comprobación mal formada para %s",
+	"%s lists no checksum for %s":                                                                  "%s no incluye ninguna suma de comprobación para %s",
+	"invalid public key: %w":                                                                       "clave pública no válida: %w",
+	"invalid public key: unsupported algorithm %q":                                                 "clave pública no válida: algoritmo no admitido %q",
+	"malformed signature":                                                                          "firma mal formada",
+	"malformed signature: %w":                                                                      "firma mal formada: %w",
+	"signed with a different key":                                                                  "firmado con otra clave",
+	"unsupported signature algorithm %q":                                                           "algoritmo de firma no admitido %q",
+	"signature verification failed":                                                                "falló la verificación de la firma",
+	"trusted comment signature verification failed":                                                "falló la verificación de la firma del comentario de confianza",
+	"got %d bytes, want %d":                                                                        "se recibieron %d bytes, se esperan %d",
+	"expected name:password":                                                                       "se espera nombre:contraseña",
+	"serve requires -mock, the only server it runs":                                                "serve requiere -mock, el único servidor que ejecuta",
+	"-sandbox-write requires -sandbox":                                                             "-sandbox-write requiere -sandbox",
+	"-socket-mode and -socket-owner require a unix: -listen address":                               "-socket-mode y -socket-owner requieren una dirección unix: en -listen",
+	"at least one -user or -user-credential is required":                                           "se requiere al menos un -user o -user-credential",
+	"-max-derivations, -max-queue, -queue-timeout and -key-cache-size must not be negative":        "-max-derivations, -max-queue, -queue-timeout y -key-cache-size no pueden ser negativos",
+	"-key-cache-size cannot be used with -pkcs11-module":                                           "-key-cache-size no se puede usar con -pkcs11-module",
+	"-pkcs11-slot and -pkcs11-pin-file require -pkcs11-module":                                     "-pkcs11-slot y -pkcs11-pin-file requieren -pkcs11-module",
+	"opening log sink: %w":                                                                         "abriendo el destino de registro: %w",
+	"reading -pkcs11-pin-file: %w":                                                                 "leyendo -pkcs11-pin-file: %w",
+	"failed to create Landlock ruleset: %w":                                                        "no se pudo crear el conjunto de reglas de Landlock: %w",
+	"failed to set no_new_privs: %w":                                                               "no se pudo establecer no_new_privs: %w",
+	"failed to enter Landlock domain: %w":                                                          "no se pudo entrar en el dominio de Landlock: %w",
+	"-sandbox-write %s: %w":                                                                        "-sandbox-write %s: %w",
+	"seccomp filtering is not supported on %s":                                                     "el filtrado seccomp no está admitido en %s",
+	"failed to install seccomp filter: %w":                                                         "no se pudo instalar el filtro seccomp: %w",
+	"-sandbox is only supported on Linux":                                                          "-sandbox solo se admite en Linux",
+	"-socket-mode and -socket-owner are not supported on %s":                                       "-socket-mode y -socket-owner no se admiten en %s",
+	"-socket-mode and -socket-owner do not apply to abstract socket %s":                            "-socket-mode y -socket-owner no se aplican al socket abstracto %s",
+	"-socket-mode: expected octal permissions such as 0660, got %q":                                "-socket-mode: se esperan permisos en octal como 0660, se recibió %q",
+	"-socket-owner: %w":                                                                            "-socket-owner: %w",
+	"user %s has non-numeric id %q":                                                                "el usuario %s tiene un id no numérico %q",
+	"group %s has non-numeric id %q":                                                               "el grupo %s tiene un id no numérico %q",
+	"-format %s is not supported by CockroachDB":                                                   "-format %s no está admitido por CockroachDB",
+	"-target cockroach requires -role":                                                             "-target cockroach requiere -role",
+	"unknown target %q":                                                                            "destino desconocido %q",
+	"-apply requires -role":                                                                        "-apply requiere -role",
+	"-apply only supports -format scram":                                                           "-apply solo admite -format scram",
+	"-apply cannot be combined with -copy or -output":                                              "-apply no se puede combinar con -copy ni -output",
+	"failed to set password for %s: %w":                                                            "no se pudo establecer la contraseña de %s: %w",
+	"failed to decode query: %w":                                                                   "no se pudo decodificar la consulta: %w",
+	"salt must be base64 of at least %d bytes":                                                     "la sal debe ser base64 de al menos %d bytes",
+	"-tty prompts for the password and cannot be combined with another password source":            "-tty solicita la contraseña y no se puede combinar con otra fuente de contraseñas",
+	"-user cannot be combined with -batch -tty, which reads user names from stdin":                 "-user no se puede combinar con -batch -tty, que lee los nombres de usuario de stdin",
+	"-tui requires an interactive terminal":                                                        "-tui requiere un terminal interactivo",
+	"FIPS 140-3 mode is not enabled; run with GODEBUG=fips140=on or build with GOFIPS140=v1.0.0":   "el modo FIPS 140-3 no está activado; ejecute con GODEBUG=fips140=on o compile con GOFIPS140=v1.0.0",
+	"format %s is not permitted with -fips":                                                        "el formato %s no está permitido con -fips",
+	"line %d: tabs are not allowed for indentation":                                                "línea %d: no se permiten tabuladores para la sangría",
+	"line %d: unexpected indentation":                                                              "línea %d: sangría inesperada",
+	"line %d: expected a sequence item":                                                            "línea %d: se esperaba un elemento de secuencia",
+	"line %d: expected key: value":                                                                 "línea %d: se esperaba clave: valor",
+	"line %d: duplicate key %q":                                                                    "línea %d: clave duplicada %q",
+	"line %d: invalid double-quoted string %s":                                                     "línea %d: cadena entre comillas dobles no válida %s",
+	"line %d: invalid single-quoted string %s":                                                     "línea %d: cadena entre comillas simples no válida %s",
+	"line %d: unterminated flow sequence":                                                          "línea %d: secuencia de flujo sin terminar",
+	"line %d: unsupported YAML syntax %q":                                                          "línea %d: sintaxis YAML no admitida %q",
+	"Copied to clipboard.":                                                                         "Copiado al portapapeles.",
+	"Copied to clipboard; clearing in %s (Ctrl-C to clear now).\n":                                 "Copiado al portapapeles; se vaciará en %s (Ctrl-C para vaciarlo ahora).\n",
+	"Clipboard cleared.":                                                                           "Portapapeles vaciado.",
+
+	// Subcommand options.
+	"Usage of %s:\n": "Uso de %s:\n",
+	"Usage: scram-sha-256 docs man [-out-dir DIR]":                                                           "Uso: scram-sha-256 docs man [-out-dir DIRECTORIO]",
+	"PostgreSQL connection URL (default: $DATABASE_URL, then PG* variables)":                                 "URL de conexión de PostgreSQL (predeterminada: $DATABASE_URL y luego las variables PG*)",
+	"Report SCRAM verifiers with fewer iterations than this":                                                 "Informar de los verificadores SCRAM con menos iteraciones que este valor",
+	"Also audit roles that cannot log in":                                                                    "Auditar también los roles que no pueden iniciar sesión",
+	"JSON or YAML manifest of users (- for stdin)":                                                           "Manifiesto JSON o YAML de usuarios (- para stdin)",
+	"Directory to write the artifacts to":                                                                    "Directorio donde escribir los artefactos",
+	"Overwrite existing artifacts":                                                                           "Sobrescribir los artefactos existentes",
+	"Read password from stdin instead of prompting":                                                          "Leer la contraseña de stdin en lugar de solicitarla",
+	"Server to test, as host:port":                                                                           "Servidor que probar, como host:puerto",
+	"User to authenticate as":                                                                                "Usuario con el que autenticarse",
+	"Wire protocol: postgres, or scram for the line protocol of serve -mock":                                 "Protocolo: postgres, o scram para el protocolo de líneas de serve -mock",
+	"Database to connect to (postgres only)":                                                                 "Base de datos a la que conectarse (solo postgres)",
+	"TLS mode: disable, prefer, require or verify-full (postgres only)":                                      "Modo TLS: disable, prefer, require o verify-full (solo postgres)",
+	"Messages are base64-encoded, as in SMTP, IMAP or driver logs":                                           "Los mensajes están codificados en base64, como en SMTP, IMAP o los registros de controladores",
+	"Read verifiers from this file (- for stdin), e.g. a pg_authid dump":                                     "Leer los verificadores de este archivo (- para stdin), p. ej. un volcado de pg_authid",
+	"Smallest acceptable iteration count":                                                                    "Número mínimo de iteraciones aceptable",
+	"Largest acceptable iteration count (0 for no limit)":                                                    "Número máximo de iteraciones aceptable (0 sin límite)",
+	"Smallest acceptable salt length in bytes":                                                               "Longitud mínima de sal aceptable en bytes",
+	"Comma-separated list of acceptable mechanisms":                                                          "Lista separada por comas de mecanismos aceptables",
+	"CSV file of role,password rows instead of prompting":                                                    "Archivo CSV con filas role,password en lugar de solicitarlas",
+	"Number of PBKDF2 iterations":                                                                            "Número de iteraciones de PBKDF2",
+	"Report what would change without altering any role":                                                     "Informar de lo que cambiaría sin modificar ningún rol",
+	"SCRAM mechanism used in the exchange":                                                                   "Mecanismo SCRAM usado en el intercambio",
+	"Username sent in client-first (n=)":                                                                     "Nombre de usuario enviado en client-first (n=)",
+	"Client nonce from client-first (r=)":                                                                    "Nonce del cliente de client-first (r=)",
+	"Nonce from server-first (r=), with or without the client nonce prefix":                                  "Nonce de server-first (r=), con o sin el prefijo del nonce del cliente",
+	"Base64 salt from server-first (s=)":                                                                     "Sal en base64 de server-first (s=)",
+	"Iteration count from server-first (i=)":                                                                 "Número de iteraciones de server-first (i=)",
+	"GS2 header the client sent":                                                                             "Cabecera GS2 que envió el cliente",
+	"Hex channel binding data for p= exchanges":                                                              "Datos de vinculación de canal en hexadecimal para intercambios p=",
+	"Captured client proof (p=) to compare against":                                                          "Prueba del cliente capturada (p=) con la que comparar",
+	"Captured server signature (v=) to compare against":                                                      "Firma del servidor capturada (v=) con la que comparar",
+	"Existing verifier the password must match":                                                              "Verificador existente con el que debe coincidir la contraseña",
+	"Iteration count for the new verifier, or auto[:duration] (default: the old count, at least 4096)":       "Número de iteraciones del nuevo verificador, o auto[:duración] (predeterminado: el anterior, al menos 4096)",
+	"Also log the rotation to journald, syslog or syslog[+tcp]://host:port":                                  "Registrar también la rotación en journald, syslog o syslog[+tcp]://host:puerto",
+	"Only report whether a newer release is available":                                                       "Solo informar de si hay una versión más reciente",
+	"Minisign public key the release checksums must be signed with":                                          "Clave pública de Minisign con la que deben estar firmadas las sumas de comprobación",
+	"Address to listen on; prefix with unix: for a unix socket":                                              "Dirección en la que escuchar; anteponga unix: para un socket unix",
+	"Permissions of the unix socket in octal, such as 0660 (default 0600)":                                   "Permisos del socket unix en octal, como 0660 (predeterminado 0600)",
+	"Owner of the unix socket as user, user:group or :group":                                                 "Propietario del socket unix como usuario, usuario:grupo o :grupo",
+	"Run the mock SCRAM server for driver testing":                                                           "Ejecutar el servidor SCRAM simulado para probar controladores",
+	"User accepted by the mock server as name:password (repeatable)":                                         "Usuario aceptado por el servidor simulado como nombre:contraseña (repetible)",
+	"User whose password is in a systemd credential, as name or name:credential (repeatable)":                "Usuario cuya contraseña está en una credencial de systemd, como nombre o nombre:credencial (repetible)",
+	"Fault to inject: none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final": "Fallo que inyectar: none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final",
+	"Maximum concurrent PBKDF2 derivations (default: number of CPUs)":                                        "Máximo de derivaciones PBKDF2 simultáneas (predeterminado: número de CPU)",
+	"Maximum conversations waiting for a derivation before e=server-busy (0 for no limit)":                   "Máximo de conversaciones esperando una derivación antes de e=server-busy (0 sin límite)",
+	"Answer e=server-busy after waiting this long for a derivation (0 to wait)":                              "Responder e=server-busy tras esperar este tiempo una derivación (0 para esperar)",
+	"Keep up to this many derived keys in memory and log hit rates to -log-sink (0 disables)":                "Mantener en memoria hasta este número de claves derivadas y registrar la tasa de aciertos en -log-sink (0 lo desactiva)",
+	"Forbid exec and filesystem writes using seccomp and Landlock (Linux)":                                   "Prohibir exec y las escrituras en el sistema de archivos con seccomp y Landlock (Linux)",
+	"Path that stays writable under -sandbox (repeatable)":                                                   "Ruta que sigue siendo escribible con -sandbox (repetible)",
+	"Also log startup and each authentication to journald, syslog or syslog[+tcp]://host:port":               "Registrar también el arranque y cada autenticación en journald, syslog o syslog[+tcp]://host:puerto",
+	"PKCS#11 library that derives user keys on a token, so SaltedPassword never enters process memory":       "Biblioteca PKCS#11 que deriva las claves de usuario en un token, de modo que SaltedPassword nunca entra en la memoria del proceso",
+	"Slot ID of the token for -pkcs11-module":                                                                "ID de ranura del token para -pkcs11-module",
+	"File whose first line is the user PIN for -pkcs11-module":                                               "Archivo cuya primera línea es el PIN de usuario para -pkcs11-module",
+	"Verifier to check the password against":                                                                 "Verificador con el que comprobar la contraseña",
+	"Offer an upgrade for verifiers with fewer iterations than this":                                         "Ofrecer una actualización para verificadores con menos iteraciones que este valor",
+	"Iteration count for the upgraded verifier, or auto[:duration] (default: -min-iterations)":               "Número de iteraciones del verificador actualizado, o auto[:duración] (predeterminado: -min-iterations)",
+	"Print a re-hashed verifier when the password matches one below -min-iterations":                         "Imprimir un verificador recalculado cuando la contraseña coincide con uno por debajo de -min-iterations",
+	"With -upgrade, set the re-hashed verifier as the password of -role at -dsn instead of printing it":      "Con -upgrade, establecer el verificador recalculado como contraseña de -role en -dsn en lugar de imprimirlo",
+	"PostgreSQL role whose password -apply sets":                                                             "Rol de PostgreSQL cuya contraseña establece -apply",
+	"Connection URL for -apply (default: $DATABASE_URL, then PG* variables)":                                 "URL de conexión para -apply (predeterminada: $DATABASE_URL y luego las variables PG*)",
+	"Also log checks and upgrades to journald, syslog or syslog[+tcp]://host:port":                           "Registrar también las comprobaciones y actualizaciones en journald, syslog o syslog[+tcp]://host:puerto",
+	"Directory to write the pages to":                                                                        "Directorio donde escribir las páginas",
+
+	// Interactive mode.
+	"SCRAM-SHA-256 Password Generator\r\n\r\n": "Generador de contraseñas SCRAM-SHA-256\r\n\r\n",
+	"\r\033[KPassword: %s  [%s%s] %s":          "\r\033[KContraseña: %s  [%s%s] %s",
+	"PBKDF2 iterations":                        "Iteraciones de PBKDF2",
+	"Output format":                            "Formato de salida",
+	"%s (arrow keys, Enter to select)\r\n":     "%s (flechas, Intro para seleccionar)\r\n",
+	"PostgreSQL role name: ":                   "Nombre del rol de PostgreSQL: ",
+	"\r\nSummary\r\n":                          "\r\nResumen\r\n",
+	"  Password strength: %s\r\n":              "  Robustez:          %s\r\n",
+	"  Iterations:        %d\r\n":              "  Iteraciones:       %d\r\n",
+	"  Format:            %s\r\n":              "  Formato:           %s\r\n",
+	"  Role:              %s\r\n":              "  Rol:               %s\r\n",
+	"Generate? [y/N] ":                         "¿Generar? [y/N] ",
+	"very weak":                                "muy débil",
+	"weak":                                     "débil",
+	"fair":                                     "aceptable",
+	"strong":                                   "fuerte",
+	"very strong":                              "muy fuerte",
+}
@@ -0,0 +1,417 @@
+package main
+
+// catalogFR holds the French translations.
+var catalogFR = map[string]string{
+	// Prompts and password validation.
+	"Password: ":                                  "Mot de passe : ",
+	"Password for %s: ":                           "Mot de passe pour %s : ",
+	"Confirm password: ":                          "Confirmer le mot de passe : ",
+	"Passwords do not match, try again.":          "Les mots de passe ne correspondent pas, réessayez.",
+	"passwords do not match":                      "les mots de passe ne correspondent pas",
+	"password cannot be empty":                    "le mot de passe ne peut pas être vide",
+	"password must be valid UTF-8":                "le mot de passe doit être en UTF-8 valide",
+	"password exceeds maximum length of %d bytes": "le mot de passe dépasse la longueur maximale de %d octets",
+	"password contains %s U+%04X at position %d":  "le mot de passe contient %s U+%04X à la position %d",
+	"control character":                           "un caractère de contrôle",
+	"unassigned code point":                       "un point de code non attribué",
+	"non-ASCII control character":                 "un caractère de contrôle non ASCII",
+	"private use character":                       "un caractère à usage privé",
+	"non-character code point":                    "un point de code non-caractère",
+	"surrogate code point":                        "un point de code de substitution",
+	"character inappropriate for plain text":      "un caractère inapproprié pour le texte brut",
+	"ideographic description character":           "un caractère de description idéographique",
+	"display-changing or deprecated character":    "un caractère modifiant l'affichage ou obsolète",
+	"tagging character":                           "un caractère d'étiquetage",
+	"password contains invisible bidirectional control U+%04X at position %d":                                      "le mot de passe contient le contrôle bidirectionnel invisible U+%04X à la position %d",
+	"password mixes right-to-left and left-to-right letters, which SASLprep rejects and editors display reordered": "le mot de passe mélange des lettres de droite à gauche et de gauche à droite, ce que SASLprep rejette et que les éditeurs affichent réordonné",
+	"password mixes visually confusable %s letters":                                                                "le mot de passe mélange des lettres %s visuellement confondables",
+	" and ":                                      " et ",
+	"Invalid password: %v\n":                     "Mot de passe invalide : %v\n",
+	"Invalid password: %s\n":                     "Mot de passe invalide : %s\n",
+	"Warning: %s\n":                              "Avertissement : %s\n",
+	"Invalid password in record %d: %v\n":        "Mot de passe invalide dans l'enregistrement %d : %v\n",
+	"Warning in record %d: %s\n":                 "Avertissement dans l'enregistrement %d : %s\n",
+	"Error: %v after %d attempts\n":              "Erreur : %v après %d tentatives\n",
+	"Error reading password from keychain: %v\n": "Erreur de lecture du mot de passe dans le trousseau : %v\n",
+	"Error reading password file: %v\n":          "Erreur de lecture du fichier de mot de passe : %v\n",
+	"Error reading credential: %v\n":             "Erreur de lecture de l'identifiant : %v\n",
+	"Error reading password from stdin: %v\n":    "Erreur de lecture du mot de passe sur stdin : %v\n",
+	"Error reading password: %v\n":               "Erreur de lecture du mot de passe : %v\n",
+
+	// Errors and subcommand messages.
+	"Calibrated iterations: %d (%v budget)\n": "Itérations calibrées : %d (budget de %v)\n",
+	"Error %v\n":                                                                        "Erreur %v\n",
+	"Error: %v\n":                                                                       "Erreur : %v\n",
+	"Error calibrating iterations: %v\n":                                                "Erreur lors du calibrage des itérations : %v\n",
+	"Error copying to clipboard: %v\n":                                                  "Erreur lors de la copie dans le presse-papiers : %v\n",
+	"Error deriving salt: %v\n":                                                         "Erreur lors de la dérivation du sel : %v\n",
+	"Error generating passphrase: %v\n":                                                 "Erreur lors de la génération de la phrase secrète : %v\n",
+	"Error generating verifier: %v\n":                                                   "Erreur lors de la génération du vérificateur : %v\n",
+	"Error in record %d: %v\n":                                                          "Erreur dans l'enregistrement %d : %v\n",
+	"Error in record %d: empty user name\n":                                             "Erreur dans l'enregistrement %d : nom d'utilisateur vide\n",
+	"Error in record %d: record is longer than %d bytes\n":                              "Erreur dans l'enregistrement %d : l'enregistrement dépasse %d octets\n",
+	"Error opening log sink: %v\n":                                                      "Erreur lors de l'ouverture de la destination des journaux : %v\n",
+	"Error opening the controlling terminal: %v\n":                                      "Erreur lors de l'ouverture du terminal de contrôle : %v\n",
+	"Error reading records from stdin: %v\n":                                            "Erreur lors de la lecture des enregistrements depuis stdin : %v\n",
+	"Error writing output: %v\n":                                                        "Erreur lors de l'écriture de la sortie : %v\n",
+	"Error: -verifier is required":                                                      "Erreur : -verifier est requis",
+	"Error: failed to restrict process: %v\n":                                           "Erreur : impossible de restreindre le processus : %v\n",
+	"Error: password does not match the existing verifier":                              "Erreur : le mot de passe ne correspond pas au vérificateur existant",
+	"Error: password does not match the verifier":                                       "Erreur : le mot de passe ne correspond pas au vérificateur",
+	"Invalid options: %v\n":                                                             "Options invalides : %v\n",
+	"Invalid options: -apply needs a SCRAM-SHA-256 verifier, not %s\n":                  "Options invalides : -apply exige un vérificateur SCRAM-SHA-256, pas %s\n",
+	"Invalid options: -apply requires -role":                                            "Options invalides : -apply exige -role",
+	"Invalid options: -apply requires -upgrade":                                         "Options invalides : -apply exige -upgrade",
+	"Invalid options: -key-cache-size must not be negative":                             "Options invalides : -key-cache-size ne doit pas être négatif",
+	"Invalid options: refusing to lower iterations from %d to %d\n":                     "Options invalides : refus de réduire les itérations de %d à %d\n",
+	"Invalid options: refusing to upgrade to %d iterations, below -min-iterations %d\n": "Options invalides : refus de passer à %d itérations, sous -min-iterations %d\n",
+	"Password matches":                                                                  "Le mot de passe correspond",
+	"Password updated for %s\n":                                                         "Mot de passe mis à jour pour %s\n",
+	"Re-hash it now? [y/N] ":                                                            "Le recalculer maintenant ? [y/N] ",
+	"Run with -upgrade to re-hash it":                                                   "Lancez avec -upgrade pour le recalculer",
+	"The verifier uses %d iterations, below the recommended %d\n":                       "Le vérificateur utilise %d itérations, moins que les %d recommandées\n",
+
+	// Help.
+	"SCRAM-SHA-256 Password Generator": "Générateur de mots de passe SCRAM-SHA-256",
+	"USAGE:":                           "UTILISATION :",
+	"COMMANDS:":                        "COMMANDES :",
+	"  migrate          Re-hash PostgreSQL roles still using md5 to SCRAM-SHA-256":                       "  migrate          Rehacher en SCRAM-SHA-256 les rôles PostgreSQL qui utilisent encore md5",
+	"  audit            Report PostgreSQL roles with md5, missing or weak passwords":                     "  audit            Signaler les rôles PostgreSQL au mot de passe md5, absent ou faible",
+	"  decode           Parse and explain SCRAM handshake messages":                                      "  decode           Analyser et expliquer les messages d'une négociation SCRAM",
+	"  lint             Check stored verifiers against format and policy rules":                          "  lint             Vérifier les vérificateurs stockés selon des règles de format et de politique",
+	"  serve -mock      Run a mock SCRAM server for testing client implementations":                      "  serve -mock      Lancer un faux serveur SCRAM pour tester des implémentations clientes",
+	"  prove            Recompute ClientProof and ServerSignature from a captured exchange":              "  prove            Recalculer ClientProof et ServerSignature à partir d'un échange capturé",
+	"  conformance      Run SCRAM handshake scenarios against a live server":                             "  conformance      Exécuter des scénarios de négociation SCRAM contre un serveur réel",
+	"  bootstrap        Write SQL, userlist and Kubernetes secrets for a manifest of users":              "  bootstrap        Écrire le SQL, la userlist et les secrets Kubernetes d'un manifeste d'utilisateurs",
+	"  rotate           Check a password against its verifier and re-hash it with a fresh salt":          "  rotate           Vérifier un mot de passe contre son vérificateur et le rehacher avec un nouveau sel",
+	"  verify           Check a password against its verifier, offering to upgrade low iteration counts": "  verify           Vérifier un mot de passe avec son vérificateur et proposer d'augmenter les itérations faibles",
+	"  docs man         Write roff man pages for the tool and every command":                             "  docs man         Écrire les pages de manuel roff de l'outil et de chaque commande",
+	"  self-update      Replace this binary with the latest signed release":                              "  self-update      Remplacer ce binaire par la dernière version signée",
+	"  version          Show version and FIPS 140-3 status":                                              "  version          Afficher la version et l'état FIPS 140-3",
+	"OPTIONS:": "OPTIONS :",
+	"  -stdin           Read password from stdin instead of prompting":                            "  -stdin           Lire le mot de passe sur stdin au lieu de le demander",
+	"  -batch           Read one password per line from stdin and print a result for each":        "  -batch           Lire un mot de passe par ligne sur stdin et afficher un résultat pour chacun",
+	"  -0               With -batch, read NUL-terminated passwords (as from find -print0);":       "  -0               Avec -batch, lire des mots de passe terminés par NUL (comme ceux de find -print0) ;",
+	"                   newlines and other control characters are still rejected":                 "                   les sauts de ligne et autres caractères de contrôle restent refusés",
+	"  -print0          With -batch, end each result with NUL instead of a newline":               "  -print0          Avec -batch, terminer chaque résultat par NUL au lieu d'un saut de ligne",
+	"  -json-records    With -batch, read JSON records that override -i, -format and -role":       "  -json-records    Avec -batch, lire des enregistrements JSON qui remplacent -i, -format et -role",
+	"  -tty             Prompt on the controlling terminal; with -batch, stdin lists users":       "  -tty             Demander sur le terminal de contrôle ; avec -batch, stdin liste les utilisateurs",
+	"  -confirm         Prompt twice and require both entries to match":                           "  -confirm         Demander deux fois et exiger que les deux saisies correspondent",
+	"  -attempts N      Number of tries allowed when -confirm entries differ (default: 3)":        "  -attempts N      Nombre d'essais autorisés quand les saisies de -confirm diffèrent (défaut : 3)",
+	"  -tui             Interactive mode with strength meter and guided choices":                  "  -tui             Mode interactif avec indicateur de robustesse et choix guidés",
+	"  -max-length N    Maximum password length in bytes (default: 1024)":                         "  -max-length N    Longueur maximale du mot de passe en octets (défaut : 1024)",
+	"  -prompt TEXT     Text shown when prompting for the password (default: \"Password: \")":     "  -prompt TEXT     Texte affiché pour demander le mot de passe (défaut : \"Password: \")",
+	"  -echo MODE       Show nothing (none), an asterisk per character (mask), or briefly":        "  -echo MODE       N'afficher rien (none), un astérisque par caractère (mask), ou brièvement",
+	"                   the last character typed (reveal-last) (default: none)":                   "                   le dernier caractère saisi (reveal-last) (défaut : none)",
+	"  -report          Print the estimated cost of cracking the result offline to stderr":        "  -report          Afficher sur stderr le coût estimé d'un cassage hors ligne du résultat",
+	"  -strict          Treat password warnings (bidi controls, confusable scripts) as errors":    "  -strict          Traiter les avertissements (contrôles bidi, écritures confondables) comme des erreurs",
+	"  -keychain ITEM   Read password from the OS keychain (macOS Keychain, Windows":              "  -keychain ITEM   Lire le mot de passe dans le trousseau du système (trousseau macOS,",
+	"                   Credential Manager, libsecret or KWallet)":                                "                   Gestionnaire d'identification Windows, libsecret ou KWallet)",
+	"  -password-file F Read password from the first line of file F":                              "  -password-file F Lire le mot de passe sur la première ligne du fichier F",
+	"  -gpg             Decrypt -password-file with gpg (e.g. a pass/gopass entry)":               "  -gpg             Déchiffrer -password-file avec gpg (par ex. une entrée pass/gopass)",
+	"  -credential NAME Read password from systemd credential NAME ($CREDENTIALS_DIRECTORY)":      "  -credential NAME Lire le mot de passe dans l'identifiant systemd NAME ($CREDENTIALS_DIRECTORY)",
+	"                   Build the verifier from a precomputed SaltedPassword; needs -salt and -i": "                   Construire le vérificateur à partir d'un SaltedPassword précalculé ; exige -salt et -i",
+	"  -salt B64        Base64 salt the -salted-password was derived with":                        "  -salt B64        Sel en base64 avec lequel -salted-password a été dérivé",
+	"  -passphrase SPEC Generate a diceware passphrase and print it before its verifier;":         "  -passphrase SPEC Générer une phrase de passe diceware et l'afficher avant son vérificateur ;",
+	"                   SPEC is words=N[,sep=S] (default: words=6, space separated)":              "                   SPEC vaut words=N[,sep=S] (défaut : words=6, séparés par des espaces)",
+	"  -wordlist FILE   Wordlist for -passphrase (default: EFF's large wordlist, built in)":       "  -wordlist FILE   Liste de mots pour -passphrase (par défaut : la grande liste de l'EFF, intégrée)",
+	"  -salt-from-key   Derive the salt from -key-file and -user with HKDF, so re-runs":           "  -salt-from-key   Dériver le sel de -key-file et -user avec HKDF, pour que chaque exécution",
+	"                   give the same verifier":                                                   "                   donne le même vérificateur",
+	"  -key-file FILE   Provisioning key for -salt-from-key (at least 32 bytes)":                  "  -key-file FILE   Clé de provisionnement pour -salt-from-key (au moins 32 octets)",
+	"  -user NAME       User the derived salt belongs to (default: -role)":                        "  -user NAME       Utilisateur auquel appartient le sel dérivé (par défaut : -role)",
+	"  -h, -help        Show this help message":                                                   "  -h, -help        Afficher cette aide",
+	"  -lang LANG       Language for prompts, help and messages: en, de, es or fr":                "  -lang LANG       Langue des invites, de l'aide et des messages : en, de, es ou fr",
+	"                   (default: from $LC_ALL, $LC_MESSAGES or $LANG)":                           "                   (défaut : d'après $LC_ALL, $LC_MESSAGES ou $LANG)",
+	"  -i, -iterations  Number of PBKDF2 iterations (default: 4096), or auto[:duration]":          "  -i, -iterations  Nombre d'itérations PBKDF2 (défaut : 4096), ou auto[:durée]",
+	"                   to fit a time budget (default duration: 100ms)":                           "                   pour tenir dans un budget de temps (durée par défaut : 100ms)",
+	"  -terraform       Run as a Terraform external data source (JSON on stdin)":                  "  -terraform       Fonctionner comme source de données externe Terraform (JSON sur stdin)",
+	"  -ansible FILE    Run as an Ansible module reading JSON arguments from FILE":                "  -ansible FILE    Fonctionner comme module Ansible lisant ses arguments JSON dans FILE",
+	"  -format FORMAT   Output format: scram, pg-md5, both, rabbitmq, ejabberd,":                  "  -format FORMAT   Format de sortie : scram, pg-md5, both, rabbitmq, ejabberd,",
+	"                   prosody, dovecot, yaml or json (default: scram)":                          "                   prosody, dovecot, yaml ou json (défaut : scram)",
+	"  -formats LIST    Print several formats from one password as a JSON document; LIST is":      "  -formats LIST    Afficher plusieurs formats d'un mot de passe dans un document JSON ; LIST est",
+	"                   comma-separated from postgres, pg-md5, kafka, mongodb, dovecot,":          "                   une liste séparée par des virgules parmi postgres, pg-md5, kafka, mongodb, dovecot,",
+	"                   ejabberd, prosody and rabbitmq":                                           "                   ejabberd, prosody et rabbitmq",
+	"  -role NAME       PostgreSQL role name, required for pg-md5 output":                         "  -role NAME       Nom du rôle PostgreSQL, requis pour la sortie pg-md5",
+	"  -target TARGET   Target database: postgres or cockroach (default: postgres)":               "  -target TARGET   Base de données cible : postgres ou cockroach (défaut : postgres)",
+	"  -apply           Set the password of -role on the database at -dsn":                        "  -apply           Définir le mot de passe de -role sur la base de données de -dsn",
+	"  -dsn URL         Connection URL for -apply (default: $DATABASE_URL)":                       "  -dsn URL         URL de connexion pour -apply (défaut : $DATABASE_URL)",
+	"  -fips            Refuse to run unless FIPS 140-3 mode is enabled":                          "  -fips            Refuser de s'exécuter hors du mode FIPS 140-3",
+	"  -output FILE     Write the result to FILE (mode 0600) instead of stdout":                   "  -output FILE     Écrire le résultat dans FILE (mode 0600) au lieu de stdout",
+	"  -force           Allow -output to replace an existing file":                                "  -force           Autoriser -output à remplacer un fichier existant",
+	"  -copy            Copy the result to the clipboard instead of printing it":                  "  -copy            Copier le résultat dans le presse-papiers au lieu de l'afficher",
+	"  -copy-timeout D  Clear the clipboard after D (default: 30s, 0 to keep)":                    "  -copy-timeout D  Vider le presse-papiers après D (défaut : 30s, 0 pour le conserver)",
+	"  -log-sink SINK   Also log credential operations to journald, syslog (local),":              "  -log-sink SINK   Journaliser aussi les opérations sur les identifiants vers journald, syslog (local),",
+	"                   syslog://host:port (UDP) or syslog+tcp://host:port":                       "                   syslog://host:port (UDP) ou syslog+tcp://host:port",
+	"EXAMPLES:": "EXEMPLES :",
+	"  %s                    # Prompt for password\n":              "  %s                    # Demander le mot de passe\n",
+	"  echo 'mypass' | %s -stdin  # Read from stdin\n":             "  echo 'mypass' | %s -stdin  # Lire sur stdin\n",
+	"  %s -i 8192               # Custom iterations\n":             "  %s -i 8192               # Nombre d'itérations personnalisé\n",
+	"  %s -format both -role app  # SCRAM and legacy md5 hashes\n": "  %s -format both -role app  # Hachages SCRAM et md5 historique\n",
+	"INSTALLATION:": "INSTALLATION :",
+
+	// Errors.
+	"no arguments file given":         "aucun fichier d’arguments indiqué",
+	"failed to read arguments: %w":    "impossible de lire les arguments : %w",
+	"arguments file exceeds %d bytes": "le fichier d’arguments dépasse %d octets",
+	"failed to decode arguments: %w":  "impossible de décoder les arguments : %w",
+	"invalid iterations %q: %w":       "nombre d’itérations invalide %q : %w",
+	"invalid password: %w":            "mot de passe invalide : %w",
+	"failed to list roles: %w":        "impossible de lister les rôles : %w",
+	"-json-records requires -batch":   "-json-records nécessite -batch",
+	"-0 requires -batch":              "-0 nécessite -batch",
+	"-print0 requires -batch":         "-print0 nécessite -batch",
+	"-batch reads passwords from stdin and cannot be combined with another password source":                          "-batch lit les mots de passe sur stdin et ne peut pas être combiné avec une autre source de mot de passe",
+	"-batch cannot be combined with -apply, -copy or -target cockroach":                                              "-batch ne peut pas être combiné avec -apply, -copy ou -target cockroach",
+	"-batch -salt-from-key requires -tty or -json-records, so that each record names the user a salt is derived for": "-batch -salt-from-key nécessite -tty ou -json-records, afin que chaque enregistrement nomme l’utilisateur pour lequel un sel est dérivé",
+	"invalid JSON record: %w":                                                          "enregistrement JSON invalide : %w",
+	"invalid JSON record: unexpected data after the object":                            "enregistrement JSON invalide : données inattendues après l’objet",
+	"the password field cannot be used with -tty, which prompts for it":                "le champ password ne peut pas être utilisé avec -tty, qui le demande",
+	"the user field is required with -tty":                                             "le champ user est obligatoire avec -tty",
+	"the password field is required":                                                   "le champ password est obligatoire",
+	"the user field is required with -salt-from-key":                                   "le champ user est obligatoire avec -salt-from-key",
+	"iterations must be at least 1":                                                    "le nombre d’itérations doit être au moins 1",
+	"-salt-from-key only supports -format scram, both, yaml or json":                   "-salt-from-key ne prend en charge que -format scram, both, yaml ou json",
+	"unknown mechanism %q (want SCRAM-SHA-1, SCRAM-SHA-256 or SCRAM-SHA-512)":          "mécanisme inconnu %q (attendu : SCRAM-SHA-1, SCRAM-SHA-256 ou SCRAM-SHA-512)",
+	"mechanism %s only applies to -format scram or both":                               "le mécanisme %s ne s’applique qu’à -format scram ou both",
+	"-manifest is required":                                                            "-manifest est obligatoire",
+	"user %s: %w":                                                                      "utilisateur %s : %w",
+	"failed to write %s: %w":                                                           "impossible d’écrire %s : %w",
+	"failed to read manifest: %w":                                                      "impossible de lire le manifeste : %w",
+	"manifest exceeds %d bytes":                                                        "le manifeste dépasse %d octets",
+	"failed to parse manifest: %w":                                                     "impossible d’analyser le manifeste : %w",
+	"failed to decode manifest: %w":                                                    "impossible de décoder le manifeste : %w",
+	"manifest lists no users":                                                          "le manifeste ne contient aucun utilisateur",
+	"%q is not a valid Kubernetes namespace":                                           "%q n’est pas un espace de noms Kubernetes valide",
+	"user %d has no name":                                                              "l’utilisateur %d n’a pas de nom",
+	"user %s is listed twice":                                                          "l’utilisateur %s figure deux fois",
+	"user %s sets both password and generate":                                          "l’utilisateur %s définit à la fois password et generate",
+	"user %s: invalid password: %w":                                                    "utilisateur %s : mot de passe invalide : %w",
+	"user %s: iterations must be at least 1":                                           "utilisateur %s : le nombre d’itérations doit être au moins 1",
+	"user %s has no targets":                                                           "l’utilisateur %s n’a aucune cible",
+	"user %s: unknown target %q (use postgres, cockroach, pgbouncer or kubernetes)":    "utilisateur %s : cible inconnue %q (utilisez postgres, cockroach, pgbouncer ou kubernetes)",
+	"user %s: target %s is listed twice":                                               "utilisateur %s : la cible %s figure deux fois",
+	"user %s: %q is not a valid Kubernetes secret name":                                "utilisateur %s : %q n’est pas un nom de secret Kubernetes valide",
+	"user %s: secret %s is used twice":                                                 "utilisateur %s : le secret %s est utilisé deux fois",
+	"failed to generate password: %w":                                                  "impossible de générer le mot de passe : %w",
+	"auto duration must be positive":                                                   "la durée de auto doit être positive",
+	"expected a number or auto[:duration]":                                             "un nombre ou auto[:durée] est attendu",
+	"no clipboard tool found for %s":                                                   "aucun outil de presse-papiers trouvé pour %s",
+	"failed to write clipboard: %w":                                                    "impossible d’écrire dans le presse-papiers : %w",
+	"invalid -host: %w":                                                                "-host invalide : %w",
+	"failed to connect to %s: %w":                                                      "impossible de se connecter à %s : %w",
+	"reading from server: %w":                                                          "lecture depuis le serveur : %w",
+	"$CREDENTIALS_DIRECTORY is not set; run under systemd with LoadCredential=%s":      "$CREDENTIALS_DIRECTORY n’est pas défini ; exécutez sous systemd avec LoadCredential=%s",
+	"invalid credential name %q":                                                       "nom d’identifiant invalide %q",
+	"expected user or user:credential":                                                 "utilisateur ou utilisateur:identifiant attendu",
+	"unknown -echo mode %q (want none, mask or reveal-last)":                           "mode -echo inconnu %q (attendu : none, mask ou reveal-last)",
+	"-echo %s requires an interactive terminal":                                        "-echo %s nécessite un terminal interactif",
+	"failed to set up terminal: %w":                                                    "impossible de configurer le terminal : %w",
+	"unsupported language %q (available: %s)":                                          "langue non prise en charge %q (disponibles : %s)",
+	"keychain item %q not found: %w":                                                   "élément de trousseau %q introuvable : %w",
+	"keychain item %q not found in libsecret or KWallet":                               "élément de trousseau %q introuvable dans libsecret ou KWallet",
+	"keychain access is not supported on this platform":                                "l’accès au trousseau n’est pas pris en charge sur cette plateforme",
+	"-log-sink %s: %w":                                                                 "-log-sink %s : %w",
+	"-log-sink must be journald, syslog, syslog://host:port or syslog+tcp://host:port": "-log-sink doit être journald, syslog, syslog://host:port ou syslog+tcp://host:port",
+	"no local syslog socket found":                                                     "aucun socket syslog local trouvé",
+	"-max-length must be at least 1":                                                   "-max-length doit être au moins 1",
+	"-i must be at least 1":                                                            "-i doit être au moins 1",
+	"-output and -copy cannot be combined":                                             "-output et -copy ne peuvent pas être combinés",
+	"-gpg requires -password-file":                                                     "-gpg nécessite -password-file",
+	"generating %s: %w":                                                                "génération de %s : %w",
+	"generating %s credentials: %w":                                                    "génération des identifiants %s : %w",
+	"generating SCRAM-SHA-256: %w":                                                     "génération de SCRAM-SHA-256 : %w",
+	"generating Dovecot password: %w":                                                  "génération du mot de passe Dovecot : %w",
+	"generating RabbitMQ hash: %w":                                                     "génération du hachage RabbitMQ : %w",
+	"-format %s cannot be combined with -passphrase":                                   "-format %s ne peut pas être combiné avec -passphrase",
+	"-format %s requires -role":                                                        "-format %s nécessite -role",
+	"unknown format %q":                                                                "format inconnu %q",
+	"failed to read password: %w":                                                      "impossible de lire le mot de passe : %w",
+	"%d roles could not be migrated":                                                   "%d rôles n’ont pas pu être migrés",
+	"failed to read password_encryption: %w":                                           "impossible de lire password_encryption : %w",
+	"failed to read role settings: %w":                                                 "impossible de lire les paramètres des rôles : %w",
+	"failed to open CSV: %w":                                                           "impossible d’ouvrir le CSV : %w",
+	"failed to read CSV: %w":                                                           "impossible de lire le CSV : %w",
+	"unknown format %q in -formats (want %s)":                                          "format inconnu %q dans -formats (attendu : %s)",
+	"-formats cannot be combined with -format":                                         "-formats ne peut pas être combiné avec -format",
+	"-formats pg-md5 requires -role":                                                   "-formats pg-md5 nécessite -role",
+	"-formats cannot be combined with -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform or -ansible": "-formats ne peut pas être combiné avec -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform ou -ansible",
+	"generating prosody credentials: %w":                                                           "génération des identifiants Prosody : %w",
+	"%s already exists (use -force to overwrite)":                                                  "%s existe déjà (utilisez -force pour écraser)",
+	"-wordlist requires -passphrase":                                                               "-wordlist nécessite -passphrase",
+	"-passphrase cannot be combined with another password source":                                  "-passphrase ne peut pas être combiné avec une autre source de mot de passe",
+	"-passphrase: expected key=value, got %q":                                                      "-passphrase : clé=valeur attendu, reçu %q",
+	"-passphrase: words must be a positive number":                                                 "-passphrase : words doit être un nombre positif",
+	"-passphrase: unknown setting %q":                                                              "-passphrase : paramètre inconnu %q",
+	"this build has no built-in wordlist; pass -wordlist, or run go generate and rebuild":          "cette version n’a pas de liste de mots intégrée ; passez -wordlist, ou lancez go generate et recompilez",
+	"built-in wordlist: %w":                                                                        "liste de mots intégrée : %w",
+	"has %d lines, want %d":                                                                        "contient %d lignes, %d attendues",
+	"line %d is not %s followed by a tab and a new word":                                           "la ligne %d n’est pas %s suivi d’une tabulation et d’un nouveau mot",
+	"%s has %d distinct words, want at least %d":                                                   "%s contient %d mots distincts, au moins %d attendus",
+	"-gpg requires gpg on PATH":                                                                    "-gpg nécessite gpg dans le PATH",
+	"failed to run gpg: %w":                                                                        "impossible d’exécuter gpg : %w",
+	"gpg failed to decrypt %s: %w":                                                                 "gpg n’a pas pu déchiffrer %s : %w",
+	"unsupported mechanism %q":                                                                     "mécanisme non pris en charge %q",
+	"-client-nonce and -server-nonce are required":                                                 "-client-nonce et -server-nonce sont obligatoires",
+	"-salt must be non-empty base64":                                                               "-salt doit être du base64 non vide",
+	"invalid -cbind-data: %w":                                                                      "-cbind-data invalide : %w",
+	"-report cannot be combined with -batch, -salted-password, -terraform or -ansible":             "-report ne peut pas être combiné avec -batch, -salted-password, -terraform ou -ansible",
+	"-salt requires -salted-password":                                                              "-salt nécessite -salted-password",
+	"-salted-password requires -salt":                                                              "-salted-password nécessite -salt",
+	"-salted-password requires the -i it was derived with":                                         "-salted-password nécessite le -i avec lequel il a été dérivé",
+	"-salted-password only supports -format scram":                                                 "-salted-password ne prend en charge que -format scram",
+	"-salted-password cannot be combined with another password source":                             "-salted-password ne peut pas être combiné avec une autre source de mot de passe",
+	"invalid -salted-password: %w":                                                                 "-salted-password invalide : %w",
+	"-key-file requires -salt-from-key":                                                            "-key-file nécessite -salt-from-key",
+	"-salt-from-key requires -key-file":                                                            "-salt-from-key nécessite -key-file",
+	"-salt-from-key requires -user":                                                                "-salt-from-key nécessite -user",
+	"-salt-from-key cannot be combined with -salted-password, -passphrase, -terraform or -ansible": "-salt-from-key ne peut pas être combiné avec -salted-password, -passphrase, -terraform ou -ansible",
+	"%s: key must be at least %d bytes":                                                            "%s : la clé doit faire au moins %d octets",
+	"release %s has no %s":                                                                         "la version %s ne contient pas %s",
+	"%s does not match its checksum in %s":                                                         "%s ne correspond pas à sa somme de contrôle dans %s",
+	"%s: larger than %d bytes":                                                                     "%s : plus de %d octets",
+	"malformed checksum for %s":                                                                    "somme de contrôle mal formée pour %s",
+	"%s lists no checksum for %s":                                                                  "%s ne contient aucune somme de contrôle pour %s",
+	"invalid public key: %w":                                                                       "clé publique invalide : %w",
+	"invalid public key: unsupported algorithm %q":                                                 "clé publique invalide : algorithme non pris en charge %q",
+	"malformed signature":                                                                          "signature mal formée",
+	"malformed signature: %w":                                                                      "signature mal formée : %w",
+	"signed with a different key":                                                                  "signé avec une autre clé",
+	"unsupported signature algorithm %q":                                                           "algorithme de signature non pris en charge %q",
+	"signature verification failed":                                                                "échec de la vérification de la signature",
+	"trusted comment signature verification failed":                                                "échec de la vérification de la signature du commentaire de confiance",
+	"got %d bytes, want %d":                                                                        "%d octets reçus, %d attendus",
+	"expected name:password":                                                                       "nom:mot_de_passe attendu",
+	"serve requires -mock, the only server it runs":                                                "serve nécessite -mock, le seul serveur qu’il fait tourner",
+	"-sandbox-write requires -sandbox":                                                             "-sandbox-write nécessite -sandbox",
+	"-socket-mode and -socket-owner require a unix: -listen address":                               "-socket-mode et -socket-owner nécessitent une adresse unix: pour -listen",
+	"at least one -user or -user-credential is required":                                           "au moins un -user ou -user-credential est requis",
+	"-max-derivations, -max-queue, -queue-timeout and -key-cache-size must not be negative":        "-max-derivations, -max-queue, -queue-timeout et -key-cache-size ne doivent pas être négatifs",
+	"-key-cache-size cannot be used with -pkcs11-module":                                           "-key-cache-size ne peut pas être utilisé avec -pkcs11-module",
+	"-pkcs11-slot and -pkcs11-pin-file require -pkcs11-module":                                     "-pkcs11-slot et -pkcs11-pin-file nécessitent -pkcs11-module",
+	"opening log sink: %w":                                                                         "ouverture de la destination des journaux : %w",
+	"reading -pkcs11-pin-file: %w":                                                                 "lecture de -pkcs11-pin-file : %w",
+	"failed to create Landlock ruleset: %w":                                                        "impossible de créer le jeu de règles Landlock : %w",
+	"failed to set no_new_privs: %w":                                                               "impossible de définir no_new_privs : %w",
+	"failed to enter Landlock domain: %w":                                                          "impossible d’entrer dans le domaine Landlock : %w",
+	"-sandbox-write %s: %w":                                                                        "-sandbox-write %s : %w",
+	"seccomp filtering is not supported on %s":                                                     "le filtrage seccomp n’est pas pris en charge sur %s",
+	"failed to install seccomp filter: %w":                                                         "impossible d’installer le filtre seccomp : %w",
+	"-sandbox is only supported on Linux":                                                          "-sandbox n’est pris en charge que sous Linux",
+	"-socket-mode and -socket-owner are not supported on %s":                                       "-socket-mode et -socket-owner ne sont pas pris en charge sur %s",
+	"-socket-mode and -socket-owner do not apply to abstract socket %s":                            "-socket-mode et -socket-owner ne s’appliquent pas au socket abstrait %s",
+	"-socket-mode: expected octal permissions such as 0660, got %q":                                "-socket-mode : permissions en octal attendues, comme 0660, reçu %q",
+	"-socket-owner: %w":                                                                            "-socket-owner : %w",
+	"user %s has non-numeric id %q":                                                                "l’utilisateur %s a un identifiant non numérique %q",
+	"group %s has non-numeric id %q":                                                               "le groupe %s a un identifiant non numérique %q",
+	"-format %s is not supported by CockroachDB":                                                   "-format %s n’est pas pris en charge par CockroachDB",
+	"-target cockroach requires -role":                                                             "-target cockroach nécessite -role",
+	"unknown target %q":                                                                            "cible inconnue %q",
+	"-apply requires -role":                                                                        "-apply nécessite -role",
+	"-apply only supports -format scram":                                                           "-apply ne prend en charge que -format scram",
+	"-apply cannot be combined with -copy or -output":                                              "-apply ne peut pas être combiné avec -copy ou -output",
+	"failed to set password for %s: %w":                                                            "impossible de définir le mot de passe de %s : %w",
+	"failed to decode query: %w":                                                                   "impossible de décoder la requête : %w",
+	"salt must be base64 of at least %d bytes":                                                     "le sel doit être du base64 d’au moins %d octets",
+	"-tty prompts for the password and cannot be combined with another password source":            "-tty demande le mot de passe et ne peut pas être combiné avec une autre source de mot de passe",
+	"-user cannot be combined with -batch -tty, which reads user names from stdin":                 "-user ne peut pas être combiné avec -batch -tty, qui lit les noms d’utilisateur sur stdin",
+	"-tui requires an interactive terminal":                                                        "-tui nécessite un terminal interactif",
+	"FIPS 140-3 mode is not enabled; run with GODEBUG=fips140=on or build with GOFIPS140=v1.0.0":   "le mode FIPS 140-3 n’est pas activé ; exécutez avec GODEBUG=fips140=on ou compilez avec GOFIPS140=v1.0.0",
+	"format %s is not permitted with -fips":                                                        "le format %s n’est pas autorisé avec -fips",
+	"line %d: tabs are not allowed for indentation":                                                "ligne %d : les tabulations ne sont pas autorisées pour l’indentation",
+	"line %d: unexpected indentation":                                                              "ligne %d : indentation inattendue",
+	"line %d: expected a sequence item":                                                            "ligne %d : élément de séquence attendu",
+	"line %d: expected key: value":                                                                 "ligne %d : clé: valeur attendu",
+	"line %d: duplicate key %q":                                                                    "ligne %d : clé en double %q",
+	"line %d: invalid double-quoted string %s":                                                     "ligne %d : chaîne entre guillemets doubles invalide %s",
+	"line %d: invalid single-quoted string %s":                                                     "ligne %d : chaîne entre guillemets simples invalide %s",
+	"line %d: unterminated flow sequence":                                                          "ligne %d : séquence de flux non terminée",
+	"line %d: unsupported YAML syntax %q":                                                          "ligne %d : syntaxe YAML non prise en charge %q",
+	"Copied to clipboard.":                                                                         "Copié dans le presse-papiers.",
+	"Copied to clipboard; clearing in %s (Ctrl-C to clear now).\n":                                 "Copié dans le presse-papiers ; effacement dans %s (Ctrl-C pour effacer maintenant).\n",
+	"Clipboard cleared.":                                                                           "Presse-papiers effacé.",
+
+	// Subcommand options.
+	"Usage of %s:\n": "Utilisation de %s :\n",
+	"Usage: scram-sha-256 docs man [-out-dir DIR]":                                                           "Utilisation : scram-sha-256 docs man [-out-dir RÉPERTOIRE]",
+	"PostgreSQL connection URL (default: $DATABASE_URL, then PG* variables)":                                 "URL de connexion PostgreSQL (par défaut : $DATABASE_URL, puis les variables PG*)",
+	"Report SCRAM verifiers with fewer iterations than this":                                                 "Signaler les vérificateurs SCRAM ayant moins d’itérations que cette valeur",
+	"Also audit roles that cannot log in":                                                                    "Auditer aussi les rôles qui ne peuvent pas se connecter",
+	"JSON or YAML manifest of users (- for stdin)":                                                           "Manifeste JSON ou YAML des utilisateurs (- pour stdin)",
+	"Directory to write the artifacts to":                                                                    "Répertoire où écrire les artefacts",
+	"Overwrite existing artifacts":                                                                           "Écraser les artefacts existants",
+	"Read password from stdin instead of prompting":                                                          "Lire le mot de passe sur stdin au lieu de le demander",
+	"Server to test, as host:port":                                                                           "Serveur à tester, sous la forme hôte:port",
+	"User to authenticate as":                                                                                "Utilisateur sous lequel s’authentifier",
+	"Wire protocol: postgres, or scram for the line protocol of serve -mock":                                 "Protocole : postgres, ou scram pour le protocole ligne à ligne de serve -mock",
+	"Database to connect to (postgres only)":                                                                 "Base de données à laquelle se connecter (postgres uniquement)",
+	"TLS mode: disable, prefer, require or verify-full (postgres only)":                                      "Mode TLS : disable, prefer, require ou verify-full (postgres uniquement)",
+	"Messages are base64-encoded, as in SMTP, IMAP or driver logs":                                           "Les messages sont encodés en base64, comme dans SMTP, IMAP ou les journaux de pilotes",
+	"Read verifiers from this file (- for stdin), e.g. a pg_authid dump":                                     "Lire les vérificateurs depuis ce fichier (- pour stdin), par exemple un export de pg_authid",
+	"Smallest acceptable iteration count":                                                                    "Nombre d’itérations minimal acceptable",
+	"Largest acceptable iteration count (0 for no limit)":                                                    "Nombre d’itérations maximal acceptable (0 pour aucune limite)",
+	"Smallest acceptable salt length in bytes":                                                               "Longueur de sel minimale acceptable en octets",
+	"Comma-separated list of acceptable mechanisms":                                                          "Liste de mécanismes acceptables séparés par des virgules",
+	"CSV file of role,password rows instead of prompting":                                                    "Fichier CSV de lignes role,password au lieu de les demander",
+	"Number of PBKDF2 iterations":                                                                            "Nombre d’itérations PBKDF2",
+	"Report what would change without altering any role":                                                     "Signaler ce qui changerait sans modifier aucun rôle",
+	"SCRAM mechanism used in the exchange":                                                                   "Mécanisme SCRAM utilisé dans l’échange",
+	"Username sent in client-first (n=)":                                                                     "Nom d’utilisateur envoyé dans client-first (n=)",
+	"Client nonce from client-first (r=)":                                                                    "Nonce du client issu de client-first (r=)",
+	"Nonce from server-first (r=), with or without the client nonce prefix":                                  "Nonce issu de server-first (r=), avec ou sans le préfixe du nonce client",
+	"Base64 salt from server-first (s=)":                                                                     "Sel en base64 issu de server-first (s=)",
+	"Iteration count from server-first (i=)":                                                                 "Nombre d’itérations issu de server-first (i=)",
+	"GS2 header the client sent":                                                                             "En-tête GS2 envoyé par le client",
+	"Hex channel binding data for p= exchanges":                                                              "Données de liaison de canal en hexadécimal pour les échanges p=",
+	"Captured client proof (p=) to compare against":                                                          "Preuve client capturée (p=) à comparer",
+	"Captured server signature (v=) to compare against":                                                      "Signature serveur capturée (v=) à comparer",
+	"Existing verifier the password must match":                                                              "Vérificateur existant auquel le mot de passe doit correspondre",
+	"Iteration count for the new verifier, or auto[:duration] (default: the old count, at least 4096)":       "Nombre d’itérations du nouveau vérificateur, ou auto[:durée] (par défaut : l’ancien nombre, au moins 4096)",
+	"Also log the rotation to journald, syslog or syslog[+tcp]://host:port":                                  "Journaliser aussi la rotation dans journald, syslog ou syslog[+tcp]://hôte:port",
+	"Only report whether a newer release is available":                                                       "Indiquer seulement si une version plus récente est disponible",
+	"Minisign public key the release checksums must be signed with":                                          "Clé publique Minisign avec laquelle les sommes de contrôle doivent être signées",
+	"Address to listen on; prefix with unix: for a unix socket":                                              "Adresse d’écoute ; préfixez par unix: pour un socket unix",
+	"Permissions of the unix socket in octal, such as 0660 (default 0600)":                                   "Permissions du socket unix en octal, comme 0660 (par défaut 0600)",
+	"Owner of the unix socket as user, user:group or :group":                                                 "Propriétaire du socket unix sous la forme utilisateur, utilisateur:groupe ou :groupe",
+	"Run the mock SCRAM server for driver testing":                                                           "Lancer le serveur SCRAM factice pour tester les pilotes",
+	"User accepted by the mock server as name:password (repeatable)":                                         "Utilisateur accepté par le serveur factice sous la forme nom:mot_de_passe (répétable)",
+	"User whose password is in a systemd credential, as name or name:credential (repeatable)":                "Utilisateur dont le mot de passe est dans un identifiant systemd, sous la forme nom ou nom:identifiant (répétable)",
+	"Fault to inject: none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final": "Panne à injecter : none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final",
+	"Maximum concurrent PBKDF2 derivations (default: number of CPUs)":                                        "Nombre maximal de dérivations PBKDF2 simultanées (par défaut : nombre de processeurs)",
+	"Maximum conversations waiting for a derivation before e=server-busy (0 for no limit)":                   "Nombre maximal de conversations en attente d’une dérivation avant e=server-busy (0 pour aucune limite)",
+	"Answer e=server-busy after waiting this long for a derivation (0 to wait)":                              "Répondre e=server-busy après avoir attendu une dérivation aussi longtemps (0 pour attendre)",
+	"Keep up to this many derived keys in memory and log hit rates to -log-sink (0 disables)":                "Garder en mémoire jusqu’à ce nombre de clés dérivées et journaliser le taux de succès dans -log-sink (0 désactive)",
+	"Forbid exec and filesystem writes using seccomp and Landlock (Linux)":                                   "Interdire exec et les écritures sur le système de fichiers avec seccomp et Landlock (Linux)",
+	"Path that stays writable under -sandbox (repeatable)":                                                   "Chemin qui reste accessible en écriture sous -sandbox (répétable)",
+	"Also log startup and each authentication to journald, syslog or syslog[+tcp]://host:port":               "Journaliser aussi le démarrage et chaque authentification dans journald, syslog ou syslog[+tcp]://hôte:port",
+	"PKCS#11 library that derives user keys on a token, so SaltedPassword never enters process memory":       "Bibliothèque PKCS#11 qui dérive les clés des utilisateurs sur un jeton, afin que SaltedPassword n’entre jamais dans la mémoire du processus",
+	"Slot ID of the token for -pkcs11-module":                                                                "Identifiant d’emplacement du jeton pour -pkcs11-module",
+	"File whose first line is the user PIN for -pkcs11-module":                                               "Fichier dont la première ligne est le code PIN utilisateur pour -pkcs11-module",
+	"Verifier to check the password against":                                                                 "Vérificateur avec lequel contrôler le mot de passe",
+	"Offer an upgrade for verifiers with fewer iterations than this":                                         "Proposer une mise à niveau des vérificateurs ayant moins d’itérations que cette valeur",
+	"Iteration count for the upgraded verifier, or auto[:duration] (default: -min-iterations)":               "Nombre d’itérations du vérificateur mis à niveau, ou auto[:durée] (par défaut : -min-iterations)",
+	"Print a re-hashed verifier when the password matches one below -min-iterations":                         "Afficher un vérificateur recalculé quand le mot de passe correspond à un vérificateur sous -min-iterations",
+	"With -upgrade, set the re-hashed verifier as the password of -role at -dsn instead of printing it":      "Avec -upgrade, définir le vérificateur recalculé comme mot de passe de -role sur -dsn au lieu de l’afficher",
+	"PostgreSQL role whose password -apply sets":                                                             "Rôle PostgreSQL dont -apply définit le mot de passe",
+	"Connection URL for -apply (default: $DATABASE_URL, then PG* variables)":                                 "URL de connexion pour -apply (par défaut : $DATABASE_URL, puis les variables PG*)",
+	"Also log checks and upgrades to journald, syslog or syslog[+tcp]://host:port":                           "Journaliser aussi les contrôles et mises à niveau dans journald, syslog ou syslog[+tcp]://hôte:port",
+	"Directory to write the pages to":                                                                        "Répertoire où écrire les pages",
+
+	// Interactive mode.
+	"SCRAM-SHA-256 Password Generator\r\n\r\n": "Générateur de mots de passe SCRAM-SHA-256\r\n\r\n",
+	"\r\033[KPassword: %s  [%s%s] %s":          "\r\033[KMot de passe : %s  [%s%s] %s",
+	"PBKDF2 iterations":                        "Itérations PBKDF2",
+	"Output format":                            "Format de sortie",
+	"%s (arrow keys, Enter to select)\r\n":     "%s (flèches, Entrée pour sélectionner)\r\n",
+	"PostgreSQL role name: ":                   "Nom du rôle PostgreSQL : ",
+	"\r\nSummary\r\n":                          "\r\nRésumé\r\n",
+	"  Password strength: %s\r\n":              "  Robustesse :       %s\r\n",
+	"  Iterations:        %d\r\n":              "  Itérations :       %d\r\n",
+	"  Format:            %s\r\n":              "  Format :           %s\r\n",
+	"  Role:              %s\r\n":              "  Rôle :             %s\r\n",
+	"Generate? [y/N] ":                         "Générer ? [y/N] ",
+	"very weak":                                "très faible",
+	"weak":                                     "faible",
+	"fair":                                     "moyen",
+	"strong":                                   "fort",
+	"very strong":                              "très fort",
+}
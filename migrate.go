@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/internal/pgwire"
+)
+
+const connectTimeout = 30 * time.Second
+
+type migrateConfig struct {
+	DSN        string
+	CSVPath    string
+	Iterations int
+	DryRun     bool
+}
+
+func migrateFlags(config *migrateConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.StringVar(&config.DSN, "dsn", os.Getenv("DATABASE_URL"), "PostgreSQL connection URL (default: $DATABASE_URL, then PG* variables)")
+	fs.StringVar(&config.CSVPath, "csv", "", "CSV file of role,password rows instead of prompting")
+	fs.IntVar(&config.Iterations, "iterations", defaultIterations, "Number of PBKDF2 iterations")
+	fs.IntVar(&config.Iterations, "i", defaultIterations, "Number of PBKDF2 iterations")
+	fs.BoolVar(&config.DryRun, "dry-run", false, "Report what would change without altering any role")
+	return fs
+}
+
+func runMigrate(args []string) int {
+	config := migrateConfig{}
+
+	fs := migrateFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if err := migrate(config, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func migrate(config migrateConfig, w io.Writer) error {
+	var passwords map[string]string
+	if config.CSVPath != "" {
+		var err error
+		if passwords, err = readPasswordCSV(config.CSVPath); err != nil {
+			return err
+		}
+	}
+
+	conn, err := connectPostgres(config.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := reportMD5Settings(conn, w); err != nil {
+		return err
+	}
+
+	rows, err := conn.Query("SELECT rolname, rolpassword FROM pg_authid WHERE rolpassword LIKE 'md5%' ORDER BY rolname")
+	if err != nil {
+		return errorf("failed to list roles: %w", err)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No roles with md5 passwords found.")
+		return nil
+	}
+
+	var migrated, skipped, failed int
+	for _, row := range rows {
+		role, oldHash := *row[0], *row[1]
+
+		password, ok := passwords[role]
+		if passwords == nil {
+			password, err = promptPasswordWithText(fmt.Sprintf("New password for %s (empty to skip): ", role))
+			if err != nil {
+				return err
+			}
+			ok = password != ""
+		}
+		if !ok {
+			fmt.Fprintf(w, "%s: skipped\n", role)
+			skipped++
+			continue
+		}
+
+		if err := migrateRole(conn, role, oldHash, password, config); err != nil {
+			fmt.Fprintf(w, "%s: failed: %v\n", role, err)
+			failed++
+			continue
+		}
+
+		change := "password changed"
+		if generatePGMD5(password, role) == oldHash {
+			change = "password unchanged"
+		}
+		action := "migrated"
+		if config.DryRun {
+			action = "would migrate"
+		}
+		fmt.Fprintf(w, "%s: %s (%s)\n", role, action, change)
+		migrated++
+	}
+
+	fmt.Fprintf(w, "%d migrated, %d skipped, %d failed\n", migrated, skipped, failed)
+	if failed > 0 {
+		return errorf("%d roles could not be migrated", failed)
+	}
+	return nil
+}
+
+func migrateRole(conn *pgwire.Conn, role, oldHash, password string, config migrateConfig) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := generateSCRAMSHA256(password, config.Iterations)
+	if err != nil {
+		return err
+	}
+	if config.DryRun {
+		return nil
+	}
+
+	return conn.Exec(fmt.Sprintf("ALTER ROLE %s PASSWORD %s",
+		pgwire.QuoteIdentifier(role), pgwire.QuoteLiteral(hash)))
+}
+
+// reportMD5Settings warns about settings that would make PostgreSQL keep
+// producing md5 hashes after the migration.
+func reportMD5Settings(conn *pgwire.Conn, w io.Writer) error {
+	rows, err := conn.Query("SHOW password_encryption")
+	if err != nil {
+		return errorf("failed to read password_encryption: %w", err)
+	}
+	if len(rows) == 1 && rows[0][0] != nil && *rows[0][0] == "md5" {
+		fmt.Fprintln(w, "warning: server password_encryption is md5; passwords set by clients will still be stored as md5")
+	}
+
+	rows, err = conn.Query("SELECT r.rolname FROM pg_db_role_setting s JOIN pg_roles r ON r.oid = s.setrole " +
+		"WHERE 'password_encryption=md5' = ANY (s.setconfig) ORDER BY 1")
+	if err != nil {
+		return errorf("failed to read role settings: %w", err)
+	}
+	for _, row := range rows {
+		fmt.Fprintf(w, "warning: role %s sets password_encryption=md5\n", *row[0])
+	}
+	return nil
+}
+
+func readPasswordCSV(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errorf("failed to open CSV: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 2
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errorf("failed to read CSV: %w", err)
+	}
+
+	passwords := make(map[string]string, len(records))
+	for i, record := range records {
+		if i == 0 && record[0] == "role" && record[1] == "password" {
+			continue
+		}
+		passwords[record[0]] = record[1]
+	}
+	return passwords, nil
+}
+
+func connectPostgres(dsn string) (*pgwire.Conn, error) {
+	cfg, err := pgwire.ParseURL(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	conn, err := pgwire.Connect(ctx, cfg)
+	if err != nil {
+		return nil, errorf("failed to connect to %s: %w", cfg.Addr(), err)
+	}
+	return conn, nil
+}
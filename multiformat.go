@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// multiFormats are the names -formats accepts. The SCRAM-SHA-256 ones
+// (postgres, kafka, mongodb, dovecot and ejabberd) share one salt and one
+// derivation; prosody uses SCRAM-SHA-1 and rabbitmq its own scheme, so
+// they get salts of their own.
+var multiFormats = []string{"postgres", "pg-md5", "kafka", "mongodb", "dovecot", "ejabberd", "prosody", "rabbitmq"}
+
+// parseFormats splits a -formats list, accepting scram as another name
+// for postgres and ignoring repeats.
+func parseFormats(list string) ([]string, error) {
+	var formats []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "scram" {
+			name = "postgres"
+		}
+		if !slices.Contains(multiFormats, name) {
+			return nil, errorf("unknown format %q in -formats (want %s)", name, strings.Join(multiFormats, ", "))
+		}
+		if !slices.Contains(formats, name) {
+			formats = append(formats, name)
+		}
+	}
+	return formats, nil
+}
+
+// validateFormats checks the options that go with -formats, which
+// replaces -format and prints one JSON document.
+func validateFormats(config Config) error {
+	if config.Formats == "" {
+		return nil
+	}
+	formats, err := parseFormats(config.Formats)
+	if err != nil {
+		return err
+	}
+	if config.Format != "scram" {
+		return errorf("-formats cannot be combined with -format")
+	}
+	if config.FIPS {
+		for _, format := range formats {
+			if err := checkFIPSFormat(format); err != nil {
+				return err
+			}
+		}
+	}
+	if slices.Contains(formats, "pg-md5") && config.Role == "" {
+		return errorf("-formats pg-md5 requires -role")
+	}
+	if config.Apply || config.Target == "cockroach" || config.TUI || config.Batch || config.Passphrase != "" ||
+		config.SaltedPassword != "" || config.Terraform || config.Ansible {
+		return errorf("-formats cannot be combined with -apply, -target cockroach, -tui, -batch, -passphrase, -salted-password, -terraform or -ansible")
+	}
+	return nil
+}
+
+// mongoCredential is a SCRAM entry of the credentials field of a
+// MongoDB admin.system.users document.
+type mongoCredential struct {
+	IterationCount int    `json:"iterationCount"`
+	Salt           string `json:"salt"`
+	StoredKey      string `json:"storedKey"`
+	ServerKey      string `json:"serverKey"`
+}
+
+// generateFormats returns a JSON object holding password in each format
+// -formats lists, keyed by format name. The SCRAM-SHA-256 formats use
+// salt when it is non-nil and one random salt otherwise.
+func generateFormats(ctx context.Context, config Config, password string, salt []byte) ([]byte, error) {
+	formats, err := parseFormats(config.Formats)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds scram.StoredCredentials
+	if slices.ContainsFunc(formats, func(f string) bool {
+		return f == "postgres" || f == "kafka" || f == "mongodb" || f == "dovecot" || f == "ejabberd"
+	}) {
+		if salt == nil {
+			salt = make([]byte, fieldSaltLength)
+			if _, err := rand.Read(salt); err != nil {
+				return nil, err
+			}
+		}
+		if creds, err = scram.NewStoredCredentialsContext(ctx, crypto.SHA256, password, salt, config.Iterations); err != nil {
+			return nil, errorf("generating SCRAM-SHA-256: %w", err)
+		}
+	}
+	b64 := base64.StdEncoding.EncodeToString
+
+	doc := make(map[string]any)
+	for _, format := range formats {
+		switch format {
+		case "postgres":
+			doc[format] = scram.EncodeVerifier(crypto.SHA256, creds)
+		case "pg-md5":
+			doc[format] = generatePGMD5(password, config.Role)
+		case "kafka":
+			// The credential string Kafka stores for SCRAM-SHA-256 users.
+			doc[format] = fmt.Sprintf("salt=%s,stored_key=%s,server_key=%s,iterations=%d",
+				b64(creds.Salt), b64(creds.StoredKey), b64(creds.ServerKey), creds.Iterations)
+		case "mongodb":
+			doc[format] = map[string]mongoCredential{"SCRAM-SHA-256": {
+				IterationCount: creds.Iterations,
+				Salt:           b64(creds.Salt),
+				StoredKey:      b64(creds.StoredKey),
+				ServerKey:      b64(creds.ServerKey),
+			}}
+		case "dovecot":
+			doc[format] = dovecotPassword(creds)
+		case "ejabberd":
+			doc[format] = map[string]any{
+				"password":       b64(creds.StoredKey),
+				"serverkey":      b64(creds.ServerKey),
+				"salt":           "sha256:" + b64(creds.Salt),
+				"iterationcount": creds.Iterations,
+			}
+		case "prosody":
+			sha1, err := prosodyCredentials(password, config.Iterations)
+			if err != nil {
+				return nil, errorf("generating prosody credentials: %w", err)
+			}
+			doc[format] = map[string]any{
+				"iteration_count": sha1.Iterations,
+				"salt":            string(sha1.Salt),
+				"stored_key":      hex.EncodeToString(sha1.StoredKey),
+				"server_key":      hex.EncodeToString(sha1.ServerKey),
+			}
+		case "rabbitmq":
+			hash, err := generateRabbitMQ(password)
+			if err != nil {
+				return nil, errorf("generating RabbitMQ hash: %w", err)
+			}
+			doc[format] = hash
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
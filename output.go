@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// checkOutputFile fails early, before a password is read, when path
+// already exists and force is not set.
+func checkOutputFile(path string, force bool) error {
+	if force {
+		return nil
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return errorf("%s already exists (use -force to overwrite)", path)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// writeOutputFile writes data to path with mode 0600. The data goes to a
+// temporary file in the same directory which is then renamed into place,
+// so readers never see a partial file and no umask can widen permissions.
+func writeOutputFile(path string, data []byte, force bool) error {
+	if err := checkOutputFile(path, force); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
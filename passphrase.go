@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultPassphraseWords gives about 77 bits with a 7776-word list such as
+// EFF's large wordlist.
+const defaultPassphraseWords = 6
+
+// defaultWordlist is EFF's large wordlist, used when -passphrase is given
+// without -wordlist. The list is published by the EFF under CC BY 3.0 US and
+// is fetched into the source tree with go generate; builtinWordlist checks
+// its structure before use.
+//
+//go:generate curl -fsSLo wordlists/eff_large_wordlist.txt https://www.eff.org/files/2016/07/18/eff_large_wordlist.txt
+//go:embed wordlists
+var wordlists embed.FS
+
+const defaultWordlist = "wordlists/eff_large_wordlist.txt"
+
+// minWordlistSize keeps a short or mostly duplicated file from producing
+// passphrases far weaker than they look.
+const minWordlistSize = 1000
+
+// passphraseSpec holds the -passphrase settings, given as comma-separated
+// key=value pairs such as "words=6" or "words=8,sep=-".
+type passphraseSpec struct {
+	Words     int
+	Separator string
+}
+
+// validatePassphrase checks the options that go with -passphrase, which
+// replaces reading a password.
+func validatePassphrase(config Config) error {
+	if config.Passphrase == "" {
+		if config.Wordlist != "" {
+			return errorf("-wordlist requires -passphrase")
+		}
+		return nil
+	}
+	if _, err := parsePassphraseSpec(config.Passphrase); err != nil {
+		return err
+	}
+	if config.UseStdin || config.TUI || config.Confirm || config.Keychain != "" || config.PasswordFile != "" ||
+		config.Credential != "" || config.SaltedPassword != "" || config.Terraform || config.Ansible {
+		return errorf("-passphrase cannot be combined with another password source")
+	}
+	return nil
+}
+
+// passphraseFromConfig generates the passphrase -passphrase asks for,
+// reports its strength on stderr and returns it with its entropy in bits.
+func passphraseFromConfig(config Config) (string, float64, error) {
+	spec, err := parsePassphraseSpec(config.Passphrase)
+	if err != nil {
+		return "", 0, err
+	}
+	var words []string
+	if config.Wordlist != "" {
+		words, err = readWordlist(config.Wordlist)
+	} else {
+		words, err = builtinWordlist()
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	passphrase, bits, err := generatePassphrase(spec, words)
+	if err != nil {
+		return "", 0, err
+	}
+	fmt.Fprintf(os.Stderr, "Passphrase of %d words from %d: %.0f bits\n", spec.Words, len(words), bits)
+	return passphrase, bits, nil
+}
+
+func parsePassphraseSpec(spec string) (passphraseSpec, error) {
+	p := passphraseSpec{Words: defaultPassphraseWords, Separator: " "}
+	for _, field := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return p, errorf("-passphrase: expected key=value, got %q", field)
+		}
+		switch key {
+		case "words":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return p, errorf("-passphrase: words must be a positive number")
+			}
+			p.Words = n
+		case "sep":
+			p.Separator = value
+		default:
+			return p, errorf("-passphrase: unknown setting %q", key)
+		}
+	}
+	return p, nil
+}
+
+// builtinWordlist returns the embedded EFF large wordlist.
+func builtinWordlist() ([]string, error) {
+	f, err := wordlists.Open(defaultWordlist)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errorf("this build has no built-in wordlist; pass -wordlist, or run go generate and rebuild")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEFFWordlist(string(data)); err != nil {
+		return nil, errorf("built-in wordlist: %w", err)
+	}
+	return parseWordlist("built-in wordlist", strings.NewReader(string(data)))
+}
+
+// effWordlistSize is 6^5, one word for each roll of five dice.
+const effWordlistSize = 7776
+
+// checkEFFWordlist checks that data has the shape of EFF's large wordlist:
+// one line per roll of five dice, 11111 to 66666 in order, each followed
+// by a tab and a distinct word. A truncated or substituted download fails
+// instead of quietly producing weaker passphrases.
+func checkEFFWordlist(data string) error {
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+	if len(lines) != effWordlistSize {
+		return errorf("has %d lines, want %d", len(lines), effWordlistSize)
+	}
+	seen := make(map[string]bool, effWordlistSize)
+	for i, line := range lines {
+		roll, word, ok := strings.Cut(line, "\t")
+		if !ok || roll != diceRoll(i) || word == "" || strings.ContainsAny(word, " \t\r") || seen[word] {
+			return errorf("line %d is not %s followed by a tab and a new word", i+1, diceRoll(i))
+		}
+		seen[word] = true
+	}
+	return nil
+}
+
+// diceRoll returns the i'th roll of five dice in EFF's order, such as
+// "11111" for 0 and "66666" for 7775.
+func diceRoll(i int) string {
+	roll := []byte("11111")
+	for d := len(roll) - 1; d >= 0; d-- {
+		roll[d] += byte(i % 6)
+		i /= 6
+	}
+	return string(roll)
+}
+
+// readWordlist reads a diceware wordlist from path.
+func readWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseWordlist(path, f)
+}
+
+// parseWordlist parses a diceware wordlist, either in EFF's format of dice
+// rolls and a word on each line or with just a word on each line. Blank
+// lines, # comments and repeated words are skipped. name identifies the
+// list in errors.
+func parseWordlist(name string, r io.Reader) ([]string, error) {
+	var words []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		word := fields[len(fields)-1]
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(words) < minWordlistSize {
+		return nil, errorf("%s has %d distinct words, want at least %d", name, len(words), minWordlistSize)
+	}
+	return words, nil
+}
+
+// generatePassphrase picks spec.Words words uniformly with crypto/rand and
+// returns them joined, along with the passphrase's entropy in bits.
+func generatePassphrase(spec passphraseSpec, words []string) (string, float64, error) {
+	max := big.NewInt(int64(len(words)))
+	picked := make([]string, spec.Words)
+	for i := range picked {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", 0, err
+		}
+		picked[i] = words[n.Int64()]
+	}
+	bits := float64(spec.Words) * math.Log2(float64(len(words)))
+	return strings.Join(picked, spec.Separator), bits, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// readPasswordFile reads the password from the first line of path. With
+// decrypt set the file is passed through gpg and the plaintext is only
+// ever held in memory, which suits pass and gopass stores.
+func readPasswordFile(path string, decrypt bool) (string, error) {
+	if !decrypt {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		return readPasswordLine(f)
+	}
+
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return "", errorf("-gpg requires gpg on PATH")
+	}
+	cmd := exec.Command("gpg", "--quiet", "--decrypt", "--", path)
+	// gpg-agent may need the terminal to ask for a passphrase.
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", errorf("failed to run gpg: %w", err)
+	}
+
+	password, readErr := readPasswordLine(stdout)
+	// Drain anything after the first line (pass stores metadata there)
+	// so gpg can exit cleanly.
+	io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return "", errorf("gpg failed to decrypt %s: %w", path, err)
+	}
+	return password, readErr
+}
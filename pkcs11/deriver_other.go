@@ -0,0 +1,27 @@
+//go:build !cgo || !unix
+
+package pkcs11
+
+import (
+	"crypto"
+	"fmt"
+)
+
+// Deriver is a scram.KeyDeriver backed by a PKCS#11 token. This build has
+// no cgo or no dlopen, so Open always fails.
+type Deriver struct{}
+
+// Open reports that PKCS#11 is unavailable in this build.
+func Open(cfg Config) (*Deriver, error) {
+	return nil, fmt.Errorf("pkcs11: this build has no PKCS#11 support (it needs cgo on a Unix system)")
+}
+
+// DeriveKeys implements scram.KeyDeriver.
+func (d *Deriver) DeriveKeys(h crypto.Hash, password string, salt []byte, iterations int) ([]byte, []byte, error) {
+	return nil, nil, fmt.Errorf("pkcs11: this build has no PKCS#11 support")
+}
+
+// Close implements io.Closer.
+func (d *Deriver) Close() error {
+	return nil
+}
@@ -0,0 +1,326 @@
+//go:build cgo && unix
+
+package pkcs11
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+
+// The subset of the PKCS#11 types the package uses, laid out as in the
+// standard's headers for Unix platforms.
+typedef unsigned long CK_ULONG;
+typedef CK_ULONG CK_RV;
+typedef unsigned char CK_BBOOL;
+
+typedef struct {
+	unsigned char major, minor;
+} CK_VERSION;
+
+typedef struct {
+	CK_ULONG mechanism;
+	void *pParameter;
+	CK_ULONG ulParameterLen;
+} CK_MECHANISM;
+
+typedef struct {
+	CK_ULONG type;
+	void *pValue;
+	CK_ULONG ulValueLen;
+} CK_ATTRIBUTE;
+
+typedef struct {
+	void *CreateMutex, *DestroyMutex, *LockMutex, *UnlockMutex;
+	CK_ULONG flags;
+	void *pReserved;
+} CK_C_INITIALIZE_ARGS;
+
+typedef struct {
+	CK_ULONG saltSource;
+	void *pSaltSourceData;
+	CK_ULONG ulSaltSourceDataLen;
+	CK_ULONG iterations;
+	CK_ULONG prf;
+	void *pPrfData;
+	CK_ULONG ulPrfDataLen;
+	unsigned char *pPassword;
+	CK_ULONG *ulPasswordLen;
+} CK_PKCS5_PBKD2_PARAMS;
+
+// CK_FUNCTION_LIST up to C_GenerateKey; the entries after it are not used.
+// Functions the package does not call are declared as plain pointers.
+typedef struct {
+	CK_VERSION version;
+	CK_RV (*C_Initialize)(void *);
+	CK_RV (*C_Finalize)(void *);
+	void *C_GetInfo, *C_GetFunctionList, *C_GetSlotList, *C_GetSlotInfo,
+		*C_GetTokenInfo, *C_GetMechanismList, *C_GetMechanismInfo,
+		*C_InitToken, *C_InitPIN, *C_SetPIN;
+	CK_RV (*C_OpenSession)(CK_ULONG, CK_ULONG, void *, void *, CK_ULONG *);
+	CK_RV (*C_CloseSession)(CK_ULONG);
+	void *C_CloseAllSessions, *C_GetSessionInfo, *C_GetOperationState,
+		*C_SetOperationState;
+	CK_RV (*C_Login)(CK_ULONG, CK_ULONG, unsigned char *, CK_ULONG);
+	CK_RV (*C_Logout)(CK_ULONG);
+	void *C_CreateObject, *C_CopyObject;
+	CK_RV (*C_DestroyObject)(CK_ULONG, CK_ULONG);
+	void *C_GetObjectSize, *C_GetAttributeValue, *C_SetAttributeValue,
+		*C_FindObjectsInit, *C_FindObjects, *C_FindObjectsFinal,
+		*C_EncryptInit, *C_Encrypt, *C_EncryptUpdate, *C_EncryptFinal,
+		*C_DecryptInit, *C_Decrypt, *C_DecryptUpdate, *C_DecryptFinal,
+		*C_DigestInit, *C_Digest, *C_DigestUpdate, *C_DigestKey,
+		*C_DigestFinal;
+	CK_RV (*C_SignInit)(CK_ULONG, CK_MECHANISM *, CK_ULONG);
+	CK_RV (*C_Sign)(CK_ULONG, unsigned char *, CK_ULONG, unsigned char *, CK_ULONG *);
+	void *C_SignUpdate, *C_SignFinal, *C_SignRecoverInit, *C_SignRecover,
+		*C_VerifyInit, *C_Verify, *C_VerifyUpdate, *C_VerifyFinal,
+		*C_VerifyRecoverInit, *C_VerifyRecover, *C_DigestEncryptUpdate,
+		*C_DecryptDigestUpdate, *C_SignEncryptUpdate,
+		*C_DecryptVerifyUpdate;
+	CK_RV (*C_GenerateKey)(CK_ULONG, CK_MECHANISM *, CK_ATTRIBUTE *, CK_ULONG, CK_ULONG *);
+} CK_FUNCTION_LIST;
+
+typedef CK_RV (*get_function_list)(CK_FUNCTION_LIST **);
+
+static CK_FUNCTION_LIST *p11_load(const char *path, void **handle) {
+	*handle = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (*handle == NULL) {
+		return NULL;
+	}
+	get_function_list get = (get_function_list)dlsym(*handle, "C_GetFunctionList");
+	CK_FUNCTION_LIST *f = NULL;
+	if (get == NULL || get(&f) != 0) {
+		dlclose(*handle);
+		*handle = NULL;
+		return NULL;
+	}
+	return f;
+}
+
+static const char *p11_dlerror(void) {
+	return dlerror();
+}
+
+static void p11_unload(void *handle) {
+	dlclose(handle);
+}
+
+static CK_RV p11_initialize(CK_FUNCTION_LIST *f) {
+	// CKF_OS_LOCKING_OK: sessions are used from several threads.
+	CK_C_INITIALIZE_ARGS args = {0};
+	args.flags = 0x2;
+	return f->C_Initialize(&args);
+}
+
+static CK_RV p11_finalize(CK_FUNCTION_LIST *f) {
+	return f->C_Finalize(NULL);
+}
+
+static CK_RV p11_open_session(CK_FUNCTION_LIST *f, CK_ULONG slot, CK_ULONG *session) {
+	// CKF_SERIAL_SESSION; session objects do not need CKF_RW_SESSION.
+	return f->C_OpenSession(slot, 0x4, NULL, NULL, session);
+}
+
+static CK_RV p11_close_session(CK_FUNCTION_LIST *f, CK_ULONG session) {
+	return f->C_CloseSession(session);
+}
+
+static CK_RV p11_login(CK_FUNCTION_LIST *f, CK_ULONG session, unsigned char *pin, CK_ULONG pinLen) {
+	// CKU_USER
+	return f->C_Login(session, 1, pin, pinLen);
+}
+
+// p11_salted_password derives SaltedPassword with CKM_PKCS5_PBKD2 as a
+// sensitive, non-extractable generic secret that can only sign.
+static CK_RV p11_salted_password(CK_FUNCTION_LIST *f, CK_ULONG session, CK_ULONG prf, CK_ULONG keyLen,
+		unsigned char *password, CK_ULONG passwordLen, unsigned char *salt, CK_ULONG saltLen,
+		CK_ULONG iterations, CK_ULONG *key) {
+	CK_PKCS5_PBKD2_PARAMS params = {
+		.saltSource = 0x1, // CKZ_SALT_SPECIFIED
+		.pSaltSourceData = salt,
+		.ulSaltSourceDataLen = saltLen,
+		.iterations = iterations,
+		.prf = prf,
+		.pPassword = password,
+		.ulPasswordLen = &passwordLen,
+	};
+	CK_MECHANISM mechanism = {0x3B0, &params, sizeof params}; // CKM_PKCS5_PBKD2
+
+	CK_ULONG class = 0x4;   // CKO_SECRET_KEY
+	CK_ULONG keyType = 0x10; // CKK_GENERIC_SECRET
+	CK_BBOOL yes = 1, no = 0;
+	CK_ATTRIBUTE template[] = {
+		{0x000, &class, sizeof class},     // CKA_CLASS
+		{0x100, &keyType, sizeof keyType}, // CKA_KEY_TYPE
+		{0x161, &keyLen, sizeof keyLen},   // CKA_VALUE_LEN
+		{0x001, &no, sizeof no},           // CKA_TOKEN
+		{0x103, &yes, sizeof yes},         // CKA_SENSITIVE
+		{0x162, &no, sizeof no},           // CKA_EXTRACTABLE
+		{0x108, &yes, sizeof yes},         // CKA_SIGN
+	};
+	return f->C_GenerateKey(session, &mechanism, template, sizeof template / sizeof template[0], key);
+}
+
+static CK_RV p11_hmac(CK_FUNCTION_LIST *f, CK_ULONG session, CK_ULONG mechanismType, CK_ULONG key,
+		unsigned char *data, CK_ULONG dataLen, unsigned char *mac, CK_ULONG *macLen) {
+	CK_MECHANISM mechanism = {mechanismType, NULL, 0};
+	CK_RV rv = f->C_SignInit(session, &mechanism, key);
+	if (rv != 0) {
+		return rv;
+	}
+	return f->C_Sign(session, data, dataLen, mac, macLen);
+}
+
+static CK_RV p11_destroy(CK_FUNCTION_LIST *f, CK_ULONG session, CK_ULONG object) {
+	return f->C_DestroyObject(session, object);
+}
+*/
+import "C"
+
+import (
+	"crypto"
+	"fmt"
+	"unsafe"
+)
+
+const (
+	ckrUserAlreadyLoggedIn        = 0x100
+	ckrCryptokiAlreadyInitialized = 0x191
+)
+
+// Deriver is a scram.KeyDeriver backed by a PKCS#11 token. It is safe for
+// concurrent use; each derivation runs in its own session.
+type Deriver struct {
+	handle    unsafe.Pointer
+	functions *C.CK_FUNCTION_LIST
+	slot      C.CK_ULONG
+	// login is held open for the Deriver's lifetime, because the token
+	// logs the application out when its last session closes.
+	login       C.CK_ULONG
+	initialized bool
+}
+
+// Open loads cfg.Module, initializes it and logs in to the token in
+// cfg.Slot. Close releases the token and unloads the module.
+func Open(cfg Config) (*Deriver, error) {
+	path := C.CString(cfg.Module)
+	defer C.free(unsafe.Pointer(path))
+
+	d := &Deriver{slot: C.CK_ULONG(cfg.Slot)}
+	d.functions = C.p11_load(path, &d.handle)
+	if d.functions == nil {
+		if d.handle == nil {
+			if msg := C.p11_dlerror(); msg != nil {
+				return nil, fmt.Errorf("pkcs11: loading %s: %s", cfg.Module, C.GoString(msg))
+			}
+		}
+		return nil, fmt.Errorf("pkcs11: %s has no usable C_GetFunctionList", cfg.Module)
+	}
+
+	switch rv := C.p11_initialize(d.functions); rv {
+	case 0:
+		d.initialized = true
+	case ckrCryptokiAlreadyInitialized:
+		// Another part of the process initialized the module and will
+		// finalize it.
+	default:
+		d.Close()
+		return nil, &Error{"C_Initialize", uint(rv)}
+	}
+
+	if rv := C.p11_open_session(d.functions, d.slot, &d.login); rv != 0 {
+		d.Close()
+		return nil, &Error{"C_OpenSession", uint(rv)}
+	}
+	if cfg.PIN != "" {
+		pin := []byte(cfg.PIN)
+		rv := C.p11_login(d.functions, d.login, (*C.uchar)(unsafe.Pointer(&pin[0])), C.CK_ULONG(len(pin)))
+		clear(pin)
+		if rv != 0 && rv != ckrUserAlreadyLoggedIn {
+			d.Close()
+			return nil, &Error{"C_Login", uint(rv)}
+		}
+	}
+	return d, nil
+}
+
+// DeriveKeys implements scram.KeyDeriver. SaltedPassword exists only as a
+// session key on the token while the two HMACs run.
+func (d *Deriver) DeriveKeys(h crypto.Hash, password string, salt []byte, iterations int) (clientKey, serverKey []byte, err error) {
+	mech, ok := mechanisms[h]
+	if !ok {
+		return nil, nil, fmt.Errorf("pkcs11: hash %v is not supported", h)
+	}
+	if len(salt) == 0 {
+		return nil, nil, fmt.Errorf("pkcs11: salt cannot be empty")
+	}
+
+	var session C.CK_ULONG
+	if rv := C.p11_open_session(d.functions, d.slot, &session); rv != 0 {
+		return nil, nil, &Error{"C_OpenSession", uint(rv)}
+	}
+	defer C.p11_close_session(d.functions, session)
+
+	pw := []byte(password)
+	defer clear(pw)
+	var key C.CK_ULONG
+	rv := C.p11_salted_password(d.functions, session, C.CK_ULONG(mech.prf), C.CK_ULONG(h.Size()),
+		bytesPtr(pw), C.CK_ULONG(len(pw)), bytesPtr(salt), C.CK_ULONG(len(salt)),
+		C.CK_ULONG(iterations), &key)
+	if rv != 0 {
+		return nil, nil, &Error{"C_GenerateKey", uint(rv)}
+	}
+	defer C.p11_destroy(d.functions, session, key)
+
+	if clientKey, err = d.hmac(session, mech.hmac, key, h.Size(), "Client Key"); err != nil {
+		return nil, nil, err
+	}
+	if serverKey, err = d.hmac(session, mech.hmac, key, h.Size(), "Server Key"); err != nil {
+		return nil, nil, err
+	}
+	return clientKey, serverKey, nil
+}
+
+func (d *Deriver) hmac(session C.CK_ULONG, mechanism uint, key C.CK_ULONG, size int, label string) ([]byte, error) {
+	data := []byte(label)
+	mac := make([]byte, size)
+	macLen := C.CK_ULONG(len(mac))
+	rv := C.p11_hmac(d.functions, session, C.CK_ULONG(mechanism), key,
+		bytesPtr(data), C.CK_ULONG(len(data)), bytesPtr(mac), &macLen)
+	if rv != 0 {
+		return nil, &Error{"C_Sign", uint(rv)}
+	}
+	if int(macLen) != size {
+		return nil, fmt.Errorf("pkcs11: C_Sign returned %d bytes, want %d", macLen, size)
+	}
+	return mac, nil
+}
+
+// Close logs out, finalizes the module if Open initialized it, and
+// unloads it.
+func (d *Deriver) Close() error {
+	if d.functions == nil {
+		return nil
+	}
+	if d.login != 0 {
+		C.p11_close_session(d.functions, d.login)
+		d.login = 0
+	}
+	var err error
+	if d.initialized {
+		if rv := C.p11_finalize(d.functions); rv != 0 {
+			err = &Error{"C_Finalize", uint(rv)}
+		}
+		d.initialized = false
+	}
+	C.p11_unload(d.handle)
+	d.functions, d.handle = nil, nil
+	return err
+}
+
+func bytesPtr(b []byte) *C.uchar {
+	if len(b) == 0 {
+		return nil
+	}
+	return (*C.uchar)(unsafe.Pointer(&b[0]))
+}
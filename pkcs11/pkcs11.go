@@ -0,0 +1,74 @@
+// Package pkcs11 implements scram.KeyDeriver on a PKCS#11 token, so that
+// SaltedPassword is only ever held inside an HSM.
+//
+// Each derivation runs CKM_PKCS5_PBKD2 with C_GenerateKey to create
+// SaltedPassword as a sensitive, non-extractable session key, then signs
+// "Client Key" and "Server Key" with it using the token's HMAC mechanism
+// (CKM_SHA256_HMAC for SCRAM-SHA-256). Only ClientKey and ServerKey leave
+// the token, and the session key is destroyed before DeriveKeys returns.
+//
+// The vendor's module is loaded at run time with dlopen, so the package
+// needs cgo on a Unix system; elsewhere Open returns an error. The
+// mechanism parameters follow PKCS#11 v2.40, where CK_PKCS5_PBKD2_PARAMS
+// passes the password length by pointer.
+package pkcs11
+
+import (
+	"crypto"
+	"fmt"
+)
+
+// Config selects the module, token and credentials a Deriver uses.
+type Config struct {
+	// Module is the path of the vendor's PKCS#11 library, such as
+	// /usr/lib/softhsm/libsofthsm2.so.
+	Module string
+	// Slot is the ID of the slot holding the token.
+	Slot uint
+	// PIN logs in as the token's normal user. It may be empty for tokens
+	// that allow session objects without a login.
+	PIN string
+}
+
+// Error is a failed PKCS#11 call.
+type Error struct {
+	// Func is the PKCS#11 function that failed, such as "C_GenerateKey".
+	Func string
+	// RV is the CK_RV it returned.
+	RV uint
+}
+
+func (e *Error) Error() string {
+	if name, ok := returnValues[e.RV]; ok {
+		return fmt.Sprintf("pkcs11: %s: %s", e.Func, name)
+	}
+	return fmt.Sprintf("pkcs11: %s: CK_RV 0x%X", e.Func, e.RV)
+}
+
+// returnValues names the CK_RV values a misconfigured token most often
+// returns.
+var returnValues = map[uint]string{
+	0x003: "CKR_SLOT_ID_INVALID",
+	0x005: "CKR_GENERAL_ERROR",
+	0x006: "CKR_FUNCTION_FAILED",
+	0x007: "CKR_ARGUMENTS_BAD",
+	0x054: "CKR_FUNCTION_NOT_SUPPORTED",
+	0x070: "CKR_MECHANISM_INVALID",
+	0x071: "CKR_MECHANISM_PARAM_INVALID",
+	0x0A0: "CKR_PIN_INCORRECT",
+	0x0A4: "CKR_PIN_LOCKED",
+	0x0B3: "CKR_SESSION_HANDLE_INVALID",
+	0x0D1: "CKR_TEMPLATE_INCONSISTENT",
+	0x0E0: "CKR_TOKEN_NOT_PRESENT",
+	0x101: "CKR_USER_NOT_LOGGED_IN",
+	0x150: "CKR_BUFFER_TOO_SMALL",
+	0x190: "CKR_CRYPTOKI_NOT_INITIALIZED",
+}
+
+// mechanisms holds the CKP_ pseudo-random function for CKM_PKCS5_PBKD2 and
+// the CKM_ HMAC mechanism that go with each hash.
+var mechanisms = map[crypto.Hash]struct{ prf, hmac uint }{
+	crypto.SHA1:   {0x1, 0x221},
+	crypto.SHA256: {0x4, 0x251},
+	crypto.SHA512: {0x6, 0x271},
+}
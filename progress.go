@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"golang.org/x/term"
+)
+
+// progressThreshold is the iteration count from which a derivation takes
+// long enough that a silent terminal looks frozen.
+const progressThreshold = 1000000
+
+// derivationContext returns a context that shows derivation progress on
+// stderr when it is a terminal and the iteration count is high.
+func derivationContext(iterations int) context.Context {
+	ctx := context.Background()
+	if iterations < progressThreshold || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return ctx
+	}
+
+	last := -1
+	return scram.WithProgress(ctx, func(done, total int) {
+		percent := done * 100 / total
+		if percent == last {
+			return
+		}
+		last = percent
+		fmt.Fprintf(os.Stderr, "\rDeriving key: %3d%%", percent)
+		if done == total {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+type proveConfig struct {
+	UseStdin    bool
+	Mechanism   string
+	User        string
+	ClientNonce string
+	ServerNonce string
+	Salt        string
+	Iterations  int
+	GS2Header   string
+	CBindData   string
+	Proof       string
+	Signature   string
+}
+
+func proveFlags(config *proveConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("prove", flag.ExitOnError)
+	fs.BoolVar(&config.UseStdin, "stdin", false, "Read password from stdin instead of prompting")
+	fs.StringVar(&config.Mechanism, "mechanism", "SCRAM-SHA-256", "SCRAM mechanism used in the exchange")
+	fs.StringVar(&config.User, "user", "", "Username sent in client-first (n=)")
+	fs.StringVar(&config.ClientNonce, "client-nonce", "", "Client nonce from client-first (r=)")
+	fs.StringVar(&config.ServerNonce, "server-nonce", "", "Nonce from server-first (r=), with or without the client nonce prefix")
+	fs.StringVar(&config.Salt, "salt", "", "Base64 salt from server-first (s=)")
+	fs.IntVar(&config.Iterations, "iterations", defaultIterations, "Iteration count from server-first (i=)")
+	fs.IntVar(&config.Iterations, "i", defaultIterations, "Iteration count from server-first (i=)")
+	fs.StringVar(&config.GS2Header, "gs2-header", "n,,", "GS2 header the client sent")
+	fs.StringVar(&config.CBindData, "cbind-data", "", "Hex channel binding data for p= exchanges")
+	fs.StringVar(&config.Proof, "proof", "", "Captured client proof (p=) to compare against")
+	fs.StringVar(&config.Signature, "signature", "", "Captured server signature (v=) to compare against")
+	return fs
+}
+
+func runProve(args []string) int {
+	config := proveConfig{}
+
+	fs := proveFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	var password string
+	var err error
+	if config.UseStdin {
+		password, err = readPasswordFromStdin()
+	} else {
+		password, err = promptPassword()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+		return 1
+	}
+
+	mismatches, err := prove(config, password, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if mismatches > 0 {
+		return 1
+	}
+	return 0
+}
+
+// prove recomputes the exchange and reports how many captured values did
+// not match.
+func prove(config proveConfig, password string, w io.Writer) (int, error) {
+	h, ok := scram.MechanismHash(config.Mechanism)
+	if !ok {
+		return 0, errorf("unsupported mechanism %q", config.Mechanism)
+	}
+	if config.ClientNonce == "" || config.ServerNonce == "" {
+		return 0, errorf("-client-nonce and -server-nonce are required")
+	}
+	if config.Iterations < 1 {
+		return 0, errorf("iterations must be at least 1")
+	}
+	salt, err := base64.StdEncoding.DecodeString(config.Salt)
+	if err != nil || len(salt) == 0 {
+		return 0, errorf("-salt must be non-empty base64")
+	}
+	cbindData, err := hex.DecodeString(config.CBindData)
+	if err != nil {
+		return 0, errorf("invalid -cbind-data: %w", err)
+	}
+
+	nonce := config.ServerNonce
+	if !strings.HasPrefix(nonce, config.ClientNonce) {
+		nonce = config.ClientNonce + nonce
+	}
+
+	user := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(config.User)
+	clientFirstBare := "n=" + user + ",r=" + config.ClientNonce
+	serverFirst := "r=" + nonce + ",s=" + config.Salt + ",i=" + strconv.Itoa(config.Iterations)
+	cbind := append([]byte(config.GS2Header), cbindData...)
+	withoutProof := "c=" + base64.StdEncoding.EncodeToString(cbind) + ",r=" + nonce
+	authMessage := clientFirstBare + "," + serverFirst + "," + withoutProof
+
+	p, err := scram.ComputeProof(h, password, salt, config.Iterations, authMessage)
+	if err != nil {
+		return 0, err
+	}
+
+	b64 := base64.StdEncoding.EncodeToString
+	fmt.Fprintf(w, "AuthMessage:     %s\n", authMessage)
+	fmt.Fprintf(w, "SaltedPassword:  %s\n", hex.EncodeToString(p.SaltedPassword))
+	fmt.Fprintf(w, "ClientKey:       %s\n", b64(p.ClientKey))
+	fmt.Fprintf(w, "StoredKey:       %s\n", b64(p.StoredKey))
+	fmt.Fprintf(w, "ServerKey:       %s\n", b64(p.ServerKey))
+	fmt.Fprintf(w, "ClientSignature: %s\n", b64(p.ClientSignature))
+	fmt.Fprintf(w, "ClientProof:     %s\n", b64(p.ClientProof))
+	fmt.Fprintf(w, "ServerSignature: %s\n", b64(p.ServerSignature))
+	fmt.Fprintf(w, "client-final:    %s,p=%s\n", withoutProof, b64(p.ClientProof))
+
+	mismatches := 0
+	for _, c := range []struct {
+		name, captured string
+		expected       []byte
+	}{
+		{"client proof", config.Proof, p.ClientProof},
+		{"server signature", config.Signature, p.ServerSignature},
+	} {
+		if c.captured == "" {
+			continue
+		}
+		got, err := base64.StdEncoding.DecodeString(c.captured)
+		if err == nil && hmac.Equal(got, c.expected) {
+			fmt.Fprintf(w, "captured %s matches\n", c.name)
+			continue
+		}
+		fmt.Fprintf(w, "captured %s DOES NOT match\n", c.name)
+		mismatches++
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// Attack rates assumed by -report, per second on one current high-end GPU
+// (roughly an RTX 4090 running hashcat). The PBKDF2 rates count HMAC
+// iterations, so an attacker's guess rate is the rate divided by the
+// iteration count; the others count whole guesses.
+const (
+	gpuPBKDF2SHA256Rate = 9e9    // PBKDF2-HMAC-SHA-256 iterations (hashcat mode 10900)
+	gpuPBKDF2SHA1Rate   = 3e10   // PBKDF2-HMAC-SHA-1 iterations (hashcat mode 12000)
+	gpuSHA256Rate       = 2e10   // salted SHA-256 (hashcat mode 1410)
+	gpuMD5Rate          = 1.6e11 // salted MD5 (hashcat mode 12)
+
+	// gpuHourCost is what renting one such GPU for an hour costs, in US
+	// dollars.
+	gpuHourCost = 2.0
+
+	// reportClusterGPUs is the size of the larger attacker -report
+	// describes.
+	reportClusterGPUs = 1000
+)
+
+// validateReport checks the options that go with -report, which needs the
+// password itself to estimate its entropy.
+func validateReport(config Config) error {
+	if !config.Report {
+		return nil
+	}
+	if config.Batch || config.SaltedPassword != "" || config.Terraform || config.Ansible {
+		return errorf("-report cannot be combined with -batch, -salted-password, -terraform or -ansible")
+	}
+	return nil
+}
+
+// attackScheme is one stored form of the password and what guessing
+// against it costs.
+type attackScheme struct {
+	name string
+	rate float64 // guesses per second per GPU
+}
+
+// reportSchemes lists the schemes the output holds the password in, for
+// -format or -formats.
+func reportSchemes(config Config) []attackScheme {
+	formats := []string{config.Format}
+	if config.Format == "both" {
+		formats = []string{"scram", "pg-md5"}
+	}
+	if config.Formats != "" {
+		formats, _ = parseFormats(config.Formats)
+	}
+
+	iterations := float64(config.Iterations)
+	var schemes []attackScheme
+	for _, format := range formats {
+		var s attackScheme
+		switch format {
+		case "scram", "yaml", "json", "postgres", "kafka", "mongodb", "dovecot", "ejabberd":
+			s = attackScheme{fmt.Sprintf("SCRAM-SHA-256, %d iterations", config.Iterations), gpuPBKDF2SHA256Rate / iterations}
+		case "prosody":
+			s = attackScheme{fmt.Sprintf("SCRAM-SHA-1, %d iterations", config.Iterations), gpuPBKDF2SHA1Rate / iterations}
+		case "pg-md5":
+			s = attackScheme{"PostgreSQL md5", gpuMD5Rate}
+		case "rabbitmq":
+			s = attackScheme{"RabbitMQ salted SHA-256", gpuSHA256Rate}
+		}
+		if !slices.Contains(schemes, s) {
+			schemes = append(schemes, s)
+		}
+	}
+	return schemes
+}
+
+// writeReport writes the -report estimate of what an offline attack on
+// the output would cost, for a password of bits entropy: on average half
+// of the 2^bits candidates are tried before the password is found.
+func writeReport(w io.Writer, config Config, bits float64, exact bool) {
+	fmt.Fprintln(w, "Cracking cost estimate (offline attack on the stored output):")
+	if exact {
+		fmt.Fprintf(w, "  Password entropy: %.0f bits (%s)\n", bits, strengthLabel(bits))
+	} else {
+		fmt.Fprintf(w, "  Password entropy: about %.0f bits (%s), estimated from length and character classes\n", bits, strengthLabel(bits))
+	}
+	guesses := math.Max(math.Exp2(bits-1), 1)
+	for _, s := range reportSchemes(config) {
+		gpuSeconds := guesses / s.rate
+		fmt.Fprintf(w, "  %s: %s guesses/s per GPU\n", s.name, formatRate(s.rate))
+		fmt.Fprintf(w, "    Expected time: %s on 1 GPU, %s on %d GPUs\n",
+			formatSeconds(gpuSeconds), formatSeconds(gpuSeconds/reportClusterGPUs), reportClusterGPUs)
+		fmt.Fprintf(w, "    Expected cost: %s at $%.2f per GPU-hour\n", formatDollars(gpuSeconds/3600*gpuHourCost), gpuHourCost)
+	}
+	fmt.Fprintln(w, "  Rates assume one high-end GPU running hashcat; a dictionary attack on a")
+	fmt.Fprintln(w, "  guessable password is far cheaper than these figures.")
+}
+
+// formatRate prints a guess rate with three significant figures.
+func formatRate(rate float64) string {
+	if rate >= 1e6 {
+		return fmt.Sprintf("%.3g", rate)
+	}
+	return fmt.Sprintf("%.0f", rate)
+}
+
+// formatSeconds prints a duration too long for time.Duration in the
+// largest unit that keeps it readable.
+func formatSeconds(s float64) string {
+	const (
+		minute = 60
+		hour   = 60 * minute
+		day    = 24 * hour
+		year   = 365.25 * day
+	)
+	switch {
+	case s < 1:
+		return "under a second"
+	case s < minute:
+		return fmt.Sprintf("%.0f seconds", s)
+	case s < hour:
+		return fmt.Sprintf("%.0f minutes", s/minute)
+	case s < day:
+		return fmt.Sprintf("%.1f hours", s/hour)
+	case s < year:
+		return fmt.Sprintf("%.0f days", s/day)
+	case s < 1e6*year:
+		return fmt.Sprintf("%.0f years", s/year)
+	}
+	return fmt.Sprintf("%.1e years", s/year)
+}
+
+// formatDollars prints a cost in US dollars.
+func formatDollars(d float64) string {
+	switch {
+	case d < 0.01:
+		return "under $0.01"
+	case d < 100:
+		return fmt.Sprintf("$%.2f", d)
+	case d < 1e12:
+		return fmt.Sprintf("$%.0f", d)
+	}
+	return fmt.Sprintf("$%.1e", d)
+}
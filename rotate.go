@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+type rotateConfig struct {
+	Verifier         string
+	UseStdin         bool
+	Iterations       int
+	IterationsBudget time.Duration
+	LogSink          string
+}
+
+func rotateFlags(config *rotateConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	fs.StringVar(&config.Verifier, "verifier", "", "Existing verifier the password must match")
+	fs.BoolVar(&config.UseStdin, "stdin", false, "Read password from stdin instead of prompting")
+	iterations := iterationsValue{&config.Iterations, &config.IterationsBudget}
+	fs.Var(iterations, "iterations", "Iteration count for the new verifier, or auto[:duration] (default: the old count, at least 4096)")
+	fs.Var(iterations, "i", "Iteration count for the new verifier, or auto[:duration] (default: the old count, at least 4096)")
+	fs.StringVar(&config.LogSink, "log-sink", "", "Also log the rotation to journald, syslog or syslog[+tcp]://host:port")
+	return fs
+}
+
+func runRotate(args []string) int {
+	config := rotateConfig{}
+
+	fs := rotateFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if config.Verifier == "" && fs.NArg() == 1 {
+		config.Verifier = fs.Arg(0)
+	}
+	if config.Verifier == "" {
+		fmt.Fprintln(os.Stderr, msg.Text("Error: -verifier is required"))
+		return exitError
+	}
+	h, old, err := scram.ParseVerifier(config.Verifier)
+	if err != nil {
+		msg.Eprintf("Error: %v\n", err)
+		return exitError
+	}
+	if config.LogSink != "" {
+		if auditLog, err = openLogSink(config.LogSink); err != nil {
+			msg.Eprintf("Error opening log sink: %v\n", err)
+			return exitError
+		}
+	}
+
+	newIterations := max(old.Iterations, defaultIterations)
+	if config.IterationsBudget > 0 {
+		newIterations, err = calibrateIterations(h, config.IterationsBudget)
+		if err != nil {
+			msg.Eprintf("Error calibrating iterations: %v\n", err)
+			return exitError
+		}
+		msg.Eprintf("Calibrated iterations: %d (%v budget)\n", newIterations, config.IterationsBudget)
+	} else if config.Iterations != 0 {
+		newIterations = config.Iterations
+	}
+	if newIterations < old.Iterations {
+		msg.Eprintf("Invalid options: refusing to lower iterations from %d to %d\n", old.Iterations, newIterations)
+		return exitPolicy
+	}
+
+	var password string
+	if config.UseStdin {
+		password, err = readPasswordFromStdin()
+	} else {
+		password, err = promptPassword()
+	}
+	if err != nil {
+		msg.Eprintf("Error reading password: %v\n", err)
+		return exitError
+	}
+
+	// Check the password against the old verifier first, so a mistyped
+	// password is never rotated in.
+	ok, err := scram.VerifyContext(derivationContext(old.Iterations), password, config.Verifier)
+	if err != nil {
+		msg.Eprintf("Error: %v\n", err)
+		return exitError
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, msg.Text("Error: password does not match the existing verifier"))
+		auditLog.Warn("password does not match the existing verifier", "mechanism", scram.MechanismName(h))
+		return exitMismatch
+	}
+
+	verifier, err := scram.NewVerifierContext(derivationContext(newIterations), h, password, newIterations)
+	if err != nil {
+		msg.Eprintf("Error generating verifier: %v\n", err)
+		return exitError
+	}
+	fmt.Println(verifier)
+	auditLog.Info("verifier rotated", "mechanism", scram.MechanismName(h), "old_iterations", old.Iterations, "iterations", newIterations)
+	return 0
+}
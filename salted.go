@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// validateSaltedPassword checks the options that go with -salted-password,
+// which replaces reading a password entirely.
+func validateSaltedPassword(config Config) error {
+	if config.SaltedPassword == "" {
+		if config.Salt != "" {
+			return errorf("-salt requires -salted-password")
+		}
+		return nil
+	}
+	if config.Salt == "" {
+		return errorf("-salted-password requires -salt")
+	}
+	// The iteration count is part of the verifier and must be the one the
+	// SaltedPassword was derived with, so the default is not assumed.
+	if !config.IterationsSet || config.IterationsBudget > 0 {
+		return errorf("-salted-password requires the -i it was derived with")
+	}
+	if config.Format != "scram" {
+		return errorf("-salted-password only supports -format scram")
+	}
+	if config.UseStdin || config.TUI || config.Confirm || config.Keychain != "" || config.PasswordFile != "" ||
+		config.Credential != "" || config.Terraform || config.Ansible {
+		return errorf("-salted-password cannot be combined with another password source")
+	}
+	return nil
+}
+
+// verifierFromSaltedPassword builds a SCRAM-SHA-256 verifier from a hex
+// SaltedPassword and the base64 salt and iteration count it was derived
+// with, as printed by the prove command.
+func verifierFromSaltedPassword(saltedHex, saltB64 string, iterations int) (string, error) {
+	salted, err := hex.DecodeString(saltedHex)
+	if err != nil {
+		return "", errorf("invalid -salted-password: %w", err)
+	}
+	salt, err := base64.StdEncoding.DecodeString(saltB64)
+	if err != nil || len(salt) == 0 {
+		return "", errorf("-salt must be non-empty base64")
+	}
+	creds, err := scram.StoredCredentialsFromSaltedPassword(crypto.SHA256, salted, salt, iterations)
+	if err != nil {
+		return "", err
+	}
+	return scram.EncodeVerifier(crypto.SHA256, creds), nil
+}
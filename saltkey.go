@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"os"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// saltKeyInfo separates salts derived for -salt-from-key from any other
+// use of the same provisioning key.
+const saltKeyInfo = "scram-sha-256 salt v1\x00"
+
+// minSaltKeyLength rejects provisioning keys too short to keep derived
+// salts unpredictable.
+const minSaltKeyLength = 32
+
+// validateSaltFromKey checks the options that go with -salt-from-key.
+func validateSaltFromKey(config Config) error {
+	if !config.SaltFromKey {
+		if config.KeyFile != "" {
+			return errorf("-key-file requires -salt-from-key")
+		}
+		return nil
+	}
+	if config.KeyFile == "" {
+		return errorf("-salt-from-key requires -key-file")
+	}
+	// With -batch each record names the user; validateBatch requires -tty.
+	if saltUser(config) == "" && !config.Batch {
+		return errorf("-salt-from-key requires -user")
+	}
+	if !saltedFormat(config.Format) {
+		return errorf("-salt-from-key only supports -format scram, both, yaml or json")
+	}
+	if config.SaltedPassword != "" || config.Passphrase != "" || config.Terraform || config.Ansible {
+		return errorf("-salt-from-key cannot be combined with -salted-password, -passphrase, -terraform or -ansible")
+	}
+	return nil
+}
+
+// saltedFormat reports whether format's output takes a derived salt.
+func saltedFormat(format string) bool {
+	return format == "scram" || format == "both" || format == "yaml" || format == "json"
+}
+
+// saltUser is the user a derived salt belongs to: -user, or else -role.
+func saltUser(config Config) string {
+	if config.User != "" {
+		return config.User
+	}
+	return config.Role
+}
+
+// readSaltKey reads the provisioning key for -salt-from-key from keyFile.
+func readSaltKey(keyFile string) ([]byte, error) {
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	// A trailing newline added by an editor must not change every salt.
+	key = bytes.TrimRight(key, "\r\n")
+	if len(key) < minSaltKeyLength {
+		return nil, errorf("%s: key must be at least %d bytes", keyFile, minSaltKeyLength)
+	}
+	return key, nil
+}
+
+// deriveSalt derives the salt for user with HKDF-SHA-256 from the
+// provisioning key, so re-runs produce the same verifier while each
+// user's salt stays distinct.
+func deriveSalt(key []byte, user string) ([]byte, error) {
+	return hkdf.Key(sha256.New, key, nil, saltKeyInfo+user, 16)
+}
+
+// verifierWithSalt builds a SCRAM verifier with h for password with the
+// given salt instead of a random one.
+func verifierWithSalt(ctx context.Context, h crypto.Hash, password string, salt []byte, iterations int) (string, error) {
+	creds, err := scram.NewStoredCredentialsContext(ctx, h, password, salt, iterations)
+	if err != nil {
+		return "", err
+	}
+	return scram.EncodeVerifier(h, creds), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandbox restricts the process with unveil and pledge once the options
+// are known: stdio and the terminal, read access to the files named on
+// the command line, and write access to the -output directory. Modes that
+// run other programs (-gpg, -keychain, -copy) are left unrestricted, since
+// those programs need far more than this process does.
+func sandbox(config Config) error {
+	if config.GPG || config.Keychain != "" || config.Copy {
+		return nil
+	}
+
+	promises := []string{"stdio", "tty"}
+	var read []string
+	if config.PasswordFile != "" {
+		read = append(read, config.PasswordFile)
+	}
+	if config.Credential != "" {
+		read = append(read, filepath.Join(os.Getenv("CREDENTIALS_DIRECTORY"), config.Credential))
+	}
+	if config.Wordlist != "" {
+		read = append(read, config.Wordlist)
+	}
+	if config.Ansible && flag.Arg(0) != "" {
+		read = append(read, flag.Arg(0))
+	}
+	if config.Apply {
+		promises = append(promises, "inet", "dns")
+		read = append(read, "/etc/hosts", "/etc/resolv.conf", "/etc/services", "/etc/ssl/cert.pem")
+	}
+	if len(read) > 0 || config.Output != "" {
+		promises = append(promises, "rpath")
+	}
+
+	for _, path := range read {
+		if err := unix.Unveil(path, "r"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if config.Output != "" {
+		// The output is written to a temporary file in the same directory,
+		// chmodded and renamed into place.
+		if err := unix.Unveil(filepath.Dir(config.Output), "rwc"); err != nil {
+			return err
+		}
+		promises = append(promises, "wpath", "cpath", "fattr")
+	}
+	if err := unix.UnveilBlock(); err != nil {
+		return err
+	}
+	return unix.Pledge(strings.Join(promises, " "), "")
+}
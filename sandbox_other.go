@@ -0,0 +1,8 @@
+//go:build !openbsd
+
+package main
+
+// sandbox is a no-op on platforms without pledge and unveil.
+func sandbox(config Config) error {
+	return nil
+}
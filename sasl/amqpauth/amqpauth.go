@@ -0,0 +1,94 @@
+// Package amqpauth drives a SCRAM conversation over AMQP's SASL
+// exchange, for clients and brokers that handle the frames themselves.
+//
+// In AMQP 0-9-1 the mechanisms come from connection.start, the initial
+// response goes in connection.start-ok and each connection.secure
+// challenge is answered with connection.secure-ok. In AMQP 1.0 they are
+// sasl-mechanisms, sasl-init, sasl-challenge and sasl-response, and the
+// server-final message arrives as the additional-data of sasl-outcome.
+package amqpauth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	_ "github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// Authenticator holds the client side of one connection's SASL exchange.
+// AMQP has no channel binding, so -PLUS mechanisms are never selected.
+type Authenticator struct {
+	Username string
+	Password string
+
+	mechanism string
+	client    sasl.Client
+}
+
+// Start picks the strongest offered mechanism and returns its name and
+// the initial response.
+func (a *Authenticator) Start(mechanisms []string) (string, []byte, error) {
+	if a.client != nil {
+		return "", nil, fmt.Errorf("amqpauth: exchange already started")
+	}
+
+	mech, err := sasl.Negotiate(mechanisms, false)
+	if err != nil {
+		return "", nil, err
+	}
+	client, err := mech.NewClient(sasl.ClientConfig{Username: a.Username, Password: a.Password})
+	if err != nil {
+		return "", nil, err
+	}
+	initial, err := client.Start()
+	if err != nil {
+		return "", nil, err
+	}
+
+	a.mechanism, a.client = mech.Name, client
+	return mech.Name, initial, nil
+}
+
+// Challenge answers a connection.secure or sasl-challenge frame.
+func (a *Authenticator) Challenge(data []byte) ([]byte, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("amqpauth: unexpected challenge")
+	}
+	resp, err := a.client.Next(data)
+	if err == nil && resp == nil {
+		// The server-final message still needs an (empty) reply.
+		resp = []byte{}
+	}
+	return resp, err
+}
+
+// Outcome checks a successful AMQP 1.0 sasl-outcome, whose additional
+// data carries the server-final message. For AMQP 0-9-1, where the
+// server-final message arrives as a last connection.secure challenge,
+// call it with nil when connection.tune arrives.
+func (a *Authenticator) Outcome(additionalData []byte) error {
+	if a.client == nil {
+		return fmt.Errorf("amqpauth: unexpected outcome")
+	}
+	if additionalData != nil {
+		if _, err := a.client.Next(additionalData); err != nil {
+			return err
+		}
+	}
+	if !a.client.Done() {
+		return fmt.Errorf("amqpauth: server accepted authentication before proving its identity")
+	}
+	return nil
+}
+
+// Mechanism returns the negotiated mechanism, or "" before Start.
+func (a *Authenticator) Mechanism() string {
+	return a.mechanism
+}
+
+// ParseMechanisms splits the space-separated mechanisms field of an AMQP
+// 0-9-1 connection.start method.
+func ParseMechanisms(field string) []string {
+	return strings.Fields(field)
+}
@@ -0,0 +1,82 @@
+package sasl
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// ChannelBinding identifies the secure channel an exchange is bound to, as
+// described in RFC 5056.
+type ChannelBinding struct {
+	Type string
+	Data []byte
+}
+
+// TLSServerEndPoint returns the RFC 5929 tls-server-end-point binding for
+// the certificate the server presented. Clients pass the first peer
+// certificate; servers pass their own leaf certificate.
+func TLSServerEndPoint(cert *x509.Certificate) (*ChannelBinding, error) {
+	if cert == nil {
+		return nil, errors.New("sasl: no server certificate for tls-server-end-point")
+	}
+
+	// MD5 and SHA-1 signatures are upgraded to SHA-256 per RFC 5929 section 4.1.
+	h := crypto.SHA256
+	switch cert.SignatureAlgorithm {
+	case x509.SHA384WithRSA, x509.ECDSAWithSHA384, x509.SHA384WithRSAPSS:
+		h = crypto.SHA384
+	case x509.SHA512WithRSA, x509.ECDSAWithSHA512, x509.SHA512WithRSAPSS:
+		h = crypto.SHA512
+	}
+
+	digest := h.New()
+	digest.Write(cert.Raw)
+	return &ChannelBinding{Type: "tls-server-end-point", Data: digest.Sum(nil)}, nil
+}
+
+// TLSUnique returns the RFC 5929 tls-unique binding. It is only defined
+// for TLS 1.2 and earlier, and crypto/tls withholds it for resumed
+// sessions without the extended master secret, where it is unsafe.
+func TLSUnique(state *tls.ConnectionState) (*ChannelBinding, error) {
+	if state == nil || state.Version >= tls.VersionTLS13 {
+		return nil, errors.New("sasl: tls-unique is not defined for TLS 1.3")
+	}
+	if len(state.TLSUnique) == 0 {
+		return nil, errors.New("sasl: tls-unique is not available for this connection")
+	}
+	return &ChannelBinding{Type: "tls-unique", Data: state.TLSUnique}, nil
+}
+
+// TLSExporter returns the RFC 9266 tls-exporter binding: 32 bytes of
+// keying material exported with the label "EXPORTER-Channel-Binding" and
+// no context.
+func TLSExporter(state *tls.ConnectionState) (*ChannelBinding, error) {
+	if state == nil {
+		return nil, errors.New("sasl: no TLS connection for tls-exporter")
+	}
+	data, err := state.ExportKeyingMaterial("EXPORTER-Channel-Binding", nil, 32)
+	if err != nil {
+		return nil, err
+	}
+	return &ChannelBinding{Type: "tls-exporter", Data: data}, nil
+}
+
+// ChannelBindingFor picks the binding for a connection by its TLS
+// version: tls-exporter for TLS 1.3 and tls-unique before it. Both sides
+// of a connection make the same choice. Protocols that mandate a type,
+// such as PostgreSQL's tls-server-end-point, should call that function
+// directly instead.
+func ChannelBindingFor(state *tls.ConnectionState) (*ChannelBinding, error) {
+	if state == nil {
+		return nil, errors.New("sasl: channel binding requires a TLS connection")
+	}
+	if state.Version >= tls.VersionTLS13 {
+		return TLSExporter(state)
+	}
+	return TLSUnique(state)
+}
@@ -0,0 +1,119 @@
+// Package kafkaauth adapts the SCRAM client conversation to Kafka
+// clients. It has no dependency on them: SCRAMClient satisfies sarama's
+// SCRAMClient interface structurally, and Session has the method set of
+// franz-go's sasl.Session.
+package kafkaauth
+
+import (
+	"fmt"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	_ "github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// Kafka brokers support these mechanisms, without channel binding.
+const (
+	SHA256 = "SCRAM-SHA-256"
+	SHA512 = "SCRAM-SHA-512"
+)
+
+// SCRAMClient implements sarama's SCRAMClient interface. Use it as
+//
+//	cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+//	cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+//		return kafkaauth.NewSCRAMClient(kafkaauth.SHA512)
+//	}
+type SCRAMClient struct {
+	mechanism string
+	client    sasl.Client
+	started   bool
+}
+
+// NewSCRAMClient returns a client for mechanism, SHA256 or SHA512.
+func NewSCRAMClient(mechanism string) *SCRAMClient {
+	return &SCRAMClient{mechanism: mechanism}
+}
+
+// Begin prepares the conversation. Authorization identities are not
+// supported.
+func (c *SCRAMClient) Begin(userName, password, authzID string) error {
+	if authzID != "" {
+		return fmt.Errorf("kafkaauth: authorization identities are not supported")
+	}
+	client, err := newClient(c.mechanism, userName, password)
+	if err != nil {
+		return err
+	}
+	c.client, c.started = client, false
+	return nil
+}
+
+// Step returns the client-first message on its first call and answers
+// each server message after that.
+func (c *SCRAMClient) Step(challenge string) (string, error) {
+	if c.client == nil {
+		return "", fmt.Errorf("kafkaauth: Step called before Begin")
+	}
+	if !c.started {
+		c.started = true
+		resp, err := c.client.Start()
+		return string(resp), err
+	}
+	resp, err := c.client.Next([]byte(challenge))
+	return string(resp), err
+}
+
+// Done reports whether the server's signature was verified.
+func (c *SCRAMClient) Done() bool {
+	return c.client != nil && c.client.Done()
+}
+
+// Session drives one franz-go authentication. franz-go's Mechanism
+// returns its own sasl.Session type, so wrap Authenticate in a small
+// Mechanism of your own:
+//
+//	func (m scramMechanism) Authenticate(ctx context.Context, host string) (sasl.Session, []byte, error) {
+//		s, first, err := kafkaauth.Authenticate(kafkaauth.SHA512, m.user, m.pass)
+//		if err != nil {
+//			return nil, nil, err
+//		}
+//		return s, first, nil
+//	}
+type Session struct {
+	client sasl.Client
+}
+
+// Authenticate starts a conversation and returns the session with the
+// client-first message.
+func Authenticate(mechanism, username, password string) (*Session, []byte, error) {
+	client, err := newClient(mechanism, username, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	first, err := client.Start()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Session{client: client}, first, nil
+}
+
+// Challenge answers a server message, reporting done once the server's
+// signature has been verified.
+func (s *Session) Challenge(resp []byte) (bool, []byte, error) {
+	next, err := s.client.Next(resp)
+	if err != nil {
+		return false, nil, err
+	}
+	return s.client.Done(), next, nil
+}
+
+func newClient(mechanism, username, password string) (sasl.Client, error) {
+	if mechanism != SHA256 && mechanism != SHA512 {
+		return nil, fmt.Errorf("kafkaauth: unsupported mechanism %q", mechanism)
+	}
+	mech, ok := sasl.Lookup(mechanism)
+	if !ok {
+		return nil, fmt.Errorf("kafkaauth: mechanism %q is not registered", mechanism)
+	}
+	return mech.NewClient(sasl.ClientConfig{Username: username, Password: password})
+}
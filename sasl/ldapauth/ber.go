@@ -0,0 +1,192 @@
+package ldapauth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// BER tags of the LDAP messages Bind sends and receives (RFC 4511).
+const (
+	tagInteger         = 0x02
+	tagOctetString     = 0x04
+	tagEnumerated      = 0x0a
+	tagSequence        = 0x30
+	tagBindRequest     = 0x60
+	tagBindResponse    = 0x61
+	tagSASLCredentials = 0xa3
+	tagServerSASLCreds = 0x87
+	tagExtendedResp    = 0x78
+)
+
+// maxMessageSize bounds a BindResponse, which carries at most a
+// server-first or server-final message and a diagnostic.
+const maxMessageSize = 1 << 16
+
+// bindRequest encodes an LDAPMessage holding a SASL BindRequest. creds is
+// omitted when nil, as for a mechanism without an initial response.
+func bindRequest(id int64, mechanism string, creds []byte) []byte {
+	auth := element(tagOctetString, []byte(mechanism))
+	if creds != nil {
+		auth = append(auth, element(tagOctetString, creds)...)
+	}
+	var op []byte
+	op = append(op, integer(tagInteger, 3)...)
+	op = append(op, element(tagOctetString, nil)...)
+	op = append(op, element(tagSASLCredentials, auth)...)
+
+	msg := integer(tagInteger, id)
+	msg = append(msg, element(tagBindRequest, op)...)
+	return element(tagSequence, msg)
+}
+
+func element(tag byte, content []byte) []byte {
+	b := []byte{tag}
+	if n := len(content); n < 0x80 {
+		b = append(b, byte(n))
+	} else {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(n))
+		b = append(b, 0x84)
+		b = append(b, length[:]...)
+	}
+	return append(b, content...)
+}
+
+func integer(tag byte, v int64) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(v)}, content...)
+		if (v < 0x80 && v >= -0x80) || len(content) == 8 {
+			break
+		}
+		v >>= 8
+	}
+	return element(tag, content)
+}
+
+type bindResponse struct {
+	resultCode  int
+	diagnostic  string
+	serverCreds []byte
+}
+
+// readBindResponse reads one LDAPMessage from r, without reading past
+// it, and decodes the BindResponse it must hold.
+func readBindResponse(r io.Reader, id int64) (bindResponse, error) {
+	var resp bindResponse
+
+	tag, msg, err := readElement(r)
+	if err != nil {
+		return resp, err
+	}
+	if tag != tagSequence {
+		return resp, fmt.Errorf("ldapauth: malformed LDAPMessage")
+	}
+	fields, err := parseElements(msg)
+	if err != nil || len(fields) < 2 || fields[0].tag != tagInteger {
+		return resp, fmt.Errorf("ldapauth: malformed LDAPMessage")
+	}
+	op := fields[1]
+	if op.tag == tagExtendedResp {
+		// A Notice of Disconnection, sent with message ID 0.
+		if result, err := parseResult(op.content); err == nil {
+			return resp, &BindError{ResultCode: result.resultCode, Message: "server closed the connection: " + result.diagnostic}
+		}
+		return resp, fmt.Errorf("ldapauth: server closed the connection")
+	}
+	if got := parseInteger(fields[0].content); got != id {
+		return resp, fmt.Errorf("ldapauth: response to message %d while waiting for %d", got, id)
+	}
+	if op.tag != tagBindResponse {
+		return resp, fmt.Errorf("ldapauth: expected BindResponse, got tag 0x%02x", op.tag)
+	}
+	return parseResult(op.content)
+}
+
+// parseResult decodes an LDAPResult and any serverSaslCreds after it.
+func parseResult(content []byte) (bindResponse, error) {
+	var resp bindResponse
+	fields, err := parseElements(content)
+	if err != nil || len(fields) < 3 || fields[0].tag != tagEnumerated {
+		return resp, fmt.Errorf("ldapauth: malformed BindResponse")
+	}
+	resp.resultCode = int(parseInteger(fields[0].content))
+	resp.diagnostic = string(fields[2].content)
+	for _, f := range fields[3:] {
+		if f.tag == tagServerSASLCreds {
+			resp.serverCreds = f.content
+		}
+	}
+	return resp, nil
+}
+
+type berElement struct {
+	tag     byte
+	content []byte
+}
+
+func readElement(r io.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	n := int(header[1])
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		if size == 0 || size > 4 {
+			return 0, nil, fmt.Errorf("ldapauth: unsupported BER length")
+		}
+		var length [4]byte
+		if _, err := io.ReadFull(r, length[4-size:]); err != nil {
+			return 0, nil, err
+		}
+		n = int(binary.BigEndian.Uint32(length[:]))
+	}
+	if n > maxMessageSize {
+		return 0, nil, fmt.Errorf("ldapauth: message of %d bytes is too large", n)
+	}
+	content := make([]byte, n)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return 0, nil, err
+	}
+	return header[0], content, nil
+}
+
+func parseElements(b []byte) ([]berElement, error) {
+	var elements []berElement
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		tag, n, b2 := b[0], int(b[1]), b[2:]
+		if n&0x80 != 0 {
+			size := n & 0x7f
+			if size == 0 || size > 4 || len(b2) < size {
+				return nil, fmt.Errorf("ldapauth: unsupported BER length")
+			}
+			n = 0
+			for _, c := range b2[:size] {
+				n = n<<8 | int(c)
+			}
+			b2 = b2[size:]
+		}
+		if n < 0 || n > len(b2) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		elements = append(elements, berElement{tag: tag, content: b2[:n]})
+		b = b2[n:]
+	}
+	return elements, nil
+}
+
+func parseInteger(b []byte) int64 {
+	var v int64
+	for i, c := range b {
+		if i == 0 && c&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(c)
+	}
+	return v
+}
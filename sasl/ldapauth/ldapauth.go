@@ -0,0 +1,177 @@
+// Package ldapauth performs an LDAP SASL bind (RFC 4513) with SCRAM, so
+// directory clients can authenticate without sending the password in a
+// simple bind.
+//
+// go-ldap's SASL binds are tied to their mechanism (GSSAPIBind always
+// names GSSAPI), so Bind runs the exchange itself on a connection before
+// it is handed to go-ldap:
+//
+//	conn, err := tls.Dial("tcp", "ldap.example.com:636", tlsConfig)
+//	state := conn.ConnectionState()
+//	auth := &ldapauth.Authenticator{Username: "alice", Password: secret, TLS: &state}
+//	if err := ldapauth.Bind(conn, auth); err != nil {
+//		return err
+//	}
+//	l := ldap.NewConn(conn, true)
+//	l.Start()
+//
+// Code that encodes its own BindRequests can call Start, Challenge and
+// Finish with the SASL credentials the requests and responses carry.
+package ldapauth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	_ "github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// BindResponse result codes used in the exchange.
+const (
+	ResultSuccess            = 0
+	ResultSASLBindInProgress = 14
+)
+
+// Authenticator holds the client side of one connection's SASL bind.
+type Authenticator struct {
+	Username string
+	Password string
+	// Authzid is the identity to act as, such as "dn:cn=admin,dc=example".
+	Authzid string
+
+	// Mechanisms are the server's supportedSASLMechanisms from the root
+	// DSE. When nil, SCRAM-SHA-256 is used without channel binding and
+	// TLS and ChannelBinding are ignored.
+	Mechanisms []string
+
+	// TLS is the state of the connection to the server. When it is set
+	// and the server offers a -PLUS mechanism, the bind is bound to the
+	// channel.
+	TLS *tls.ConnectionState
+
+	// ChannelBinding, when set, is used instead of the binding derived
+	// from TLS.
+	ChannelBinding *sasl.ChannelBinding
+
+	mechanism string
+	client    sasl.Client
+}
+
+// Start picks the strongest offered mechanism and returns its name and
+// the credentials for the first BindRequest.
+func (a *Authenticator) Start() (string, []byte, error) {
+	if a.client != nil {
+		return "", nil, fmt.Errorf("ldapauth: bind already started")
+	}
+
+	// Without the server's mechanism list the client cannot tell whether
+	// -PLUS was withheld, so it must send the gs2 flag "n" rather than "y",
+	// which a server offering -PLUS would reject as a downgrade.
+	var cb *sasl.ChannelBinding
+	mechanisms := a.Mechanisms
+	if mechanisms == nil {
+		mechanisms = []string{"SCRAM-SHA-256"}
+	} else if cb = a.ChannelBinding; cb == nil && a.TLS != nil {
+		var err error
+		if cb, err = sasl.ChannelBindingFor(a.TLS); err != nil {
+			return "", nil, err
+		}
+	}
+
+	mech, err := sasl.Negotiate(mechanisms, cb != nil)
+	if err != nil {
+		return "", nil, err
+	}
+	client, err := mech.NewClient(sasl.ClientConfig{
+		Username:       a.Username,
+		Password:       a.Password,
+		Authzid:        a.Authzid,
+		ChannelBinding: cb,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	initial, err := client.Start()
+	if err != nil {
+		return "", nil, err
+	}
+
+	a.mechanism, a.client = mech.Name, client
+	return mech.Name, initial, nil
+}
+
+// Challenge answers the serverSaslCreds of a saslBindInProgress response
+// with the credentials for the next BindRequest.
+func (a *Authenticator) Challenge(serverCreds []byte) ([]byte, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("ldapauth: unexpected saslBindInProgress")
+	}
+	return a.client.Next(serverCreds)
+}
+
+// Finish checks the serverSaslCreds of the successful BindResponse, which
+// carry the server-final message.
+func (a *Authenticator) Finish(serverCreds []byte) error {
+	if a.client == nil {
+		return fmt.Errorf("ldapauth: unexpected bind success")
+	}
+	if serverCreds != nil {
+		if _, err := a.client.Next(serverCreds); err != nil {
+			return err
+		}
+	}
+	if !a.client.Done() {
+		return fmt.Errorf("ldapauth: server accepted the bind before proving its identity")
+	}
+	return nil
+}
+
+// Mechanism returns the negotiated mechanism, or "" before Start.
+func (a *Authenticator) Mechanism() string {
+	return a.mechanism
+}
+
+// BindError is a BindResponse whose result was neither success nor
+// saslBindInProgress, such as invalidCredentials (49).
+type BindError struct {
+	ResultCode int
+	Message    string
+}
+
+func (e *BindError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ldapauth: bind failed with result code %d", e.ResultCode)
+	}
+	return fmt.Sprintf("ldapauth: bind failed with result code %d: %s", e.ResultCode, e.Message)
+}
+
+// Bind authenticates conn with a. It must run before any other LDAP
+// operation on the connection, and reads exactly the bind responses, so
+// conn can be handed to an LDAP client afterwards.
+func Bind(conn io.ReadWriter, a *Authenticator) error {
+	mech, creds, err := a.Start()
+	if err != nil {
+		return err
+	}
+	for id := int64(1); ; id++ {
+		if _, err := conn.Write(bindRequest(id, mech, creds)); err != nil {
+			return err
+		}
+		resp, err := readBindResponse(conn, id)
+		if err != nil {
+			return err
+		}
+		switch resp.resultCode {
+		case ResultSuccess:
+			return a.Finish(resp.serverCreds)
+		case ResultSASLBindInProgress:
+			if creds, err = a.Challenge(resp.serverCreds); err != nil {
+				return err
+			}
+		default:
+			return &BindError{ResultCode: resp.resultCode, Message: resp.diagnostic}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package ldapauth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+)
+
+func TestStartGS2Flag(t *testing.T) {
+	cb := &sasl.ChannelBinding{Type: "tls-server-end-point", Data: make([]byte, 32)}
+	for _, tc := range []struct {
+		name       string
+		mechanisms []string
+		want       string
+	}{
+		{"mechanisms unknown", nil, "n,,"},
+		{"plus not offered", []string{"SCRAM-SHA-256"}, "y,,"},
+		{"plus offered", []string{"SCRAM-SHA-256", "SCRAM-SHA-256-PLUS"}, "p=tls-server-end-point,,"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &Authenticator{Username: "alice", Password: "secret", Mechanisms: tc.mechanisms, ChannelBinding: cb}
+			_, initial, err := a.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(initial), tc.want) {
+				t.Errorf("client-first %q, want prefix %q", initial, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package pgauth drives a SCRAM conversation over PostgreSQL's SASL
+// authentication messages, for drivers, poolers and proxies that handle
+// the wire protocol themselves.
+//
+// The methods map onto the pgproto3 message types used by pgx:
+// AuthenticationSASL.AuthMechanisms goes to Start, whose results fill a
+// SASLInitialResponse; AuthenticationSASLContinue.Data goes to Continue,
+// whose result fills a SASLResponse; AuthenticationSASLFinal.Data goes to
+// Final. Check Done when AuthenticationOk arrives.
+package pgauth
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	_ "github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// Authenticator holds the client side of one connection's SASL exchange.
+type Authenticator struct {
+	// Password is the role's password. PostgreSQL takes the user name
+	// from the startup message, so none is sent in the exchange.
+	Password string
+
+	// TLS is the state of the connection to the server. When it holds a
+	// peer certificate, tls-server-end-point channel binding is used and
+	// a -PLUS mechanism is preferred.
+	TLS *tls.ConnectionState
+
+	// Allowed, when set, limits negotiation to these mechanisms, for
+	// example to refuse anything but SCRAM-SHA-256-PLUS.
+	Allowed []string
+
+	// ChannelBinding, when set, is used instead of the binding derived
+	// from TLS.
+	ChannelBinding *sasl.ChannelBinding
+
+	// HidePlus makes the exchange proceed as if the server had offered no
+	// -PLUS mechanism, so a client able to bind sends the gs2 flag "y".
+	// A server that did offer -PLUS must then reject the exchange as a
+	// downgrade; conformance tests use it to check that it does.
+	HidePlus bool
+
+	offered   []string
+	mechanism string
+	client    sasl.Client
+}
+
+// Start picks the strongest offered mechanism and returns its name and
+// the client-first message.
+func (a *Authenticator) Start(mechanisms []string) (string, []byte, error) {
+	if a.client != nil {
+		return "", nil, fmt.Errorf("pgauth: exchange already started")
+	}
+
+	a.offered = mechanisms
+	plusAdvertised := sasl.PlusOffered(mechanisms)
+	if a.HidePlus {
+		var visible []string
+		for _, name := range mechanisms {
+			if !strings.HasSuffix(strings.ToUpper(name), "-PLUS") {
+				visible = append(visible, name)
+			}
+		}
+		mechanisms, plusAdvertised = visible, false
+	}
+
+	cb := a.ChannelBinding
+	if cb == nil && a.TLS != nil && len(a.TLS.PeerCertificates) > 0 {
+		var err error
+		if cb, err = sasl.TLSServerEndPoint(a.TLS.PeerCertificates[0]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if a.Allowed != nil {
+		var allowed []string
+		for _, name := range mechanisms {
+			for _, allow := range a.Allowed {
+				if strings.EqualFold(name, allow) {
+					allowed = append(allowed, name)
+					break
+				}
+			}
+		}
+		mechanisms = allowed
+	}
+
+	mech, err := sasl.Negotiate(mechanisms, cb != nil)
+	if err != nil {
+		return "", nil, err
+	}
+	// Allowed may have removed the -PLUS mechanisms Negotiate would have
+	// seen, so the full offer decides the gs2 flag.
+	client, err := mech.NewClient(sasl.ClientConfig{Password: a.Password, ChannelBinding: cb, PlusAdvertised: plusAdvertised})
+	if err != nil {
+		return "", nil, err
+	}
+	initial, err := client.Start()
+	if err != nil {
+		return "", nil, err
+	}
+
+	a.mechanism, a.client = mech.Name, client
+	return mech.Name, initial, nil
+}
+
+// Continue processes the server-first message and returns the
+// client-final message.
+func (a *Authenticator) Continue(data []byte) ([]byte, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("pgauth: unexpected SASL continue message")
+	}
+	return a.client.Next(data)
+}
+
+// Final verifies the server-final message.
+func (a *Authenticator) Final(data []byte) error {
+	if a.client == nil {
+		return fmt.Errorf("pgauth: unexpected SASL final message")
+	}
+	_, err := a.client.Next(data)
+	return err
+}
+
+// Done reports whether the server has proved it knows the password. A
+// server that sends AuthenticationOk before that must not be trusted.
+func (a *Authenticator) Done() bool {
+	return a.client != nil && a.client.Done()
+}
+
+// Offered returns the mechanisms the server offered, or nil before Start.
+func (a *Authenticator) Offered() []string {
+	return a.offered
+}
+
+// Mechanism returns the negotiated mechanism, or "" before Start.
+func (a *Authenticator) Mechanism() string {
+	return a.mechanism
+}
+
+// ParseMechanisms splits the body of an AuthenticationSASL message, after
+// its 4-byte code, into mechanism names.
+func ParseMechanisms(data []byte) []string {
+	var mechanisms []string
+	for _, name := range strings.Split(string(data), "\x00") {
+		if name != "" {
+			mechanisms = append(mechanisms, name)
+		}
+	}
+	return mechanisms
+}
+
+// InitialResponse encodes the body of a SASLInitialResponse message.
+func InitialResponse(mechanism string, data []byte) []byte {
+	msg := append([]byte(mechanism), 0)
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(data)))
+	return append(msg, data...)
+}
@@ -0,0 +1,190 @@
+// Package sasl defines mechanism-independent SASL client and server
+// interfaces and a registry through which mechanisms make themselves
+// available to protocol libraries.
+//
+// Mechanisms register from their package init function, so a program
+// selects the implementations it wants with a blank import:
+//
+//	import _ "github.com/SonOfBytes/scram-sha-256/scram"
+package sasl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Client is the client side of a SASL exchange.
+type Client interface {
+	// Start returns the initial response sent along with the mechanism name.
+	Start() ([]byte, error)
+	// Next processes a server challenge and returns the response to send.
+	Next(challenge []byte) ([]byte, error)
+	// Done reports whether the exchange completed successfully.
+	Done() bool
+}
+
+// Server is the server side of a SASL exchange.
+type Server interface {
+	// Next processes a client response and returns the challenge to send.
+	// done is true once the exchange has finished; a non-nil error means
+	// authentication failed, in which case challenge may still carry a
+	// mechanism-specific error message for the client.
+	Next(response []byte) (challenge []byte, done bool, err error)
+}
+
+// ClientConfig carries the credentials a client mechanism authenticates with.
+type ClientConfig struct {
+	Username string
+	Password string
+	// Authzid is the identity to act as, for mechanisms that support one.
+	Authzid string
+	// ChannelBinding is required by -PLUS mechanisms. With other mechanisms
+	// it tells the server the client could have bound to the channel,
+	// unless PlusAdvertised is set.
+	ChannelBinding *ChannelBinding
+	// PlusAdvertised is set when the server offered -PLUS mechanisms but
+	// the client is using another one, for instance because it was
+	// restricted to a plain mechanism. Claiming it could have bound would
+	// then look like a downgrade attack to the server, so it is not
+	// claimed. Mechanisms returned by Negotiate set it themselves.
+	PlusAdvertised bool
+}
+
+// ServerConfig carries what a server mechanism needs to check credentials.
+type ServerConfig struct {
+	// Lookup returns the stored secret for username in the mechanism's
+	// native encoding, for SCRAM the PostgreSQL-style verifier string.
+	Lookup func(username string) (string, error)
+	// ChannelBinding is required by -PLUS mechanisms and ignored otherwise.
+	ChannelBinding *ChannelBinding
+	// PlusAdvertised is set when -PLUS mechanisms were offered to the
+	// client, letting other mechanisms detect a downgrade.
+	PlusAdvertised bool
+	// Authorize decides whether username may act as authzid. Requests for
+	// another identity are refused when it is nil.
+	Authorize func(username, authzid string) error
+}
+
+// Mechanism describes a registered SASL mechanism.
+type Mechanism struct {
+	Name string
+	// Priority orders mechanisms during negotiation; higher is preferred.
+	Priority int
+	// Plus reports whether the mechanism requires channel binding.
+	Plus bool
+
+	NewClient func(ClientConfig) (Client, error)
+	NewServer func(ServerConfig) (Server, error)
+}
+
+// ErrNoMechanism is returned by Negotiate when no offered mechanism is usable.
+var ErrNoMechanism = errors.New("sasl: no supported mechanism offered")
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Mechanism)
+)
+
+// Register makes a mechanism available by name. It panics if the name is
+// empty, has no constructors, or is registered twice.
+func Register(m Mechanism) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if m.Name == "" {
+		panic("sasl: Register mechanism with empty name")
+	}
+	if m.NewClient == nil && m.NewServer == nil {
+		panic("sasl: Register mechanism " + m.Name + " without constructors")
+	}
+	key := strings.ToUpper(m.Name)
+	if _, dup := registry[key]; dup {
+		panic("sasl: Register called twice for mechanism " + m.Name)
+	}
+	registry[key] = m
+}
+
+// Lookup returns the mechanism registered under name, ignoring case.
+func Lookup(name string) (Mechanism, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	m, ok := registry[strings.ToUpper(name)]
+	return m, ok
+}
+
+// Mechanisms returns the names of all registered mechanisms, most preferred
+// first.
+func Mechanisms() []string {
+	registryMu.RLock()
+	mechs := make([]Mechanism, 0, len(registry))
+	for _, m := range registry {
+		mechs = append(mechs, m)
+	}
+	registryMu.RUnlock()
+
+	sortMechanisms(mechs)
+	names := make([]string, len(mechs))
+	for i, m := range mechs {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Negotiate picks the most preferred registered mechanism among those the
+// peer offered. -PLUS mechanisms are only considered when channelBinding is
+// true, and are then chosen over any other mechanism, since a server that
+// offers them rejects clients that could bind but did not.
+func Negotiate(offered []string, channelBinding bool) (Mechanism, error) {
+	var candidates, plus []Mechanism
+	for _, name := range offered {
+		m, ok := Lookup(name)
+		if !ok || (m.Plus && !channelBinding) {
+			continue
+		}
+		candidates = append(candidates, m)
+		if m.Plus {
+			plus = append(plus, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return Mechanism{}, fmt.Errorf("%w: %s", ErrNoMechanism, strings.Join(offered, " "))
+	}
+	if len(plus) > 0 {
+		candidates = plus
+	}
+
+	sortMechanisms(candidates)
+	m := candidates[0]
+	if PlusOffered(offered) && m.NewClient != nil {
+		newClient := m.NewClient
+		m.NewClient = func(cfg ClientConfig) (Client, error) {
+			cfg.PlusAdvertised = true
+			return newClient(cfg)
+		}
+	}
+	return m, nil
+}
+
+// PlusOffered reports whether offered includes a -PLUS mechanism, known
+// to this registry or not.
+func PlusOffered(offered []string) bool {
+	for _, name := range offered {
+		if strings.HasSuffix(strings.ToUpper(name), "-PLUS") {
+			return true
+		}
+	}
+	return false
+}
+
+func sortMechanisms(mechs []Mechanism) {
+	sort.Slice(mechs, func(i, j int) bool {
+		if mechs[i].Priority != mechs[j].Priority {
+			return mechs[i].Priority > mechs[j].Priority
+		}
+		return mechs[i].Name < mechs[j].Name
+	})
+}
@@ -0,0 +1,62 @@
+// Package smtpauth adapts the SCRAM client conversation to net/smtp.
+package smtpauth
+
+import (
+	"fmt"
+	"net/smtp"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	_ "github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+type auth struct {
+	cfg    sasl.ClientConfig
+	client sasl.Client
+}
+
+// New returns an smtp.Auth that authenticates with the strongest SCRAM
+// mechanism the server advertises. net/smtp does not expose the TLS
+// connection state, so -PLUS mechanisms are never selected.
+func New(username, password string) smtp.Auth {
+	return &auth{cfg: sasl.ClientConfig{Username: username, Password: password}}
+}
+
+func (a *auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	mech, err := sasl.Negotiate(server.Auth, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	a.client, err = mech.NewClient(a.cfg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	initial, err := a.client.Start()
+	if err != nil {
+		return "", nil, err
+	}
+	return mech.Name, initial, nil
+}
+
+func (a *auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		// The server reported success; only accept it once the client has
+		// verified the server signature.
+		if !a.client.Done() {
+			return nil, fmt.Errorf("smtpauth: server completed authentication before proving its identity")
+		}
+		return nil, nil
+	}
+
+	resp, err := a.client.Next(fromServer)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		// net/smtp stops the exchange on a nil response, but the server is
+		// still waiting for the empty reply to its server-final message.
+		resp = []byte{}
+	}
+	return resp, nil
+}
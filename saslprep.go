@@ -0,0 +1,90 @@
+package main
+
+import (
+	"unicode"
+)
+
+// ProhibitedCharacterError reports a character that SASLprep (RFC 4013)
+// forbids, so servers normalizing the password would reject it.
+type ProhibitedCharacterError struct {
+	Rune     rune
+	Position int // 1-based, in characters
+	Reason   string
+}
+
+func (e *ProhibitedCharacterError) Error() string {
+	return msg.Sprintf("password contains %s U+%04X at position %d", msg.Text(e.Reason), e.Rune, e.Position)
+}
+
+// saslprepProhibited lists the RFC 3454 tables RFC 4013 section 2.3
+// prohibits, other than ASCII controls and unassigned code points which
+// are checked separately.
+var saslprepProhibited = []struct {
+	reason string
+	table  *unicode.RangeTable
+}{
+	{"non-ASCII control character", &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{0x0080, 0x009f, 1}, {0x06dd, 0x06dd, 1}, {0x070f, 0x070f, 1}, {0x180e, 0x180e, 1},
+			{0x200c, 0x200d, 1}, {0x2028, 0x2029, 1}, {0x2060, 0x2063, 1}, {0x206a, 0x206f, 1},
+			{0xfeff, 0xfeff, 1}, {0xfff9, 0xfffc, 1},
+		},
+		R32: []unicode.Range32{{0x1d173, 0x1d17a, 1}},
+	}},
+	{"private use character", &unicode.RangeTable{
+		R16: []unicode.Range16{{0xe000, 0xf8ff, 1}},
+		R32: []unicode.Range32{{0xf0000, 0xffffd, 1}, {0x100000, 0x10fffd, 1}},
+	}},
+	{"non-character code point", &unicode.RangeTable{
+		R16: []unicode.Range16{{0xfdd0, 0xfdef, 1}, {0xfffe, 0xffff, 1}},
+		R32: []unicode.Range32{
+			{0x1fffe, 0x1ffff, 1}, {0x2fffe, 0x2ffff, 1}, {0x3fffe, 0x3ffff, 1}, {0x4fffe, 0x4ffff, 1},
+			{0x5fffe, 0x5ffff, 1}, {0x6fffe, 0x6ffff, 1}, {0x7fffe, 0x7ffff, 1}, {0x8fffe, 0x8ffff, 1},
+			{0x9fffe, 0x9ffff, 1}, {0xafffe, 0xaffff, 1}, {0xbfffe, 0xbffff, 1}, {0xcfffe, 0xcffff, 1},
+			{0xdfffe, 0xdffff, 1}, {0xefffe, 0xeffff, 1}, {0xffffe, 0xfffff, 1}, {0x10fffe, 0x10ffff, 1},
+		},
+	}},
+	{"surrogate code point", &unicode.RangeTable{
+		R16: []unicode.Range16{{0xd800, 0xdfff, 1}},
+	}},
+	{"character inappropriate for plain text", &unicode.RangeTable{
+		R16: []unicode.Range16{{0xfffd, 0xfffd, 1}},
+	}},
+	{"ideographic description character", &unicode.RangeTable{
+		R16: []unicode.Range16{{0x2ff0, 0x2ffb, 1}},
+	}},
+	{"display-changing or deprecated character", &unicode.RangeTable{
+		R16: []unicode.Range16{{0x0340, 0x0341, 1}, {0x200e, 0x200f, 1}, {0x202a, 0x202e, 1}},
+	}},
+	{"tagging character", &unicode.RangeTable{
+		R32: []unicode.Range32{{0xe0001, 0xe0001, 1}, {0xe0020, 0xe007f, 1}},
+	}},
+}
+
+// assigned covers every general category except Cn (unassigned).
+var assigned = []*unicode.RangeTable{
+	unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Z,
+	unicode.Cc, unicode.Cf, unicode.Co, unicode.Cs,
+}
+
+// checkSASLprep returns a ProhibitedCharacterError for the first character
+// in password that RFC 4013 prohibits. password must be valid UTF-8.
+func checkSASLprep(password string) error {
+	position := 0
+	for _, r := range password {
+		position++
+
+		if r < 0x20 || r == 0x7f {
+			return &ProhibitedCharacterError{Rune: r, Position: position, Reason: "control character"}
+		}
+		for _, p := range saslprepProhibited {
+			if unicode.Is(p.table, r) {
+				return &ProhibitedCharacterError{Rune: r, Position: position, Reason: p.reason}
+			}
+		}
+		if !unicode.IsOneOf(assigned, r) {
+			return &ProhibitedCharacterError{Rune: r, Position: position, Reason: "unassigned code point"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package scram_test
+
+import (
+	"crypto"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+// awkward has a username that needs escaping in a saslname.
+var awkward = scramtest.Fixture{Username: "ops,team=db", Password: "pencil", Hash: crypto.SHA256, Salt: []byte("scramtest-awkwrd"), Iterations: 4096}
+
+func TestAuthzidEscaping(t *testing.T) {
+	client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: awkward.Username, Password: awkward.Password, Authzid: "dn:cn=admin,dc=example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var asked [2]string
+	server, err := scram.NewServer(scram.ServerConfig{
+		Hash:   crypto.SHA256,
+		Lookup: scramtest.Store(awkward),
+		Authorize: func(username, authzid string) error {
+			asked = [2]string{username, authzid}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript, err := scramtest.Exchange(client, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "n,a=dn:cn=3Dadmin=2Cdc=3Dexample,n=ops=2Cteam=3Ddb,r="; !strings.HasPrefix(transcript.ClientFirst, want) {
+		t.Errorf("client-first %q, want prefix %q", transcript.ClientFirst, want)
+	}
+	if server.Username() != awkward.Username || server.Authzid() != "dn:cn=admin,dc=example" {
+		t.Errorf("server saw user %q authzid %q", server.Username(), server.Authzid())
+	}
+	if asked != [2]string{awkward.Username, "dn:cn=admin,dc=example"} {
+		t.Errorf("Authorize called with %q", asked)
+	}
+}
+
+func TestAuthzidUnescapeRejectsMalformed(t *testing.T) {
+	for _, header := range []string{"n,a=admin=2X,", "n,a=admin=,", "n,a=a,b,", "n,b=admin,"} {
+		server, err := scramtest.NewServer(crypto.SHA256, scramtest.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := server.Next([]byte(header + "n=alice,r=fyko+d2lbbFgONRv9qkxdawL")); err == nil {
+			t.Errorf("%q: accepted", header)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	errDenied := errors.New("alice is not in the admins group")
+	for _, tc := range []struct {
+		name      string
+		authzid   string
+		authorize func(username, authzid string) error
+		called    bool
+		wantErr   error
+	}{
+		{name: "no authzid", authorize: func(string, string) error { return errDenied }},
+		{name: "authzid is the username", authzid: "alice", authorize: func(string, string) error { return errDenied }},
+		{name: "allowed", authzid: "admin", authorize: func(string, string) error { return nil }, called: true},
+		{name: "rejected", authzid: "admin", authorize: func(string, string) error { return errDenied }, called: true, wantErr: errDenied},
+		{name: "no hook", authzid: "admin", wantErr: scram.ErrNotAuthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: scramtest.Alice.Password, Authzid: tc.authzid})
+			if err != nil {
+				t.Fatal(err)
+			}
+			called := false
+			cfg := scram.ServerConfig{Hash: crypto.SHA256, Lookup: scramtest.Store(scramtest.Alice)}
+			if tc.authorize != nil {
+				cfg.Authorize = func(username, authzid string) error {
+					called = true
+					return tc.authorize(username, authzid)
+				}
+			}
+			server, err := scram.NewServer(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			transcript, err := scramtest.Exchange(client, server)
+			if called != tc.called {
+				t.Errorf("Authorize called: %v, want %v", called, tc.called)
+			}
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Errorf("exchange failed: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) || !errors.Is(err, scram.ErrNotAuthorized) {
+				t.Errorf("got %v, want %v wrapped in ErrNotAuthorized", err, tc.wantErr)
+			}
+			// The client learns only that it failed, not why.
+			if transcript.ServerFinal != "e=other-error" {
+				t.Errorf("server-final %q, want e=other-error", transcript.ServerFinal)
+			}
+		})
+	}
+}
+
+// TestAuthorizeAfterProof checks that a wrong password fails at the proof
+// check without consulting Authorize.
+func TestAuthorizeAfterProof(t *testing.T) {
+	client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: "wrong", Authzid: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := scram.NewServer(scram.ServerConfig{
+		Hash:   crypto.SHA256,
+		Lookup: scramtest.Store(scramtest.Alice),
+		Authorize: func(string, string) error {
+			t.Error("Authorize called before the proof was verified")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scramtest.Exchange(client, server); !errors.Is(err, scram.ErrProofMismatch) {
+		t.Errorf("got %v, want ErrProofMismatch", err)
+	}
+}
@@ -0,0 +1,155 @@
+package scram
+
+import (
+	"container/list"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+)
+
+// KeyCache is a KDF that remembers recent SaltedPassword results, so
+// workloads that verify the same credentials repeatedly, or clients that
+// reconnect often, skip redundant PBKDF2 work. Entries are keyed by an
+// HMAC of the hash, salt, iteration count and password under a random
+// per-cache key, so no unsalted password hash is kept. It is safe for
+// concurrent use.
+//
+// To use it everywhere, install it at startup:
+//
+//	scram.DefaultKDF = scram.NewKeyCache(nil, 1024)
+type KeyCache struct {
+	kdf    KDF
+	size   int
+	secret []byte
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List
+	hits    uint64
+	misses  uint64
+}
+
+type cacheEntry struct {
+	key   [sha256.Size]byte
+	value []byte
+}
+
+// CacheStats reports how effective a KeyCache has been.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// HitRate returns the fraction of lookups served from the cache.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// NewKeyCache returns a cache holding up to size results from kdf, or
+// PBKDF2 when kdf is nil. A size of zero or less disables caching, so the
+// cache can be switched off from configuration without changing callers.
+func NewKeyCache(kdf KDF, size int) *KeyCache {
+	if kdf == nil {
+		kdf = PBKDF2{}
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("scram: failed to generate cache key: " + err.Error())
+	}
+	return &KeyCache{
+		kdf:     kdf,
+		size:    size,
+		secret:  secret,
+		entries: make(map[[sha256.Size]byte]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Key implements KDF.
+func (c *KeyCache) Key(h crypto.Hash, password, salt []byte, iterations int) ([]byte, error) {
+	return c.KeyContext(context.Background(), h, password, salt, iterations)
+}
+
+// KeyContext implements ContextKDF.
+func (c *KeyCache) KeyContext(ctx context.Context, h crypto.Hash, password, salt []byte, iterations int) ([]byte, error) {
+	derive := func() ([]byte, error) {
+		if ck, ok := c.kdf.(ContextKDF); ok {
+			return ck.KeyContext(ctx, h, password, salt, iterations)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return c.kdf.Key(h, password, salt, iterations)
+	}
+	if c.size <= 0 {
+		return derive()
+	}
+
+	key := c.cacheKey(h, password, salt, iterations)
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		c.hits++
+		value := append([]byte(nil), e.Value.(*cacheEntry).value...)
+		c.mu.Unlock()
+		return value, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	value, err := derive()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: append([]byte(nil), value...)})
+		for c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).key)
+		}
+	}
+	return value, nil
+}
+
+// Stats returns the hit and miss counts and the number of entries held.
+func (c *KeyCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Entries: c.order.Len()}
+}
+
+// Purge drops every entry, for example after credentials are rotated.
+func (c *KeyCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.order.Init()
+}
+
+func (c *KeyCache) cacheKey(h crypto.Hash, password, salt []byte, iterations int) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, c.secret)
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(h))
+	binary.BigEndian.PutUint64(buf[8:], uint64(iterations))
+	mac.Write(buf[:])
+	// Length-prefix the variable fields so they cannot run together.
+	mac.Write(binary.BigEndian.AppendUint32(nil, uint32(len(salt))))
+	mac.Write(salt)
+	mac.Write(password)
+
+	var key [sha256.Size]byte
+	mac.Sum(key[:0])
+	return key
+}
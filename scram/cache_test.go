@@ -0,0 +1,120 @@
+package scram
+
+import (
+	"bytes"
+	"crypto"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingKDF returns the password as the key and counts its calls.
+type countingKDF struct {
+	calls atomic.Int64
+}
+
+func (k *countingKDF) Key(h crypto.Hash, password, salt []byte, iterations int) ([]byte, error) {
+	k.calls.Add(1)
+	return append([]byte(nil), password...), nil
+}
+
+func TestKeyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	kdf := &countingKDF{}
+	c := NewKeyCache(kdf, 2)
+	key := func(password string) {
+		t.Helper()
+		got, err := c.Key(crypto.SHA256, []byte(password), benchSalt, 4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != password {
+			t.Fatalf("Key(%q) = %q", password, got)
+		}
+	}
+
+	key("a")
+	key("b")
+	key("a") // a is now more recent than b
+	key("c") // evicts b
+	if n := kdf.calls.Load(); n != 3 {
+		t.Fatalf("%d derivations after filling the cache, want 3", n)
+	}
+	key("a")
+	if n := kdf.calls.Load(); n != 3 {
+		t.Errorf("a was evicted; want b evicted")
+	}
+	key("b")
+	if n := kdf.calls.Load(); n != 4 {
+		t.Errorf("b was still cached; want it evicted")
+	}
+
+	want := CacheStats{Hits: 2, Misses: 4, Entries: 2}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestKeyCacheKeysAllInputs(t *testing.T) {
+	kdf := &countingKDF{}
+	c := NewKeyCache(kdf, 8)
+	c.Key(crypto.SHA256, []byte("pencil"), benchSalt, 4096)
+	c.Key(crypto.SHA512, []byte("pencil"), benchSalt, 4096)
+	c.Key(crypto.SHA256, []byte("pencil"), []byte("another salt"), 4096)
+	c.Key(crypto.SHA256, []byte("pencil"), benchSalt, 8192)
+	if n := kdf.calls.Load(); n != 4 {
+		t.Errorf("%d derivations, want 4: entries differing in one input were shared", n)
+	}
+}
+
+func TestKeyCacheDisabled(t *testing.T) {
+	kdf := &countingKDF{}
+	c := NewKeyCache(kdf, 0)
+	c.Key(crypto.SHA256, []byte("pencil"), benchSalt, 4096)
+	c.Key(crypto.SHA256, []byte("pencil"), benchSalt, 4096)
+	if n := kdf.calls.Load(); n != 2 {
+		t.Errorf("%d derivations with size 0, want 2", n)
+	}
+}
+
+// TestKeyCacheConcurrent is most useful under -race.
+func TestKeyCacheConcurrent(t *testing.T) {
+	kdf := &countingKDF{}
+	c := NewKeyCache(kdf, 4)
+	passwords := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e"), []byte("f")}
+
+	const goroutines, rounds = 8, 200
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				password := passwords[(g+i)%len(passwords)]
+				got, err := c.Key(crypto.SHA256, password, benchSalt, 4096)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !bytes.Equal(got, password) {
+					t.Errorf("Key(%q) = %q", password, got)
+					return
+				}
+				if i%50 == 0 {
+					c.Purge()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	if stats.Hits+stats.Misses != goroutines*rounds {
+		t.Errorf("%d hits and %d misses, want %d lookups", stats.Hits, stats.Misses, goroutines*rounds)
+	}
+	if stats.Misses != uint64(kdf.calls.Load()) {
+		t.Errorf("%d misses but %d derivations", stats.Misses, kdf.calls.Load())
+	}
+	if stats.Entries > 4 {
+		t.Errorf("%d entries, more than the size of 4", stats.Entries)
+	}
+}
@@ -0,0 +1,155 @@
+package scram_test
+
+import (
+	"bytes"
+	"crypto"
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+var (
+	serverBinding = &sasl.ChannelBinding{Type: "tls-server-end-point", Data: bytes.Repeat([]byte{0xa5}, 32)}
+	otherBinding  = &sasl.ChannelBinding{Type: "tls-server-end-point", Data: bytes.Repeat([]byte{0x5a}, 32)}
+)
+
+// aliceVerifier looks up scramtest.Alice for a sasl.ServerConfig.
+func aliceVerifier(username string) (string, error) {
+	if username != scramtest.Alice.Username {
+		return "", scram.ErrUnknownUser
+	}
+	return scramtest.Alice.Verifier(), nil
+}
+
+// TestChannelBindingNegotiation covers each combination of what the client
+// can bind to, what the server offered, what the client saw of the offer,
+// and the gs2 flag that results, through the sasl registry as a driver
+// would use it.
+func TestChannelBindingNegotiation(t *testing.T) {
+	plain := []string{"SCRAM-SHA-256"}
+	both := []string{"SCRAM-SHA-256", "SCRAM-SHA-256-PLUS"}
+
+	for _, tc := range []struct {
+		name string
+		// offered is what the server offered and seen what reached the
+		// client, which differ when the offer was tampered with.
+		offered, seen []string
+		// allowed restricts the client to a plain mechanism, as
+		// pgauth.Authenticator.Allowed does.
+		allowed bool
+		client  *sasl.ChannelBinding
+		server  *sasl.ChannelBinding
+		flag    string
+		wantErr string
+	}{
+		{name: "no binding, plain offered", offered: plain, flag: "n,,"},
+		{name: "no binding, plus offered", offered: both, server: serverBinding, flag: "n,,"},
+		{name: "binding, plain offered", offered: plain, client: serverBinding, flag: "y,,"},
+		{name: "binding, plus offered", offered: both, client: serverBinding, server: serverBinding, flag: "p=tls-server-end-point,,"},
+		{name: "binding, plus offered, restricted to plain", offered: both, allowed: true, client: serverBinding, server: serverBinding, flag: "n,,"},
+		{name: "binding, plus stripped from offer", offered: both, seen: plain, client: serverBinding, server: serverBinding, flag: "y,,",
+			wantErr: "did not use the advertised -PLUS mechanism"},
+		{name: "binding to another channel", offered: both, client: otherBinding, server: serverBinding, flag: "p=tls-server-end-point,,",
+			wantErr: "channel binding mismatch"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			seen := tc.seen
+			if seen == nil {
+				seen = tc.offered
+			}
+			candidates := seen
+			if tc.allowed {
+				candidates = plain
+			}
+			mech, err := sasl.Negotiate(candidates, tc.client != nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, err := mech.NewClient(sasl.ClientConfig{
+				Username:       scramtest.Alice.Username,
+				Password:       scramtest.Alice.Password,
+				ChannelBinding: tc.client,
+				PlusAdvertised: sasl.PlusOffered(seen),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, err := mech.NewServer(sasl.ServerConfig{
+				Lookup:         aliceVerifier,
+				ChannelBinding: tc.server,
+				PlusAdvertised: sasl.PlusOffered(tc.offered),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			transcript, err := scramtest.Exchange(client, server)
+			if !strings.HasPrefix(transcript.ClientFirst, tc.flag) {
+				t.Errorf("client-first %q, want gs2 header %q", transcript.ClientFirst, tc.flag)
+			}
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("exchange failed: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestGS2FlagChecks sends each gs2 flag to servers for plain and -PLUS
+// mechanisms that did and did not advertise -PLUS.
+func TestGS2FlagChecks(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		client         scram.ClientConfig
+		plus           bool
+		plusAdvertised bool
+		wantErr        string
+	}{
+		{name: "n to plain", client: scram.ClientConfig{}},
+		{name: "n to plain, plus advertised", client: scram.ClientConfig{}, plusAdvertised: true},
+		{name: "y to plain", client: scram.ClientConfig{ChannelBindingSupported: true}},
+		{name: "y to plain, plus advertised", client: scram.ClientConfig{ChannelBindingSupported: true}, plusAdvertised: true,
+			wantErr: "did not use the advertised -PLUS mechanism"},
+		{name: "p to plain", client: scram.ClientConfig{ChannelBinding: serverBinding},
+			wantErr: "channel binding requested without -PLUS mechanism"},
+		{name: "p to plus", client: scram.ClientConfig{ChannelBinding: serverBinding}, plus: true, plusAdvertised: true},
+		{name: "n to plus", client: scram.ClientConfig{}, plus: true, plusAdvertised: true,
+			wantErr: "-PLUS mechanism selected without channel binding"},
+		{name: "y to plus", client: scram.ClientConfig{ChannelBindingSupported: true}, plus: true, plusAdvertised: true,
+			wantErr: "-PLUS mechanism selected without channel binding"},
+		{name: "p with another type", client: scram.ClientConfig{ChannelBinding: &sasl.ChannelBinding{Type: "tls-exporter", Data: serverBinding.Data}}, plus: true, plusAdvertised: true,
+			wantErr: `unsupported channel binding type "tls-exporter"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.client
+			cfg.Hash, cfg.Username, cfg.Password = crypto.SHA256, scramtest.Alice.Username, scramtest.Alice.Password
+			client, err := scram.NewClient(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, err := scram.NewServer(scram.ServerConfig{
+				Hash:           crypto.SHA256,
+				Lookup:         scramtest.Store(scramtest.Alice),
+				Plus:           tc.plus,
+				ChannelBinding: serverBinding,
+				PlusAdvertised: tc.plusAdvertised,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = scramtest.Exchange(client, server)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("exchange failed: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package scram
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+)
+
+// ClientConfig configures a client conversation.
+type ClientConfig struct {
+	Hash     crypto.Hash
+	Username string
+	Password string
+	// Authzid, when set, asks to act as this identity once authenticated
+	// as Username, for example a pooler connecting on behalf of a user.
+	Authzid string
+	// KDF derives SaltedPassword; DefaultKDF is used when it is nil.
+	KDF KDF
+	// ChannelBinding, when set, binds the exchange to the secure channel
+	// and must be used with a -PLUS mechanism.
+	ChannelBinding *sasl.ChannelBinding
+	// ChannelBindingSupported is set when the client could bind to the
+	// channel but the server offered no -PLUS mechanism. The gs2 header
+	// then carries "y", so a server that did offer -PLUS can tell the
+	// offer was stripped in transit. It is ignored when ChannelBinding is
+	// set.
+	ChannelBindingSupported bool
+	// Nonce controls the client nonce and the server nonce accepted.
+	Nonce NoncePolicy
+	// Parsing selects how strictly server messages are parsed.
+	Parsing ParseMode
+	// Extensions are optional attributes appended to the client-first
+	// message, keyed by their one-letter names.
+	Extensions map[string]string
+}
+
+type clientState int
+
+const (
+	clientStart clientState = iota
+	clientFirstSent
+	clientFinalSent
+	clientDone
+	clientFailed
+)
+
+// Client is the client side of a SCRAM conversation. It implements
+// sasl.Client.
+type Client struct {
+	cfg   ClientConfig
+	state clientState
+
+	gs2             gs2Header
+	clientNonce     string
+	clientFirstBare string
+	serverSignature []byte
+	extensions      map[string]string
+}
+
+// NewClient starts a client conversation.
+func NewClient(cfg ClientConfig) (*Client, error) {
+	if MechanismName(cfg.Hash) == "" {
+		return nil, fmt.Errorf("scram: unsupported hash %v", cfg.Hash)
+	}
+	if err := checkApproved(cfg.Hash); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Nonce.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkExtensions(cfg.Extensions); err != nil {
+		return nil, err
+	}
+	nonce, err := cfg.Nonce.generate()
+	if err != nil {
+		return nil, err
+	}
+
+	gs2 := gs2Header{cbindFlag: "n", authzid: cfg.Authzid}
+	switch {
+	case cfg.ChannelBinding != nil:
+		gs2.cbindFlag = "p"
+		gs2.cbindType = cfg.ChannelBinding.Type
+	case cfg.ChannelBindingSupported:
+		gs2.cbindFlag = "y"
+	}
+	return &Client{cfg: cfg, gs2: gs2, clientNonce: nonce}, nil
+}
+
+// Start returns the client-first message.
+func (c *Client) Start() ([]byte, error) {
+	if c.state != clientStart {
+		return nil, fmt.Errorf("scram: conversation already started")
+	}
+
+	c.clientFirstBare = appendExtensions("n="+escapeName(c.cfg.Username)+",r="+c.clientNonce, c.cfg.Extensions)
+	c.state = clientFirstSent
+	return []byte(c.gs2.String() + c.clientFirstBare), nil
+}
+
+// Next processes the server-first or server-final message. An empty
+// challenge before the conversation has started is treated as a request
+// for the client-first message.
+func (c *Client) Next(challenge []byte) ([]byte, error) {
+	return c.NextContext(context.Background(), challenge)
+}
+
+// NextContext is like Next but abandons the key derivation for the
+// server-first message with ctx's error when ctx is done.
+func (c *Client) NextContext(ctx context.Context, challenge []byte) ([]byte, error) {
+	switch c.state {
+	case clientStart:
+		return c.Start()
+	case clientFirstSent:
+		resp, err := c.clientFinal(ctx, string(challenge))
+		c.advance(clientFinalSent, err)
+		return resp, err
+	case clientFinalSent:
+		err := c.verifyServerFinal(string(challenge))
+		c.advance(clientDone, err)
+		return nil, err
+	}
+	return nil, fmt.Errorf("scram: conversation is finished")
+}
+
+// Done reports whether the server's signature was verified.
+func (c *Client) Done() bool {
+	return c.state == clientDone
+}
+
+// Extensions returns the optional attributes the server sent that this
+// package does not interpret, keyed by their one-letter names.
+func (c *Client) Extensions() map[string]string {
+	return c.extensions
+}
+
+// VerifyServerFinal processes the server-final message as a distinct
+// step. Unlike Next it fails if the conversation is not waiting for that
+// message, so a caller cannot mistake an earlier step for completion.
+func (c *Client) VerifyServerFinal(serverFinal []byte) error {
+	if c.state != clientFinalSent {
+		return fmt.Errorf("scram: not waiting for the server-final message")
+	}
+	_, err := c.NextContext(context.Background(), serverFinal)
+	return err
+}
+
+// Valid returns nil only once a server-final message carrying the
+// expected ServerSignature has been verified. Call it when the protocol
+// reports success, since a server that skips or fakes the server-final
+// message has not proved it knows the password.
+func (c *Client) Valid() error {
+	switch c.state {
+	case clientDone:
+		return nil
+	case clientFailed:
+		return fmt.Errorf("scram: authentication failed")
+	}
+	return fmt.Errorf("scram: server signature has not been verified")
+}
+
+func (c *Client) advance(next clientState, err error) {
+	if err != nil {
+		c.state = clientFailed
+		return
+	}
+	c.state = next
+}
+
+func (c *Client) clientFinal(ctx context.Context, serverFirst string) ([]byte, error) {
+	attrs, err := parseAttributes(serverFirst)
+	if err != nil {
+		return nil, err
+	}
+	values, err := expectAttributes(attrs, "rsi", c.cfg.Parsing)
+	if err != nil {
+		return nil, err
+	}
+
+	c.extensions = collectExtensions(c.extensions, attrs, "rsi")
+
+	nonce := values[0]
+	if err := c.cfg.Nonce.checkServerNonce(c.clientNonce, nonce); err != nil {
+		return nil, err
+	}
+	salt, err := c.cfg.Parsing.decodeBase64(values[1])
+	if err != nil {
+		return nil, fmt.Errorf("scram: invalid salt encoding: %w", err)
+	}
+	iterations, err := strconv.Atoi(values[2])
+	if err != nil {
+		return nil, fmt.Errorf("scram: invalid iteration count %q", values[2])
+	}
+	if iterations < 1 {
+		return nil, fmt.Errorf("scram: server sent iteration count %d: %w", iterations, ErrIterationsTooLow)
+	}
+
+	cbind := []byte(c.gs2.String())
+	if c.cfg.ChannelBinding != nil {
+		cbind = append(cbind, c.cfg.ChannelBinding.Data...)
+	}
+	withoutProof := "c=" + base64.StdEncoding.EncodeToString(cbind) + ",r=" + nonce
+	authMessage := c.clientFirstBare + "," + serverFirst + "," + withoutProof
+
+	proof, err := computeProof(ctx, c.cfg.KDF, c.cfg.Hash, c.cfg.Password, salt, iterations, authMessage)
+	if err != nil {
+		return nil, err
+	}
+	c.serverSignature = proof.ServerSignature
+
+	return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof.ClientProof)), nil
+}
+
+func (c *Client) verifyServerFinal(serverFinal string) error {
+	attrs, err := parseAttributes(serverFinal)
+	if err != nil {
+		return err
+	}
+	if c.cfg.Parsing == ParseLenient {
+		// Take the first e= or v= attribute, skipping anything else.
+		for i, attr := range attrs {
+			if attr.key == 'e' || attr.key == 'v' {
+				attrs = attrs[i:]
+				break
+			}
+		}
+	}
+	if c.cfg.Parsing == ParseStrict && len(attrs) > 1 {
+		return fmt.Errorf("scram: unexpected extension attribute %q in server-final message", attrs[1].key)
+	}
+	if attrs[0].key == 'm' {
+		return fmt.Errorf("scram: %w: m=%s", ErrMandatoryExtension, attrs[0].value)
+	}
+	c.extensions = collectExtensions(c.extensions, attrs[1:], "")
+
+	switch attrs[0].key {
+	case 'e':
+		if attrs[0].value == "invalid-proof" {
+			return fmt.Errorf("scram: server rejected authentication: %w", ErrProofMismatch)
+		}
+		return fmt.Errorf("scram: server rejected authentication: %s", attrs[0].value)
+	case 'v':
+		sig, err := c.cfg.Parsing.decodeBase64(attrs[0].value)
+		if err != nil {
+			return fmt.Errorf("scram: invalid server signature encoding: %w", err)
+		}
+		if !hmac.Equal(sig, c.serverSignature) {
+			return fmt.Errorf("scram: %w", ErrServerSignatureMismatch)
+		}
+		return nil
+	}
+	return fmt.Errorf("scram: unexpected attribute %q in server-final message", attrs[0].key)
+}
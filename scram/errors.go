@@ -0,0 +1,25 @@
+package scram
+
+import "errors"
+
+// Failure classes for use with errors.Is. Errors returned by the package
+// wrap these with the details of the failure.
+var (
+	ErrEmptyPassword           = errors.New("password cannot be empty")
+	ErrInvalidUTF8             = errors.New("password must be valid UTF-8")
+	ErrPasswordTooLong         = errors.New("password too long")
+	ErrIterationsTooLow        = errors.New("iterations must be at least 1")
+	ErrMalformedVerifier       = errors.New("malformed verifier")
+	ErrProofMismatch           = errors.New("client proof mismatch")
+	ErrServerSignatureMismatch = errors.New("server signature mismatch")
+	ErrNotAuthorized           = errors.New("not authorized")
+	// ErrMandatoryExtension reports a peer that sent the reserved m=
+	// attribute, which announces an extension that must be understood.
+	ErrMandatoryExtension = errors.New("mandatory extension not supported")
+	// ErrUnknownUser is returned by the CredentialLookup implementations
+	// in this package when a user has no stored credentials.
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrBusy is returned when a Limiter has no derivation slot free
+	// within its queue limits.
+	ErrBusy = errors.New("too many concurrent derivations")
+)
@@ -0,0 +1,103 @@
+package scram_test
+
+import (
+	"crypto"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+func TestExtensionsReachPeer(t *testing.T) {
+	client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: scramtest.Alice.Password, Extensions: map[string]string{"x": "client-data", "t": "42"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := scram.NewServer(scram.ServerConfig{Hash: crypto.SHA256, Lookup: scramtest.Store(scramtest.Alice), Extensions: map[string]string{"q": "server-data"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript, err := scramtest.Exchange(client, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(transcript.ClientFirst, ",t=42,x=client-data") {
+		t.Errorf("client-first %q does not end with the extensions in name order", transcript.ClientFirst)
+	}
+	if want := map[string]string{"x": "client-data", "t": "42"}; !maps.Equal(server.Extensions(), want) {
+		t.Errorf("server.Extensions() = %v, want %v", server.Extensions(), want)
+	}
+	if want := map[string]string{"q": "server-data"}; !maps.Equal(client.Extensions(), want) {
+		t.Errorf("client.Extensions() = %v, want %v", client.Extensions(), want)
+	}
+}
+
+func TestExtensionNamesChecked(t *testing.T) {
+	for _, ext := range []map[string]string{
+		{"m": "mandatory"},
+		{"n": "reserved"},
+		{"xy": "two letters"},
+		{"1": "digit"},
+		{"x": "has,comma"},
+	} {
+		if _, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: "pencil", Extensions: ext}); err == nil {
+			t.Errorf("NewClient accepted extensions %v", ext)
+		}
+		if _, err := scram.NewServer(scram.ServerConfig{Hash: crypto.SHA256, Lookup: scramtest.Store(), Extensions: ext}); err == nil {
+			t.Errorf("NewServer accepted extensions %v", ext)
+		}
+	}
+}
+
+// TestMandatoryExtensionClientFirst sends m= to a server, which must
+// answer with the extensions-not-supported server-error and stop.
+func TestMandatoryExtensionClientFirst(t *testing.T) {
+	for _, msg := range []string{
+		"n,,m=ext,n=alice,r=fyko+d2lbbFgONRv9qkxdawL",
+		"n,,n=alice,r=fyko+d2lbbFgONRv9qkxdawL,m=ext",
+	} {
+		server, err := scramtest.NewServer(crypto.SHA256, scramtest.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		challenge, done, err := server.Next([]byte(msg))
+		if !errors.Is(err, scram.ErrMandatoryExtension) {
+			t.Errorf("%q: got %v, want ErrMandatoryExtension", msg, err)
+		}
+		if !done || string(challenge) != "e=extensions-not-supported" {
+			t.Errorf("%q: server answered %q (done %v), want e=extensions-not-supported", msg, challenge, done)
+		}
+		if _, _, err := server.Next([]byte("c=biws,r=x,p=AAAA")); err == nil {
+			t.Errorf("%q: server continued after failing", msg)
+		}
+	}
+}
+
+// TestMandatoryExtensionServerFirst sends m= to a client, which must fail
+// rather than compute a proof.
+func TestMandatoryExtensionServerFirst(t *testing.T) {
+	for _, layout := range []string{"m=ext,r=%s,s=QSXCR+Q6sek8bf92,i=4096", "r=%s,s=QSXCR+Q6sek8bf92,i=4096,m=ext"} {
+		client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: "pencil"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := client.Start()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, nonce, _ := strings.Cut(string(first), ",r=")
+		serverFirst := strings.Replace(layout, "%s", nonce+"3rfcNHYJY1ZVvWVs7j", 1)
+
+		final, err := client.Next([]byte(serverFirst))
+		if !errors.Is(err, scram.ErrMandatoryExtension) {
+			t.Errorf("%q: got %v, want ErrMandatoryExtension", serverFirst, err)
+		}
+		if final != nil || client.Done() {
+			t.Errorf("%q: client went on to send %q", serverFirst, final)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package scram
+
+import (
+	"crypto"
+	"crypto/fips140"
+	"fmt"
+)
+
+// FIPSMode reports whether the Go Cryptographic Module is running in FIPS
+// 140-3 mode, in which case the package refuses SCRAM-SHA-1.
+func FIPSMode() bool {
+	return fips140.Enabled()
+}
+
+func checkApproved(h crypto.Hash) error {
+	if FIPSMode() && h == crypto.SHA1 {
+		return fmt.Errorf("scram: %s is not permitted in FIPS 140-3 mode", MechanismName(h))
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package scram
+
+import (
+	"context"
+	"crypto"
+	"crypto/fips140"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+)
+
+// cancelCheckInterval is how many PBKDF2 iterations run between checks
+// of the context and progress reports.
+const cancelCheckInterval = 4096
+
+// KDF computes SaltedPassword, the Hi() function of RFC 5802, which is
+// PBKDF2 with HMAC-h and an output the size of h. Implementations may
+// substitute a hardware-accelerated or separately validated PBKDF2.
+// The password and salt slices may be reused once Key returns, so
+// implementations must copy them to keep them.
+type KDF interface {
+	Key(h crypto.Hash, password, salt []byte, iterations int) ([]byte, error)
+}
+
+// PBKDF2 is the default KDF, backed by crypto/pbkdf2 so that derivations
+// run inside the Go Cryptographic Module when FIPS 140-3 mode is enabled.
+type PBKDF2 struct{}
+
+// Key implements KDF.
+func (PBKDF2) Key(h crypto.Hash, password, salt []byte, iterations int) ([]byte, error) {
+	return pbkdf2.Key(h.New, string(password), salt, iterations, h.Size())
+}
+
+// ContextKDF is implemented by KDFs that can abandon a derivation part
+// way through when ctx is done, and report progress to a ProgressFunc set
+// with WithProgress.
+type ContextKDF interface {
+	KDF
+	KeyContext(ctx context.Context, h crypto.Hash, password, salt []byte, iterations int) ([]byte, error)
+}
+
+// KeyContext implements ContextKDF. In FIPS 140-3 mode the derivation must
+// stay inside the Go Cryptographic Module, so ctx is only checked before
+// it starts.
+func (k PBKDF2) KeyContext(ctx context.Context, h crypto.Hash, password, salt []byte, iterations int) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	progress := progressFrom(ctx)
+	if fips140.Enabled() {
+		progress(0, iterations)
+		key, err := k.Key(h, password, salt, iterations)
+		if err == nil {
+			progress(iterations, iterations)
+		}
+		return key, err
+	}
+
+	// SCRAM needs a single PBKDF2 block: U1 = HMAC(salt || INT(1)),
+	// Ui = HMAC(Ui-1), result = U1 ^ ... ^ Un.
+	mac := hmac.New(h.New, password)
+	mac.Write(salt)
+	mac.Write(firstBlock)
+	// u is rewritten in place each iteration, so the loop allocates nothing.
+	u := mac.Sum(nil)
+	out := append([]byte(nil), u...)
+	for i := 2; i <= iterations; i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			progress(i, iterations)
+		}
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range out {
+			out[j] ^= u[j]
+		}
+	}
+	progress(iterations, iterations)
+	return out, nil
+}
+
+// firstBlock is INT(1), the big-endian index of the only PBKDF2 block.
+var firstBlock = []byte{0, 0, 0, 1}
+
+// DefaultKDF is used wherever a KDF is not configured explicitly. Replace it
+// during program initialization to route every derivation in the package
+// through another implementation.
+var DefaultKDF KDF = PBKDF2{}
+
+func saltedPassword(ctx context.Context, kdf KDF, h crypto.Hash, password string, salt []byte, iterations int) ([]byte, error) {
+	if kdf == nil {
+		kdf = DefaultKDF
+	}
+	release, err := DefaultLimiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if ck, ok := kdf.(ContextKDF); ok {
+		return ck.KeyContext(ctx, h, []byte(password), salt, iterations)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return kdf.Key(h, []byte(password), salt, iterations)
+}
+
+var (
+	clientKeyLabel = []byte("Client Key")
+	serverKeyLabel = []byte("Server Key")
+)
+
+// KeyDeriver computes ClientKey and ServerKey for a password. The software
+// implementation holds SaltedPassword in process memory while it runs the
+// two HMACs; an implementation backed by an HSM can derive SaltedPassword
+// as a non-extractable token key and perform the HMACs there instead.
+type KeyDeriver interface {
+	DeriveKeys(h crypto.Hash, password string, salt []byte, iterations int) (clientKey, serverKey []byte, err error)
+}
+
+// ContextKeyDeriver is implemented by KeyDerivers that can abandon a
+// derivation when ctx is done.
+type ContextKeyDeriver interface {
+	KeyDeriver
+	DeriveKeysContext(ctx context.Context, h crypto.Hash, password string, salt []byte, iterations int) (clientKey, serverKey []byte, err error)
+}
+
+// SoftwareKeyDeriver derives keys in process memory. It is the fallback
+// used when no other KeyDeriver is configured.
+type SoftwareKeyDeriver struct {
+	// KDF derives SaltedPassword; DefaultKDF is used when it is nil.
+	KDF KDF
+}
+
+// DeriveKeys implements KeyDeriver.
+func (d SoftwareKeyDeriver) DeriveKeys(h crypto.Hash, password string, salt []byte, iterations int) ([]byte, []byte, error) {
+	return d.DeriveKeysContext(context.Background(), h, password, salt, iterations)
+}
+
+// DeriveKeysContext implements ContextKeyDeriver.
+func (d SoftwareKeyDeriver) DeriveKeysContext(ctx context.Context, h crypto.Hash, password string, salt []byte, iterations int) ([]byte, []byte, error) {
+	salted, err := saltedPassword(ctx, d.KDF, h, password, salt, iterations)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientKey, serverKey := keysFromSaltedPassword(h, salted)
+	return clientKey, serverKey, nil
+}
+
+// keysFromSaltedPassword computes ClientKey and ServerKey, using one HMAC
+// state keyed with SaltedPassword for both.
+func keysFromSaltedPassword(h crypto.Hash, salted []byte) (clientKey, serverKey []byte) {
+	mac := hmac.New(h.New, salted)
+	mac.Write(clientKeyLabel)
+	clientKey = mac.Sum(nil)
+	mac.Reset()
+	mac.Write(serverKeyLabel)
+	return clientKey, mac.Sum(nil)
+}
@@ -0,0 +1,88 @@
+package scram
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter bounds how many PBKDF2 derivations run at once, so a burst of
+// requests with high iteration counts cannot starve other workloads on the
+// host. Derivations beyond the limit queue for a slot; those that would
+// exceed MaxQueue, or that wait longer than QueueTimeout, fail with
+// ErrBusy, which services can report like an HTTP 503. It is safe for
+// concurrent use.
+//
+// A server can hold its own and call Acquire around each derivation, as
+// testscram.Server does. To bound every derivation in the process
+// instead, install one at startup:
+//
+//	scram.DefaultLimiter = &scram.Limiter{MaxQueue: 16, QueueTimeout: 2 * time.Second}
+type Limiter struct {
+	// MaxConcurrent limits how many derivations run at once; zero means
+	// runtime.GOMAXPROCS(0).
+	MaxConcurrent int
+	// MaxQueue limits how many derivations may wait for a slot; zero means
+	// no limit. Derivations beyond it get ErrBusy at once.
+	MaxQueue int
+	// QueueTimeout is how long a derivation waits for a slot before
+	// getting ErrBusy; zero means it waits until its context is done.
+	QueueTimeout time.Duration
+
+	slotsOnce sync.Once
+	slots     chan struct{}
+	waiting   atomic.Int64
+}
+
+// DefaultLimiter, when set, bounds every derivation of SaltedPassword in
+// the package, whichever KDF performs it. It is nil by default, which
+// leaves derivations unbounded. KeyDerivers that do not derive in process
+// memory, such as HSM-backed ones, are not limited by it.
+var DefaultLimiter *Limiter
+
+// Acquire takes a derivation slot, waiting within MaxQueue and
+// QueueTimeout, and returns the function that gives it back. A nil
+// Limiter imposes no limit.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	l.slotsOnce.Do(func() {
+		n := l.MaxConcurrent
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		l.slots = make(chan struct{}, n)
+	})
+	release := func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if waiting := l.waiting.Add(1); l.MaxQueue > 0 && waiting > int64(l.MaxQueue) {
+		l.waiting.Add(-1)
+		return nil, fmt.Errorf("scram: %w", ErrBusy)
+	}
+	defer l.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.QueueTimeout > 0 {
+		t := time.NewTimer(l.QueueTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timeout:
+		return nil, fmt.Errorf("scram: %w", ErrBusy)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
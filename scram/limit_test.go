@@ -0,0 +1,78 @@
+package scram
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterMaxQueue(t *testing.T) {
+	l := &Limiter{MaxConcurrent: 1, MaxQueue: 1}
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One derivation may wait for the slot; the next is turned away.
+	queued := make(chan error)
+	go func() {
+		release, err := l.Acquire(context.Background())
+		if err == nil {
+			release()
+		}
+		queued <- err
+	}()
+	for l.waiting.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := l.Acquire(context.Background()); !errors.Is(err, ErrBusy) {
+		t.Errorf("Acquire beyond MaxQueue: got %v, want ErrBusy", err)
+	}
+
+	release()
+	if err := <-queued; err != nil {
+		t.Errorf("queued Acquire: %v", err)
+	}
+}
+
+func TestLimiterQueueTimeout(t *testing.T) {
+	l := &Limiter{MaxConcurrent: 1, QueueTimeout: 20 * time.Millisecond}
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	start := time.Now()
+	if _, err := l.Acquire(context.Background()); !errors.Is(err, ErrBusy) {
+		t.Errorf("Acquire after QueueTimeout: got %v, want ErrBusy", err)
+	}
+	if waited := time.Since(start); waited < l.QueueTimeout {
+		t.Errorf("gave up after %v, before QueueTimeout", waited)
+	}
+}
+
+func TestLimiterContextDone(t *testing.T) {
+	l := &Limiter{MaxConcurrent: 1}
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -0,0 +1,251 @@
+package scram
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseMode selects how strictly messages and verifiers from a peer are
+// parsed, since servers and drivers vary in how closely they follow
+// RFC 5802.
+type ParseMode int
+
+const (
+	// ParseStandard follows RFC 5802: attributes must appear in the
+	// defined order, trailing extension attributes are collected for the
+	// application and the reserved m= attribute fails authentication.
+	ParseStandard ParseMode = iota
+	// ParseStrict also rejects extension attributes and non-canonical
+	// encodings, for conformance testing.
+	ParseStrict
+	// ParseLenient skips m= and extension attributes wherever they appear
+	// and accepts the required attributes in any order.
+	ParseLenient
+)
+
+// decodeBase64 decodes s, requiring canonical padding and trailing bits
+// in strict mode and allowing missing padding in lenient mode.
+func (m ParseMode) decodeBase64(s string) ([]byte, error) {
+	switch m {
+	case ParseStrict:
+		return base64.StdEncoding.Strict().DecodeString(s)
+	case ParseLenient:
+		if !strings.HasSuffix(s, "=") && len(s)%4 != 0 {
+			return base64.RawStdEncoding.DecodeString(s)
+		}
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+type attribute struct {
+	key   byte
+	value string
+}
+
+// parseAttributes splits a SCRAM message into its key=value attributes.
+func parseAttributes(msg string) ([]attribute, error) {
+	if msg == "" {
+		return nil, fmt.Errorf("scram: empty message")
+	}
+
+	parts := strings.Split(msg, ",")
+	attrs := make([]attribute, len(parts))
+	for i, part := range parts {
+		if len(part) < 2 || part[1] != '=' || !isAlpha(part[0]) {
+			return nil, fmt.Errorf("scram: malformed attribute %q", part)
+		}
+		attrs[i] = attribute{key: part[0], value: part[2:]}
+	}
+	return attrs, nil
+}
+
+// expectAttributes returns the values of the given keys from attrs,
+// applying mode. In standard and strict modes they must come first and in
+// order; trailing attributes are extensions, which strict mode rejects.
+func expectAttributes(attrs []attribute, keys string, mode ParseMode) ([]string, error) {
+	values := make([]string, len(keys))
+	if mode == ParseLenient {
+		for i := range keys {
+			found := false
+			for _, attr := range attrs {
+				if attr.key == keys[i] {
+					values[i], found = attr.value, true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("scram: missing attribute %q", keys[i])
+			}
+		}
+		return values, nil
+	}
+
+	for _, attr := range attrs {
+		if attr.key == 'm' {
+			return nil, fmt.Errorf("scram: %w: m=%s", ErrMandatoryExtension, attr.value)
+		}
+	}
+	if len(attrs) < len(keys) {
+		return nil, fmt.Errorf("scram: expected %d attributes, got %d", len(keys), len(attrs))
+	}
+	for i := range keys {
+		if attrs[i].key != keys[i] {
+			return nil, fmt.Errorf("scram: expected attribute %q, got %q", keys[i], attrs[i].key)
+		}
+		values[i] = attrs[i].value
+	}
+	if mode == ParseStrict && len(attrs) > len(keys) {
+		return nil, fmt.Errorf("scram: unexpected extension attribute %q", attrs[len(keys)].key)
+	}
+	return values, nil
+}
+
+// reservedKeys are the attribute names RFC 5802 defines, which cannot be
+// used for extensions.
+const reservedKeys = "aceimnprsv"
+
+// collectExtensions adds the attributes in attrs other than keys and m= to
+// ext, allocating it on first use.
+func collectExtensions(ext map[string]string, attrs []attribute, keys string) map[string]string {
+	for _, attr := range attrs {
+		if attr.key == 'm' || strings.IndexByte(keys, attr.key) >= 0 {
+			continue
+		}
+		if ext == nil {
+			ext = make(map[string]string)
+		}
+		ext[string(attr.key)] = attr.value
+	}
+	return ext
+}
+
+// checkExtensions validates extension attributes to be sent.
+func checkExtensions(ext map[string]string) error {
+	for key, value := range ext {
+		if len(key) != 1 || !isAlpha(key[0]) {
+			return fmt.Errorf("scram: extension name %q is not a single letter", key)
+		}
+		if strings.Contains(reservedKeys, key) {
+			return fmt.Errorf("scram: extension name %q is reserved", key)
+		}
+		if strings.Contains(value, ",") {
+			return fmt.Errorf("scram: extension %s value contains ','", key)
+		}
+	}
+	return nil
+}
+
+// appendExtensions appends ext to msg in name order.
+func appendExtensions(msg string, ext map[string]string) string {
+	keys := make([]string, 0, len(ext))
+	for key := range ext {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		msg += "," + key + "=" + ext[key]
+	}
+	return msg
+}
+
+// errorValue maps a parse failure to the server-error-value reported to
+// the client.
+func errorValue(err error) string {
+	if errors.Is(err, ErrMandatoryExtension) {
+		return "extensions-not-supported"
+	}
+	return "other-error"
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// gs2Header is the parsed GS2 header that prefixes the client-first message.
+type gs2Header struct {
+	cbindFlag string // "n", "y" or "p"
+	cbindType string
+	authzid   string
+}
+
+func (h gs2Header) String() string {
+	flag := h.cbindFlag
+	if flag == "p" {
+		flag = "p=" + h.cbindType
+	}
+	authzid := ""
+	if h.authzid != "" {
+		authzid = "a=" + escapeName(h.authzid)
+	}
+	return flag + "," + authzid + ","
+}
+
+// splitClientFirst separates the GS2 header from the client-first-bare
+// message.
+func splitClientFirst(msg string) (gs2Header, string, error) {
+	flag, rest, ok := strings.Cut(msg, ",")
+	if !ok {
+		return gs2Header{}, "", fmt.Errorf("scram: client-first message has no gs2 header")
+	}
+	authz, bare, ok := strings.Cut(rest, ",")
+	if !ok {
+		return gs2Header{}, "", fmt.Errorf("scram: client-first message has no gs2 header")
+	}
+
+	var hdr gs2Header
+	switch {
+	case flag == "n" || flag == "y":
+		hdr.cbindFlag = flag
+	case strings.HasPrefix(flag, "p="):
+		hdr.cbindFlag = "p"
+		hdr.cbindType = flag[2:]
+		if hdr.cbindType == "" {
+			return gs2Header{}, "", fmt.Errorf("scram: empty channel binding type")
+		}
+	default:
+		return gs2Header{}, "", fmt.Errorf("scram: invalid gs2 channel binding flag %q", flag)
+	}
+
+	if authz != "" {
+		name, ok := strings.CutPrefix(authz, "a=")
+		if !ok {
+			return gs2Header{}, "", fmt.Errorf("scram: malformed authzid %q", authz)
+		}
+		var err error
+		if hdr.authzid, err = unescapeName(name); err != nil {
+			return gs2Header{}, "", err
+		}
+	}
+	return hdr, bare, nil
+}
+
+// escapeName encodes a saslname per RFC 5802 section 5.1.
+func escapeName(name string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(name)
+}
+
+func unescapeName(name string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case ',':
+			return "", fmt.Errorf("scram: unescaped ',' in name")
+		case '=':
+			switch {
+			case strings.HasPrefix(name[i:], "=2C"):
+				b.WriteByte(',')
+			case strings.HasPrefix(name[i:], "=3D"):
+				b.WriteByte('=')
+			default:
+				return "", fmt.Errorf("scram: invalid escape in name %q", name)
+			}
+			i += 2
+		default:
+			b.WriteByte(name[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,121 @@
+package scram
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// base64Alphabet is the default character set for generated nonces.
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// printableCharset is the size of the printable alphabet RFC 5802 allows
+// in nonces (0x21-0x7E except ','), used to bound a peer nonce's entropy.
+const printableCharset = 93
+
+// NoncePolicy controls the nonces a conversation generates and the ones
+// it accepts from the peer. The zero value generates 24 base64
+// characters and accepts any well-formed peer nonce.
+type NoncePolicy struct {
+	// Length is the number of characters to generate; 0 means 24.
+	Length int
+	// Charset holds the characters to generate from; empty means the
+	// base64 alphabet. It may only contain printable ASCII other than ','.
+	Charset string
+	// MinEntropy is the smallest acceptable entropy, in bits, for both
+	// the generated nonce and the peer's part of the nonce. A peer's part
+	// is credited at most log2(93) bits per character, so this amounts to
+	// a minimum length.
+	MinEntropy float64
+}
+
+func (p NoncePolicy) length() int {
+	if p.Length == 0 {
+		return 4 * nonceLength / 3
+	}
+	return p.Length
+}
+
+func (p NoncePolicy) charset() string {
+	if p.Charset == "" {
+		return base64Alphabet
+	}
+	return p.Charset
+}
+
+// validate checks that the policy can produce nonces it would accept.
+func (p NoncePolicy) validate() error {
+	if p.Length < 0 {
+		return fmt.Errorf("scram: nonce length %d is negative", p.Length)
+	}
+	seen := make(map[rune]bool)
+	for _, r := range p.charset() {
+		if !isNonceChar(r) {
+			return fmt.Errorf("scram: nonce charset contains %q, which is not printable ASCII other than ','", r)
+		}
+		if seen[r] {
+			return fmt.Errorf("scram: nonce charset repeats %q", r)
+		}
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		return fmt.Errorf("scram: nonce charset needs at least two characters")
+	}
+	if bits := float64(p.length()) * math.Log2(float64(len(seen))); bits < p.MinEntropy {
+		return fmt.Errorf("scram: nonce of %d characters from a %d-character set has %.0f bits of entropy, below the minimum of %.0f",
+			p.length(), len(seen), bits, p.MinEntropy)
+	}
+	return nil
+}
+
+// generate returns a new random nonce.
+func (p NoncePolicy) generate() (string, error) {
+	if p.Length == 0 && p.Charset == "" {
+		return newNonce()
+	}
+
+	charset := p.charset()
+	max := big.NewInt(int64(len(charset)))
+	b := make([]byte, p.length())
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("scram: failed to generate nonce: %w", err)
+		}
+		b[i] = charset[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// check validates the part of a nonce contributed by the peer, named
+// "client" or "server" in errors.
+func (p NoncePolicy) check(peer, part string) error {
+	if part == "" {
+		return fmt.Errorf("scram: empty %s nonce", peer)
+	}
+	for i, r := range part {
+		if !isNonceChar(r) {
+			return fmt.Errorf("scram: %s nonce contains invalid character %q at offset %d", peer, r, i)
+		}
+	}
+	if bits := float64(len(part)) * math.Log2(printableCharset); bits < p.MinEntropy {
+		return fmt.Errorf("scram: %s nonce of %d characters has at most %.0f bits of entropy, below the minimum of %.0f",
+			peer, len(part), bits, p.MinEntropy)
+	}
+	return nil
+}
+
+func isNonceChar(r rune) bool {
+	return r >= 0x21 && r <= 0x7e && r != ','
+}
+
+// checkServerNonce validates that nonce extends clientNonce with an
+// acceptable server part.
+func (p NoncePolicy) checkServerNonce(clientNonce, nonce string) error {
+	if !strings.HasPrefix(nonce, clientNonce) {
+		return fmt.Errorf("scram: server nonce does not start with the client nonce")
+	}
+	return p.check("server", nonce[len(clientNonce):])
+}
@@ -0,0 +1,107 @@
+package scram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNoncePolicyValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		policy  NoncePolicy
+		wantErr string
+	}{
+		{name: "zero value", policy: NoncePolicy{}},
+		{name: "hex", policy: NoncePolicy{Length: 32, Charset: "0123456789abcdef", MinEntropy: 128}},
+		{name: "negative length", policy: NoncePolicy{Length: -1}, wantErr: "negative"},
+		{name: "comma", policy: NoncePolicy{Charset: "ab,"}, wantErr: "not printable ASCII"},
+		{name: "space", policy: NoncePolicy{Charset: "ab "}, wantErr: "not printable ASCII"},
+		{name: "non-ASCII", policy: NoncePolicy{Charset: "abé"}, wantErr: "not printable ASCII"},
+		{name: "repeat", policy: NoncePolicy{Charset: "abca"}, wantErr: "repeats"},
+		{name: "one character", policy: NoncePolicy{Charset: "a"}, wantErr: "at least two"},
+		{name: "too little entropy", policy: NoncePolicy{Length: 16, Charset: "0123456789abcdef", MinEntropy: 128}, wantErr: "64 bits"},
+		{name: "default below minimum", policy: NoncePolicy{MinEntropy: 256}, wantErr: "below the minimum of 256"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.validate()
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("validate() = %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("validate() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoncePolicyGenerate(t *testing.T) {
+	p := NoncePolicy{Length: 40, Charset: "xyz"}
+	nonce, err := p.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nonce) != 40 || strings.Trim(nonce, "xyz") != "" {
+		t.Errorf("generate() = %q, want 40 characters from xyz", nonce)
+	}
+	if err := p.check("server", nonce); err != nil {
+		t.Errorf("generated nonce fails its own check: %v", err)
+	}
+}
+
+func TestNoncePolicyCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		policy  NoncePolicy
+		part    string
+		wantErr string
+	}{
+		{name: "printable", part: "!~AZaz09+/"},
+		{name: "empty", part: "", wantErr: "empty client nonce"},
+		{name: "comma", part: "abc,def", wantErr: `invalid character ',' at offset 3`},
+		{name: "space", part: "abc def", wantErr: "invalid character ' '"},
+		{name: "control", part: "abc\x7f", wantErr: "invalid character"},
+		{name: "non-ASCII", part: "abcé", wantErr: "invalid character"},
+		// 10 characters carry at most 10*log2(93), about 65 bits.
+		{name: "short enough", policy: NoncePolicy{MinEntropy: 64}, part: "0123456789"},
+		{name: "too short", policy: NoncePolicy{MinEntropy: 66}, part: "0123456789", wantErr: "at most 65 bits"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.check("client", tc.part)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("check() = %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("check() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckServerNonce(t *testing.T) {
+	const client = "fyko+d2lbbFgONRv9qkxdawL"
+	for _, tc := range []struct {
+		name    string
+		policy  NoncePolicy
+		nonce   string
+		wantErr string
+	}{
+		{name: "extends client nonce", nonce: client + "3rfcNHYJY1ZVvWVs7j"},
+		{name: "client nonce only", nonce: client, wantErr: "empty server nonce"},
+		{name: "different prefix", nonce: "Xyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j", wantErr: "does not start with the client nonce"},
+		{name: "truncated client nonce", nonce: client[:10], wantErr: "does not start with the client nonce"},
+		{name: "invalid server part", nonce: client + "abc,def", wantErr: "server nonce contains invalid character"},
+		{name: "short server part", policy: NoncePolicy{MinEntropy: 128}, nonce: client + "abc", wantErr: "server nonce of 3 characters"},
+		// Entropy is judged on the server's part alone, not the whole nonce.
+		{name: "long client part does not count", policy: NoncePolicy{MinEntropy: 64}, nonce: client + "abcdefgh", wantErr: "server nonce of 8 characters"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.checkServerNonce(client, tc.nonce)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("checkServerNonce() = %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("checkServerNonce() = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package scram_test
+
+import (
+	"crypto"
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+// TestNoncePolicyExchange checks nonce policies end to end: each side's
+// generated nonce must satisfy the other side's minimum.
+func TestNoncePolicyExchange(t *testing.T) {
+	short := scram.NoncePolicy{Length: 8, Charset: "0123456789abcdef"} // 32 bits
+	strict := scram.NoncePolicy{MinEntropy: 128}
+	for _, tc := range []struct {
+		name           string
+		client, server scram.NoncePolicy
+		wantErr        string
+	}{
+		{name: "defaults"},
+		{name: "both strict", client: strict, server: strict},
+		{name: "hex nonces", client: scram.NoncePolicy{Length: 32, Charset: "0123456789abcdef"}, server: scram.NoncePolicy{Length: 32, Charset: "0123456789abcdef", MinEntropy: 128}},
+		{name: "short client nonce", client: short, server: strict, wantErr: "server: scram: client nonce of 8 characters"},
+		{name: "short server nonce", client: strict, server: short, wantErr: "client: scram: server nonce of 8 characters"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: scramtest.Alice.Password, Nonce: tc.client})
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, err := scram.NewServer(scram.ServerConfig{Hash: crypto.SHA256, Lookup: scramtest.Store(scramtest.Alice), Nonce: tc.server})
+			if err != nil {
+				t.Fatal(err)
+			}
+			transcript, err := scramtest.Exchange(client, server)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("exchange failed: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got %v, want %q", err, tc.wantErr)
+			}
+			if err == nil && tc.client.Length > 0 {
+				_, nonce, _ := strings.Cut(transcript.ClientFirst, ",r=")
+				if len(nonce) != tc.client.Length {
+					t.Errorf("client nonce %q, want %d characters", nonce, tc.client.Length)
+				}
+			}
+		})
+	}
+}
+
+func TestNoncePolicyRejectedAtConstruction(t *testing.T) {
+	bad := scram.NoncePolicy{Length: 4, Charset: "ab", MinEntropy: 64}
+	if _, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: "pencil", Nonce: bad}); err == nil {
+		t.Error("NewClient accepted a policy below its own minimum entropy")
+	}
+	if _, err := scram.NewServer(scram.ServerConfig{Hash: crypto.SHA256, Lookup: scramtest.Store(), Nonce: bad}); err == nil {
+		t.Error("NewServer accepted a policy below its own minimum entropy")
+	}
+}
+
+// TestServerNonceMustExtendClientNonce answers as testscram.BadNonce does,
+// with a server nonce that does not start with the client's.
+func TestServerNonceMustExtendClientNonce(t *testing.T) {
+	client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: "pencil"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Next([]byte("r=bm90LXlvdXItbm9uY2U=,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"))
+	if err == nil || !strings.Contains(err.Error(), "does not start with the client nonce") {
+		t.Errorf("got %v, want a nonce mismatch", err)
+	}
+}
@@ -0,0 +1,158 @@
+package scram_test
+
+import (
+	"crypto"
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+var parseModes = []struct {
+	name string
+	mode scram.ParseMode
+}{
+	{"standard", scram.ParseStandard},
+	{"strict", scram.ParseStrict},
+	{"lenient", scram.ParseLenient},
+}
+
+// accepts lists, per mode, whether a message variant must be accepted.
+type accepts struct{ standard, strict, lenient bool }
+
+func (a accepts) in(mode scram.ParseMode) bool {
+	switch mode {
+	case scram.ParseStrict:
+		return a.strict
+	case scram.ParseLenient:
+		return a.lenient
+	}
+	return a.standard
+}
+
+func TestParseModesClientFirst(t *testing.T) {
+	const nonce = "fyko+d2lbbFgONRv9qkxdawL"
+	for _, tc := range []struct {
+		name string
+		bare string
+		want accepts
+	}{
+		{"canonical", "n=alice,r=" + nonce, accepts{true, true, true}},
+		{"reordered", "r=" + nonce + ",n=alice", accepts{false, false, true}},
+		{"trailing extension", "n=alice,r=" + nonce + ",x=1", accepts{true, false, true}},
+		{"leading extension", "x=1,n=alice,r=" + nonce, accepts{false, false, true}},
+		{"trailing m=", "n=alice,r=" + nonce + ",m=ext", accepts{false, false, true}},
+		{"leading m=", "m=ext,n=alice,r=" + nonce, accepts{false, false, true}},
+		{"missing nonce", "n=alice", accepts{false, false, false}},
+	} {
+		for _, m := range parseModes {
+			t.Run(tc.name+"/"+m.name, func(t *testing.T) {
+				server, err := scram.NewServer(scram.ServerConfig{Hash: crypto.SHA256, Lookup: scramtest.Store(scramtest.Alice), Parsing: m.mode})
+				if err != nil {
+					t.Fatal(err)
+				}
+				challenge, _, err := server.Next([]byte("n,," + tc.bare))
+				if want := tc.want.in(m.mode); (err == nil) != want {
+					t.Fatalf("accepted: %v, want %v (%v)", err == nil, want, err)
+				}
+				if err != nil {
+					return
+				}
+				if !strings.HasPrefix(string(challenge), "r="+nonce) {
+					t.Errorf("server-first %q does not extend the client nonce", challenge)
+				}
+				if server.Username() != "alice" {
+					t.Errorf("username %q, want alice", server.Username())
+				}
+				if strings.Contains(tc.bare, "x=1") && server.Extensions()["x"] != "1" {
+					t.Errorf("Extensions() = %v, want x=1", server.Extensions())
+				}
+			})
+		}
+	}
+}
+
+func TestParseModesServerFirst(t *testing.T) {
+	const salt = "W22ZaJ0SNY7soEsUEjb6gQ=="
+	for _, tc := range []struct {
+		name   string
+		layout string // %r is replaced by the combined nonce
+		want   accepts
+	}{
+		{"canonical", "r=%r,s=" + salt + ",i=4096", accepts{true, true, true}},
+		{"reordered", "s=" + salt + ",r=%r,i=4096", accepts{false, false, true}},
+		{"trailing extension", "r=%r,s=" + salt + ",i=4096,x=1", accepts{true, false, true}},
+		{"leading extension", "x=1,r=%r,s=" + salt + ",i=4096", accepts{false, false, true}},
+		{"trailing m=", "r=%r,s=" + salt + ",i=4096,m=ext", accepts{false, false, true}},
+		{"unpadded salt", "r=%r,s=" + strings.TrimRight(salt, "=") + ",i=4096", accepts{false, false, true}},
+		{"missing iterations", "r=%r,s=" + salt, accepts{false, false, false}},
+	} {
+		for _, m := range parseModes {
+			t.Run(tc.name+"/"+m.name, func(t *testing.T) {
+				client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "user", Password: "pencil", Parsing: m.mode})
+				if err != nil {
+					t.Fatal(err)
+				}
+				first, err := client.Start()
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, nonce, _ := strings.Cut(string(first), ",r=")
+				serverFirst := strings.Replace(tc.layout, "%r", nonce+"%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0", 1)
+
+				final, err := client.Next([]byte(serverFirst))
+				if want := tc.want.in(m.mode); (err == nil) != want {
+					t.Fatalf("accepted: %v, want %v (%v)", err == nil, want, err)
+				}
+				if err == nil && !strings.HasPrefix(string(final), "c=biws,r="+nonce) {
+					t.Errorf("client-final %q", final)
+				}
+			})
+		}
+	}
+}
+
+func TestParseModesServerFinal(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		rewrite func(serverFinal string) string
+		want    accepts
+	}{
+		{"canonical", func(v string) string { return v }, accepts{true, true, true}},
+		{"trailing extension", func(v string) string { return v + ",x=1" }, accepts{true, false, true}},
+		{"leading extension", func(v string) string { return "x=1," + v }, accepts{false, false, true}},
+		{"leading m=", func(v string) string { return "m=ext," + v }, accepts{false, false, true}},
+	} {
+		for _, m := range parseModes {
+			t.Run(tc.name+"/"+m.name, func(t *testing.T) {
+				client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: "alice", Password: scramtest.Alice.Password, Parsing: m.mode})
+				if err != nil {
+					t.Fatal(err)
+				}
+				server, err := scramtest.NewServer(crypto.SHA256, scramtest.Alice)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = scramtest.Exchange(client, rewriteFinal{server, tc.rewrite})
+				if want := tc.want.in(m.mode); (err == nil) != want {
+					t.Errorf("accepted: %v, want %v (%v)", err == nil, want, err)
+				}
+			})
+		}
+	}
+}
+
+// rewriteFinal alters the server-final message of a server conversation.
+type rewriteFinal struct {
+	*scram.Server
+	rewrite func(string) string
+}
+
+func (r rewriteFinal) Next(response []byte) ([]byte, bool, error) {
+	challenge, done, err := r.Server.Next(response)
+	if done && err == nil {
+		challenge = []byte(r.rewrite(string(challenge)))
+	}
+	return challenge, done, err
+}
@@ -0,0 +1,68 @@
+package scram
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// DefaultMaxPasswordLength is the longest password, in bytes, that
+// ReadPassword accepts unless WithMaxLength gives another limit.
+const DefaultMaxPasswordLength = 1024
+
+// ReadOption configures ReadPassword.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	maxLength int
+}
+
+// WithMaxLength sets the longest password, in bytes, that ReadPassword
+// accepts.
+func WithMaxLength(n int) ReadOption {
+	return func(o *readOptions) { o.maxLength = n }
+}
+
+// ReadPassword reads a password from the first line of r, the way the
+// command-line tool reads stdin, password files and credentials. The
+// trailing LF or CRLF is removed and anything after the first line is
+// ignored.
+//
+// At most the maximum length plus a CRLF is read from r, so oversized or
+// endless input fails with ErrPasswordTooLong without being buffered.
+// Input that is not valid UTF-8 fails with ErrInvalidUTF8. An empty line
+// is returned as an empty password; NewVerifier and the other derivation
+// functions reject it with ErrEmptyPassword. Errors from r are returned
+// as they are.
+//
+// The returned slice belongs to the caller, which can clear it once the
+// password has been used.
+func ReadPassword(r io.Reader, opts ...ReadOption) ([]byte, error) {
+	o := readOptions{maxLength: DefaultMaxPasswordLength}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxLength < 1 {
+		return nil, fmt.Errorf("scram: maximum password length must be at least 1, got %d", o.maxLength)
+	}
+
+	reader := bufio.NewReader(io.LimitReader(r, int64(o.maxLength)+2))
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		clear(line)
+		return nil, err
+	}
+
+	password := bytes.TrimRight(line, "\r\n")
+	if len(password) > o.maxLength {
+		clear(password)
+		return nil, fmt.Errorf("scram: %w: more than %d bytes", ErrPasswordTooLong, o.maxLength)
+	}
+	if !utf8.Valid(password) {
+		clear(password)
+		return nil, fmt.Errorf("scram: %w", ErrInvalidUTF8)
+	}
+	return password, nil
+}
@@ -0,0 +1,46 @@
+package scram
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"fmt"
+)
+
+// The RFC 5802 primitives, for protocol implementations and test suites
+// that build their own exchanges. Each takes the hash function as a
+// parameter, so they work for any hash linked into the program, not only
+// the ones with a registered mechanism. The package's own conversations
+// and verifiers are built from them.
+
+// H is the hash function: H(data) is h applied to data. It panics if h is
+// not available, as crypto.Hash.New does.
+func H(h crypto.Hash, data []byte) []byte {
+	d := h.New()
+	d.Write(data)
+	return d.Sum(nil)
+}
+
+// HMAC is HMAC(key, data) with h. It panics if h is not available.
+func HMAC(h crypto.Hash, key, data []byte) []byte {
+	mac := hmac.New(h.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Hi is Hi(password, salt, iterations), PBKDF2 with HMAC-h and an output
+// the size of h, computed with DefaultKDF. password must already be
+// normalized with SASLprep. SHA-1 is refused in FIPS 140-3 mode, as
+// elsewhere in the package.
+func Hi(h crypto.Hash, password, salt []byte, iterations int) ([]byte, error) {
+	if !h.Available() {
+		return nil, fmt.Errorf("scram: hash %v is not available", h)
+	}
+	if err := checkApproved(h); err != nil {
+		return nil, err
+	}
+	if iterations < 1 {
+		return nil, fmt.Errorf("scram: %w", ErrIterationsTooLow)
+	}
+	return saltedPassword(context.Background(), nil, h, string(password), salt, iterations)
+}
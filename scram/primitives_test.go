@@ -0,0 +1,130 @@
+package scram
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func mustBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestHiRFC6070 checks Hi against the PBKDF2-HMAC-SHA1 test vectors of
+// RFC 6070 whose output is the size of SHA-1.
+func TestHiRFC6070(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iterations     int
+		want           string
+	}{
+		{"password", "salt", 1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 2, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{"password", "salt", 4096, "4b007901b765489abead49d926f721d065a429c1"},
+		{"password", "salt", 16777216, "eefe3d61cd4da4e4e9945b3d6ba2158c2634e984"},
+	}
+	for _, tt := range tests {
+		if tt.iterations > 1<<20 && testing.Short() {
+			continue
+		}
+		got, err := Hi(crypto.SHA1, []byte(tt.password), []byte(tt.salt), tt.iterations)
+		if err != nil {
+			t.Fatalf("Hi(%d): %v", tt.iterations, err)
+		}
+		if want := mustHex(t, tt.want); !bytes.Equal(got, want) {
+			t.Errorf("Hi(%q, %q, %d) = %x, want %x", tt.password, tt.salt, tt.iterations, got, want)
+		}
+	}
+}
+
+// TestPrimitivesRFCExamples recomputes the example exchanges of RFC 5802
+// (SCRAM-SHA-1) and RFC 7677 (SCRAM-SHA-256), user "user" with password
+// "pencil", from H, HMAC and Hi.
+func TestPrimitivesRFCExamples(t *testing.T) {
+	tests := []struct {
+		name            string
+		hash            crypto.Hash
+		salt            string
+		clientNonce     string
+		serverNonce     string
+		saltedPassword  string
+		clientProof     string
+		serverSignature string
+	}{
+		{
+			name:            "RFC 5802",
+			hash:            crypto.SHA1,
+			salt:            "QSXCR+Q6sek8bf92",
+			clientNonce:     "fyko+d2lbbFgONRv9qkxdawL",
+			serverNonce:     "fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j",
+			saltedPassword:  "1d96ee3a529b5a5f9e47c01f229a2cb8a6e15f7d",
+			clientProof:     "v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=",
+			serverSignature: "rmF9pqV8S7suAoZWja4dJRkFsKQ=",
+		},
+		{
+			name:            "RFC 7677",
+			hash:            crypto.SHA256,
+			salt:            "W22ZaJ0SNY7soEsUEjb6gQ==",
+			clientNonce:     "rOprNGfwEbeRWgbNEkqO",
+			serverNonce:     "rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0",
+			saltedPassword:  "c4a49510323ab4f952cac1fa99441939e78ea74d6be81ddf7096e87513dc615d",
+			clientProof:     "dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+			serverSignature: "6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.hash
+			salted, err := Hi(h, []byte("pencil"), mustBase64(t, tt.salt), 4096)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := mustHex(t, tt.saltedPassword); !bytes.Equal(salted, want) {
+				t.Fatalf("SaltedPassword = %x, want %x", salted, want)
+			}
+
+			authMessage := []byte("n=user,r=" + tt.clientNonce +
+				",r=" + tt.serverNonce + ",s=" + tt.salt + ",i=4096" +
+				",c=biws,r=" + tt.serverNonce)
+			clientKey := HMAC(h, salted, []byte("Client Key"))
+			clientSignature := HMAC(h, H(h, clientKey), authMessage)
+			proof := make([]byte, len(clientKey))
+			for i := range proof {
+				proof[i] = clientKey[i] ^ clientSignature[i]
+			}
+			if got := base64.StdEncoding.EncodeToString(proof); got != tt.clientProof {
+				t.Errorf("ClientProof = %s, want %s", got, tt.clientProof)
+			}
+
+			serverSignature := HMAC(h, HMAC(h, salted, []byte("Server Key")), authMessage)
+			if got := base64.StdEncoding.EncodeToString(serverSignature); got != tt.serverSignature {
+				t.Errorf("ServerSignature = %s, want %s", got, tt.serverSignature)
+			}
+		})
+	}
+}
+
+func TestHiRejectsLowIterations(t *testing.T) {
+	for _, iterations := range []int{0, -1} {
+		if _, err := Hi(crypto.SHA256, []byte("pencil"), []byte("salt"), iterations); !errors.Is(err, ErrIterationsTooLow) {
+			t.Errorf("Hi with %d iterations: got %v, want ErrIterationsTooLow", iterations, err)
+		}
+	}
+}
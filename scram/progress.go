@@ -0,0 +1,25 @@
+package scram
+
+import "context"
+
+// ProgressFunc receives the number of PBKDF2 iterations completed out of
+// total. It is called from the deriving goroutine and should return
+// quickly.
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes derivations run with it
+// report progress to fn, roughly every 4096 iterations and once on
+// completion. In FIPS 140-3 mode the derivation cannot be split, so only
+// the start and end are reported.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func progressFrom(ctx context.Context) ProgressFunc {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		return fn
+	}
+	return func(int, int) {}
+}
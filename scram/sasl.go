@@ -0,0 +1,85 @@
+package scram
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+)
+
+func init() {
+	for i, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA512} {
+		register(h, 10*(i+1), false)
+		register(h, 10*(i+1)+1, true)
+	}
+}
+
+func register(h crypto.Hash, priority int, plus bool) {
+	name := MechanismName(h)
+	if plus {
+		name += "-PLUS"
+	}
+
+	sasl.Register(sasl.Mechanism{
+		Name:     name,
+		Priority: priority,
+		Plus:     plus,
+		NewClient: func(cfg sasl.ClientConfig) (sasl.Client, error) {
+			cc := ClientConfig{Hash: h, Username: cfg.Username, Password: cfg.Password, Authzid: cfg.Authzid}
+			if plus {
+				if cfg.ChannelBinding == nil {
+					return nil, errMissingChannelBinding(name)
+				}
+				cc.ChannelBinding = cfg.ChannelBinding
+			} else {
+				// "y" claims the server offered no -PLUS mechanism.
+				cc.ChannelBindingSupported = cfg.ChannelBinding != nil && !cfg.PlusAdvertised
+			}
+			return NewClient(cc)
+		},
+		NewServer: func(cfg sasl.ServerConfig) (sasl.Server, error) {
+			if plus && cfg.ChannelBinding == nil {
+				return nil, errMissingChannelBinding(name)
+			}
+			return NewServer(ServerConfig{
+				Hash:           h,
+				Lookup:         verifierLookup(h, cfg.Lookup),
+				Plus:           plus,
+				ChannelBinding: cfg.ChannelBinding,
+				PlusAdvertised: cfg.PlusAdvertised,
+				Authorize:      cfg.Authorize,
+			})
+		},
+	})
+}
+
+func errMissingChannelBinding(name string) error {
+	return fmt.Errorf("scram: %s requires channel binding data", name)
+}
+
+// verifierLookup adapts a lookup returning verifier strings to one
+// returning parsed credentials for hash h.
+func verifierLookup(h crypto.Hash, lookup func(string) (string, error)) CredentialLookup {
+	if lookup == nil {
+		return nil
+	}
+	return CredentialLookupFunc(func(username string) (StoredCredentials, error) {
+		verifier, err := lookup(username)
+		if err != nil {
+			return StoredCredentials{}, err
+		}
+		return parseVerifierFor(h, verifier)
+	})
+}
+
+// parseVerifierFor parses verifier and checks that it is for hash h.
+func parseVerifierFor(h crypto.Hash, verifier string) (StoredCredentials, error) {
+	vh, creds, err := ParseVerifier(verifier)
+	if err != nil {
+		return StoredCredentials{}, err
+	}
+	if vh != h {
+		return StoredCredentials{}, fmt.Errorf("scram: stored verifier is %s, not %s", MechanismName(vh), MechanismName(h))
+	}
+	return creds, nil
+}
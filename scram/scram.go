@@ -0,0 +1,334 @@
+// Package scram implements the Salted Challenge Response Authentication
+// Mechanism described in RFC 5802 and RFC 7677, for SHA-1, SHA-256 and
+// SHA-512 and their channel-binding -PLUS variants.
+//
+// Importing the package registers every variant with the sasl registry.
+package scram
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+const (
+	nonceLength = 18
+	saltLength  = 16
+)
+
+// StoredCredentials is what a server keeps for a user instead of the
+// plaintext password.
+type StoredCredentials struct {
+	Salt       []byte
+	Iterations int
+	StoredKey  []byte
+	ServerKey  []byte
+}
+
+// MechanismName returns the SASL mechanism name for h, such as
+// "SCRAM-SHA-256", or "" if h is not a supported SCRAM hash.
+func MechanismName(h crypto.Hash) string {
+	switch h {
+	case crypto.SHA1:
+		return "SCRAM-SHA-1"
+	case crypto.SHA256:
+		return "SCRAM-SHA-256"
+	case crypto.SHA512:
+		return "SCRAM-SHA-512"
+	}
+	return ""
+}
+
+// MechanismHash returns the hash used by the named SCRAM mechanism, with or
+// without the -PLUS suffix.
+func MechanismHash(name string) (crypto.Hash, bool) {
+	name = strings.TrimSuffix(name, "-PLUS")
+	for _, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA512} {
+		if MechanismName(h) == name {
+			return h, true
+		}
+	}
+	return 0, false
+}
+
+// NewStoredCredentials derives the stored credentials for password in
+// process memory using DefaultKDF.
+func NewStoredCredentials(h crypto.Hash, password string, salt []byte, iterations int) (StoredCredentials, error) {
+	return NewStoredCredentialsContext(context.Background(), h, password, salt, iterations)
+}
+
+// NewStoredCredentialsContext is like NewStoredCredentials but stops
+// early with ctx's error when ctx is done.
+func NewStoredCredentialsContext(ctx context.Context, h crypto.Hash, password string, salt []byte, iterations int) (StoredCredentials, error) {
+	return DeriveStoredCredentialsContext(ctx, SoftwareKeyDeriver{}, h, password, salt, iterations)
+}
+
+// DeriveStoredCredentials derives the stored credentials for password with d.
+func DeriveStoredCredentials(d KeyDeriver, h crypto.Hash, password string, salt []byte, iterations int) (StoredCredentials, error) {
+	return DeriveStoredCredentialsContext(context.Background(), d, h, password, salt, iterations)
+}
+
+// DeriveStoredCredentialsContext is like DeriveStoredCredentials but stops
+// early with ctx's error when ctx is done. KeyDerivers that do not
+// implement ContextKeyDeriver are only checked before they start.
+func DeriveStoredCredentialsContext(ctx context.Context, d KeyDeriver, h crypto.Hash, password string, salt []byte, iterations int) (StoredCredentials, error) {
+	if err := checkApproved(h); err != nil {
+		return StoredCredentials{}, err
+	}
+	if iterations < 1 {
+		return StoredCredentials{}, fmt.Errorf("scram: %w", ErrIterationsTooLow)
+	}
+
+	var clientKey, serverKey []byte
+	var err error
+	if cd, ok := d.(ContextKeyDeriver); ok {
+		clientKey, serverKey, err = cd.DeriveKeysContext(ctx, h, password, salt, iterations)
+	} else if err = ctx.Err(); err == nil {
+		clientKey, serverKey, err = d.DeriveKeys(h, password, salt, iterations)
+	}
+	if err != nil {
+		return StoredCredentials{}, err
+	}
+	return StoredCredentials{
+		Salt:       salt,
+		Iterations: iterations,
+		StoredKey:  H(h, clientKey),
+		ServerKey:  serverKey,
+	}, nil
+}
+
+// StoredCredentialsFromSaltedPassword builds the stored credentials from
+// a SaltedPassword computed elsewhere, such as on the user's own machine,
+// so the password itself is never seen. salt and iterations must be the
+// values SaltedPassword was derived with; they cannot be checked here.
+func StoredCredentialsFromSaltedPassword(h crypto.Hash, saltedPassword, salt []byte, iterations int) (StoredCredentials, error) {
+	if MechanismName(h) == "" {
+		return StoredCredentials{}, fmt.Errorf("scram: unsupported hash %v", h)
+	}
+	if err := checkApproved(h); err != nil {
+		return StoredCredentials{}, err
+	}
+	if iterations < 1 {
+		return StoredCredentials{}, fmt.Errorf("scram: %w", ErrIterationsTooLow)
+	}
+	if len(saltedPassword) != h.Size() {
+		return StoredCredentials{}, fmt.Errorf("scram: salted password is %d bytes, want %d for %s", len(saltedPassword), h.Size(), MechanismName(h))
+	}
+	clientKey, serverKey := keysFromSaltedPassword(h, saltedPassword)
+	return StoredCredentials{
+		Salt:       salt,
+		Iterations: iterations,
+		StoredKey:  H(h, clientKey),
+		ServerKey:  serverKey,
+	}, nil
+}
+
+// NewVerifier derives a verifier for password with a fresh random salt.
+func NewVerifier(h crypto.Hash, password string, iterations int) (string, error) {
+	return NewVerifierContext(context.Background(), h, password, iterations)
+}
+
+// NewVerifierContext is like NewVerifier but stops early with ctx's error
+// when ctx is done, so callers can put a deadline on high iteration counts.
+func NewVerifierContext(ctx context.Context, h crypto.Hash, password string, iterations int) (string, error) {
+	if MechanismName(h) == "" {
+		return "", fmt.Errorf("scram: unsupported hash %v", h)
+	}
+	if iterations < 1 {
+		return "", fmt.Errorf("scram: %w", ErrIterationsTooLow)
+	}
+	if password == "" {
+		return "", fmt.Errorf("scram: %w", ErrEmptyPassword)
+	}
+	if !utf8.ValidString(password) {
+		return "", fmt.Errorf("scram: %w", ErrInvalidUTF8)
+	}
+
+	salt := saltPool.Get().(*[saltLength]byte)
+	defer saltPool.Put(salt)
+	if _, err := rand.Read(salt[:]); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	creds, err := NewStoredCredentialsContext(ctx, h, password, salt[:], iterations)
+	if err != nil {
+		return "", err
+	}
+	return EncodeVerifier(h, creds), nil
+}
+
+// saltPool and encodePool hold scratch buffers reused across verifiers,
+// so that hashing many passwords in a row creates little garbage.
+var (
+	saltPool   = sync.Pool{New: func() any { return new([saltLength]byte) }}
+	encodePool = sync.Pool{New: func() any { return new([]byte) }}
+)
+
+// GenerateContext derives a SCRAM-SHA-256 verifier, the PostgreSQL
+// default, honouring ctx as NewVerifierContext does.
+func GenerateContext(ctx context.Context, password string, iterations int) (string, error) {
+	return NewVerifierContext(ctx, crypto.SHA256, password, iterations)
+}
+
+// Verify reports whether password matches verifier.
+func Verify(password, verifier string) (bool, error) {
+	return VerifyContext(context.Background(), password, verifier)
+}
+
+// VerifyContext is like Verify but stops early with ctx's error when ctx
+// is done.
+func VerifyContext(ctx context.Context, password, verifier string) (bool, error) {
+	h, creds, err := ParseVerifier(verifier)
+	if err != nil {
+		return false, err
+	}
+
+	got, err := NewStoredCredentialsContext(ctx, h, password, creds.Salt, creds.Iterations)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(got.StoredKey, creds.StoredKey) && hmac.Equal(got.ServerKey, creds.ServerKey), nil
+}
+
+// EncodeVerifier formats credentials in the PostgreSQL pg_authid layout:
+// MECHANISM$iterations:salt$storedkey:serverkey.
+func EncodeVerifier(h crypto.Hash, creds StoredCredentials) string {
+	bp := encodePool.Get().(*[]byte)
+	defer encodePool.Put(bp)
+
+	buf := append((*bp)[:0], MechanismName(h)...)
+	buf = append(buf, '$')
+	buf = strconv.AppendInt(buf, int64(creds.Iterations), 10)
+	buf = append(buf, ':')
+	buf = base64.StdEncoding.AppendEncode(buf, creds.Salt)
+	buf = append(buf, '$')
+	buf = base64.StdEncoding.AppendEncode(buf, creds.StoredKey)
+	buf = append(buf, ':')
+	buf = base64.StdEncoding.AppendEncode(buf, creds.ServerKey)
+	*bp = buf
+	return string(buf)
+}
+
+// ParseVerifier decodes a verifier produced by EncodeVerifier.
+func ParseVerifier(verifier string) (crypto.Hash, StoredCredentials, error) {
+	return ParseVerifierMode(verifier, ParseStandard)
+}
+
+// ParseVerifierMode is like ParseVerifier with a choice of strictness.
+// ParseStrict only accepts the canonical form EncodeVerifier produces;
+// ParseLenient also accepts surrounding whitespace, lower-case mechanism
+// names and unpadded base64.
+func ParseVerifierMode(verifier string, mode ParseMode) (crypto.Hash, StoredCredentials, error) {
+	if mode == ParseLenient {
+		verifier = strings.TrimSpace(verifier)
+	}
+	name, rest, ok := strings.Cut(verifier, "$")
+	if !ok {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: no mechanism prefix", ErrMalformedVerifier)
+	}
+	if mode == ParseLenient {
+		name = strings.ToUpper(name)
+	}
+	h, ok := MechanismHash(name)
+	if !ok || (mode == ParseStrict && name != MechanismName(h)) {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: unsupported mechanism %q", ErrMalformedVerifier, name)
+	}
+	decode := mode.decodeBase64
+
+	params, keys, ok := strings.Cut(rest, "$")
+	if !ok {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: missing the key section", ErrMalformedVerifier)
+	}
+	itersStr, saltB64, ok := strings.Cut(params, ":")
+	if !ok {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: missing the salt", ErrMalformedVerifier)
+	}
+	storedB64, serverB64, ok := strings.Cut(keys, ":")
+	if !ok {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: missing the server key", ErrMalformedVerifier)
+	}
+
+	var creds StoredCredentials
+	var err error
+	creds.Iterations, err = strconv.Atoi(itersStr)
+	if err != nil || creds.Iterations < 1 || (mode == ParseStrict && strconv.Itoa(creds.Iterations) != itersStr) {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: invalid iteration count %q", ErrMalformedVerifier, itersStr)
+	}
+	if creds.Salt, err = decode(saltB64); err != nil {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: invalid salt encoding: %w", ErrMalformedVerifier, err)
+	}
+	if creds.StoredKey, err = decode(storedB64); err != nil {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: invalid stored key encoding: %w", ErrMalformedVerifier, err)
+	}
+	if creds.ServerKey, err = decode(serverB64); err != nil {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: invalid server key encoding: %w", ErrMalformedVerifier, err)
+	}
+	if len(creds.StoredKey) != h.Size() || len(creds.ServerKey) != h.Size() {
+		return 0, StoredCredentials{}, fmt.Errorf("scram: %w: key length does not match %s", ErrMalformedVerifier, name)
+	}
+	return h, creds, nil
+}
+
+// Proof holds every value derived while authenticating one exchange, for
+// debugging handshakes that fail.
+type Proof struct {
+	SaltedPassword  []byte
+	ClientKey       []byte
+	StoredKey       []byte
+	ServerKey       []byte
+	ClientSignature []byte
+	ClientProof     []byte
+	ServerSignature []byte
+}
+
+// ComputeProof derives the client proof and server signature for
+// authMessage, which is client-first-bare + "," + server-first + "," +
+// client-final-without-proof, using DefaultKDF.
+func ComputeProof(h crypto.Hash, password string, salt []byte, iterations int, authMessage string) (Proof, error) {
+	return computeProof(context.Background(), nil, h, password, salt, iterations, authMessage)
+}
+
+func computeProof(ctx context.Context, kdf KDF, h crypto.Hash, password string, salt []byte, iterations int, authMessage string) (Proof, error) {
+	if err := checkApproved(h); err != nil {
+		return Proof{}, err
+	}
+
+	var p Proof
+	var err error
+	if p.SaltedPassword, err = saltedPassword(ctx, kdf, h, password, salt, iterations); err != nil {
+		return Proof{}, err
+	}
+	p.ClientKey = HMAC(h, p.SaltedPassword, []byte("Client Key"))
+	p.StoredKey = H(h, p.ClientKey)
+	p.ServerKey = HMAC(h, p.SaltedPassword, []byte("Server Key"))
+	p.ClientSignature = HMAC(h, p.StoredKey, []byte(authMessage))
+	p.ClientProof = xorBytes(p.ClientKey, p.ClientSignature)
+	p.ServerSignature = HMAC(h, p.ServerKey, []byte(authMessage))
+	return p, nil
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, nonceLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("scram: failed to generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,37 @@
+package scram
+
+import (
+	"crypto"
+	"testing"
+)
+
+var benchSalt = []byte("0123456789abcdef")
+
+func BenchmarkNewVerifier(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewVerifier(crypto.SHA256, "pencil", 4096); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDeriveStoredCredentials(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := DeriveStoredCredentials(SoftwareKeyDeriver{}, crypto.SHA256, "pencil", benchSalt, 4096); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeVerifier(b *testing.B) {
+	creds, err := NewStoredCredentials(crypto.SHA256, "pencil", benchSalt, 4096)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		EncodeVerifier(crypto.SHA256, creds)
+	}
+}
@@ -0,0 +1,316 @@
+package scram
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+)
+
+// CredentialLookup finds the stored credentials for a user.
+type CredentialLookup interface {
+	Lookup(username string) (StoredCredentials, error)
+}
+
+// CredentialLookupFunc adapts an ordinary function to CredentialLookup.
+type CredentialLookupFunc func(username string) (StoredCredentials, error)
+
+// Lookup calls f(username).
+func (f CredentialLookupFunc) Lookup(username string) (StoredCredentials, error) {
+	return f(username)
+}
+
+// ServerConfig configures a server conversation.
+type ServerConfig struct {
+	Hash crypto.Hash
+	// Lookup returns the stored credentials for a user.
+	Lookup CredentialLookup
+	// Plus is set when the client selected the -PLUS mechanism, in which
+	// case it must bind to ChannelBinding.
+	Plus           bool
+	ChannelBinding *sasl.ChannelBinding
+	// PlusAdvertised is set when the server offered -PLUS mechanisms. A
+	// client that can bind but believes the server cannot sends "y", which
+	// then means the mechanism list was tampered with, so it is rejected.
+	PlusAdvertised bool
+	// Nonce controls the server nonce and the client nonce accepted.
+	Nonce NoncePolicy
+	// Authorize decides whether the authenticated username may act as the
+	// authzid the client asked for. It is called once the client proof
+	// has been verified, and only when authzid is set and differs from
+	// the username. When it is nil such requests are refused.
+	Authorize func(username, authzid string) error
+	// Parsing selects how strictly client messages are parsed.
+	Parsing ParseMode
+	// Extensions are optional attributes appended to the server-first
+	// message, keyed by their one-letter names.
+	Extensions map[string]string
+	// When Lookup fails with ErrUnknownUser the server still answers with
+	// a server-first message, as RFC 5802 section 9 recommends, and fails
+	// at the proof check, so clients cannot tell unknown users from wrong
+	// passwords. The fake salt is derived from the username with
+	// FakeSaltKey, so it is the same on every attempt; servers behind one
+	// address should share the key. When it is nil a random key is made
+	// once per process. FakeIterations is the iteration count sent,
+	// 4096 when zero; set it to the count real users have.
+	FakeSaltKey    []byte
+	FakeIterations int
+}
+
+// defaultFakeIterations is the count sent for unknown users by default,
+// the minimum RFC 7677 recommends.
+const defaultFakeIterations = 4096
+
+var (
+	processFakeSaltKeyOnce sync.Once
+	processFakeSaltKey     []byte
+)
+
+type serverState int
+
+const (
+	serverStart serverState = iota
+	serverFirstSent
+	serverDone
+	serverFailed
+)
+
+// Server is the server side of a SCRAM conversation. It implements
+// sasl.Server.
+type Server struct {
+	cfg   ServerConfig
+	state serverState
+
+	username        string
+	gs2             gs2Header
+	creds           StoredCredentials
+	nonce           string
+	clientFirstBare string
+	serverFirst     string
+	extensions      map[string]string
+	// unknown holds the lookup error while the conversation continues
+	// with fake credentials for a user that does not exist.
+	unknown error
+}
+
+// NewServer starts a server conversation.
+func NewServer(cfg ServerConfig) (*Server, error) {
+	if MechanismName(cfg.Hash) == "" {
+		return nil, fmt.Errorf("scram: unsupported hash %v", cfg.Hash)
+	}
+	if err := checkApproved(cfg.Hash); err != nil {
+		return nil, err
+	}
+	if cfg.Lookup == nil {
+		return nil, fmt.Errorf("scram: server requires a credential lookup")
+	}
+	if cfg.Plus && cfg.ChannelBinding == nil {
+		return nil, fmt.Errorf("scram: -PLUS mechanism requires channel binding data")
+	}
+	if err := cfg.Nonce.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkExtensions(cfg.Extensions); err != nil {
+		return nil, err
+	}
+	return &Server{cfg: cfg}, nil
+}
+
+// Username returns the authentication identity sent by the client.
+func (s *Server) Username() string {
+	return s.username
+}
+
+// Authzid returns the identity the client asked to act as, or the
+// username when it did not ask for one. After a successful exchange it is
+// the identity the session should run as.
+func (s *Server) Authzid() string {
+	if s.gs2.authzid == "" {
+		return s.username
+	}
+	return s.gs2.authzid
+}
+
+// Extensions returns the optional attributes the client sent that this
+// package does not interpret, keyed by their one-letter names.
+func (s *Server) Extensions() map[string]string {
+	return s.extensions
+}
+
+// Next processes the client-first or client-final message.
+func (s *Server) Next(response []byte) ([]byte, bool, error) {
+	switch s.state {
+	case serverStart:
+		challenge, err := s.serverFirstMessage(string(response))
+		if err != nil {
+			s.state = serverFailed
+			return []byte("e=" + errorValue(err)), true, err
+		}
+		s.state = serverFirstSent
+		return challenge, false, nil
+	case serverFirstSent:
+		challenge, errValue, err := s.serverFinalMessage(string(response))
+		if err != nil {
+			s.state = serverFailed
+			return []byte("e=" + errValue), true, err
+		}
+		s.state = serverDone
+		return challenge, true, nil
+	}
+	return nil, true, fmt.Errorf("scram: conversation is finished")
+}
+
+func (s *Server) serverFirstMessage(clientFirst string) ([]byte, error) {
+	gs2, bare, err := splitClientFirst(clientFirst)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case gs2.cbindFlag == "p" && !s.cfg.Plus:
+		return nil, fmt.Errorf("scram: channel binding requested without -PLUS mechanism")
+	case gs2.cbindFlag != "p" && s.cfg.Plus:
+		return nil, fmt.Errorf("scram: -PLUS mechanism selected without channel binding")
+	case gs2.cbindFlag == "p" && gs2.cbindType != s.cfg.ChannelBinding.Type:
+		return nil, fmt.Errorf("scram: unsupported channel binding type %q", gs2.cbindType)
+	case gs2.cbindFlag == "y" && s.cfg.PlusAdvertised:
+		return nil, fmt.Errorf("scram: client supports channel binding but did not use the advertised -PLUS mechanism")
+	}
+
+	attrs, err := parseAttributes(bare)
+	if err != nil {
+		return nil, err
+	}
+	values, err := expectAttributes(attrs, "nr", s.cfg.Parsing)
+	if err != nil {
+		return nil, err
+	}
+	s.extensions = collectExtensions(s.extensions, attrs, "nr")
+	if s.username, err = unescapeName(values[0]); err != nil {
+		return nil, err
+	}
+	if err := s.cfg.Nonce.check("client", values[1]); err != nil {
+		return nil, err
+	}
+
+	if s.creds, err = s.cfg.Lookup.Lookup(s.username); err != nil {
+		if !errors.Is(err, ErrUnknownUser) {
+			return nil, fmt.Errorf("scram: credential lookup for %q failed: %w", s.username, err)
+		}
+		s.unknown = fmt.Errorf("scram: credential lookup for %q failed: %w", s.username, err)
+		if s.creds, err = s.fakeCredentials(); err != nil {
+			return nil, err
+		}
+	}
+
+	serverNonce, err := s.cfg.Nonce.generate()
+	if err != nil {
+		return nil, err
+	}
+	s.gs2 = gs2
+	s.nonce = values[1] + serverNonce
+	s.clientFirstBare = bare
+	s.serverFirst = appendExtensions("r="+s.nonce+
+		",s="+base64.StdEncoding.EncodeToString(s.creds.Salt)+
+		",i="+strconv.Itoa(s.creds.Iterations), s.cfg.Extensions)
+	return []byte(s.serverFirst), nil
+}
+
+// serverFinalMessage verifies the client proof. On failure it also returns
+// the server-error-value to report to the client.
+func (s *Server) serverFinalMessage(clientFinal string) ([]byte, string, error) {
+	withoutProof, proofAttr, ok := strings.Cut(clientFinal, ",p=")
+	if !ok {
+		return nil, "other-error", fmt.Errorf("scram: client-final message has no proof")
+	}
+	attrs, err := parseAttributes(withoutProof)
+	if err != nil {
+		return nil, "other-error", err
+	}
+	values, err := expectAttributes(attrs, "cr", s.cfg.Parsing)
+	if err != nil {
+		return nil, errorValue(err), err
+	}
+	s.extensions = collectExtensions(s.extensions, attrs, "cr")
+
+	cbind, err := s.cfg.Parsing.decodeBase64(values[0])
+	if err != nil {
+		return nil, "other-error", fmt.Errorf("scram: invalid channel binding encoding: %w", err)
+	}
+	expected := []byte(s.gs2.String())
+	if s.gs2.cbindFlag == "p" {
+		expected = append(expected, s.cfg.ChannelBinding.Data...)
+	}
+	if !hmac.Equal(cbind, expected) {
+		return nil, "channel-bindings-dont-match", fmt.Errorf("scram: channel binding mismatch")
+	}
+	if values[1] != s.nonce {
+		return nil, "other-error", fmt.Errorf("scram: nonce mismatch")
+	}
+
+	proof, err := s.cfg.Parsing.decodeBase64(proofAttr)
+	if err != nil {
+		return nil, "other-error", fmt.Errorf("scram: invalid proof encoding: %w", err)
+	}
+
+	h := s.cfg.Hash
+	if len(proof) != h.Size() {
+		return nil, "invalid-proof", fmt.Errorf("scram: %w: invalid proof length", ErrProofMismatch)
+	}
+	authMessage := s.clientFirstBare + "," + s.serverFirst + "," + withoutProof
+	clientSignature := HMAC(h, s.creds.StoredKey, []byte(authMessage))
+	clientKey := xorBytes(proof, clientSignature)
+	// The check runs for unknown users too, so that they take as long to
+	// reject as a wrong password.
+	if !hmac.Equal(H(h, clientKey), s.creds.StoredKey) || s.unknown != nil {
+		if s.unknown != nil {
+			return nil, "invalid-proof", s.unknown
+		}
+		return nil, "invalid-proof", fmt.Errorf("scram: %w", ErrProofMismatch)
+	}
+	if authzid := s.gs2.authzid; authzid != "" && authzid != s.username {
+		if s.cfg.Authorize == nil {
+			return nil, "other-error", fmt.Errorf("scram: %w: %q may not act as %q", ErrNotAuthorized, s.username, authzid)
+		}
+		if err := s.cfg.Authorize(s.username, authzid); err != nil {
+			return nil, "other-error", fmt.Errorf("scram: %w: %q may not act as %q: %w", ErrNotAuthorized, s.username, authzid, err)
+		}
+	}
+
+	serverSignature := HMAC(h, s.creds.ServerKey, []byte(authMessage))
+	return []byte("v=" + base64.StdEncoding.EncodeToString(serverSignature)), "", nil
+}
+
+// fakeCredentials returns the credentials presented for an unknown user:
+// a salt derived from the username and a StoredKey no proof can match.
+func (s *Server) fakeCredentials() (StoredCredentials, error) {
+	key := s.cfg.FakeSaltKey
+	if key == nil {
+		processFakeSaltKeyOnce.Do(func() {
+			processFakeSaltKey = make([]byte, 32)
+			if _, err := rand.Read(processFakeSaltKey); err != nil {
+				panic("scram: failed to generate fake salt key: " + err.Error())
+			}
+		})
+		key = processFakeSaltKey
+	}
+	iterations := s.cfg.FakeIterations
+	if iterations <= 0 {
+		iterations = defaultFakeIterations
+	}
+	storedKey := make([]byte, s.cfg.Hash.Size())
+	if _, err := rand.Read(storedKey); err != nil {
+		return StoredCredentials{}, err
+	}
+	return StoredCredentials{
+		Salt:       HMAC(crypto.SHA256, key, []byte("scram fake salt\x00"+s.username))[:saltLength],
+		Iterations: iterations,
+		StoredKey:  storedKey,
+	}, nil
+}
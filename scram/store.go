@@ -0,0 +1,161 @@
+package scram
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a CredentialLookup backed by a map. It is safe for
+// concurrent use.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	users map[string]StoredCredentials
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: make(map[string]StoredCredentials)}
+}
+
+// Set stores the credentials for username, replacing any existing ones.
+func (m *MemoryStore) Set(username string, creds StoredCredentials) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[username] = creds
+}
+
+// Delete removes username.
+func (m *MemoryStore) Delete(username string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.users, username)
+}
+
+// Lookup implements CredentialLookup.
+func (m *MemoryStore) Lookup(username string) (StoredCredentials, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	creds, ok := m.users[username]
+	if !ok {
+		return StoredCredentials{}, ErrUnknownUser
+	}
+	return creds, nil
+}
+
+// LoadUserlist reads a PgBouncer-style userlist.txt from path; see
+// ReadUserlist.
+func LoadUserlist(path string, h crypto.Hash, iterations int) (*MemoryStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadUserlist(f, h, iterations)
+}
+
+// ReadUserlist parses a PgBouncer-style userlist.txt, one
+// "username" "secret" pair per line with quotes doubled inside values.
+// Secrets that are verifiers for h are used as they are, plain passwords
+// are hashed with a random salt and the given iteration count, and md5
+// hashes or verifiers for other mechanisms are skipped. Lines that do not
+// start with a quote are ignored.
+func ReadUserlist(r io.Reader, h crypto.Hash, iterations int) (*MemoryStore, error) {
+	store := NewMemoryStore()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, `"`) {
+			continue
+		}
+
+		username, rest, err := readQuoted(text)
+		if err != nil {
+			return nil, fmt.Errorf("scram: userlist line %d: %w", line, err)
+		}
+		secret, _, err := readQuoted(strings.TrimLeft(rest, " \t"))
+		if err != nil {
+			return nil, fmt.Errorf("scram: userlist line %d: %w", line, err)
+		}
+
+		switch {
+		case strings.HasPrefix(secret, "SCRAM-"):
+			vh, creds, err := ParseVerifier(secret)
+			if err != nil {
+				return nil, fmt.Errorf("scram: userlist line %d: %w", line, err)
+			}
+			if vh == h {
+				store.Set(username, creds)
+			}
+		case len(secret) == 35 && strings.HasPrefix(secret, "md5"):
+			// md5 hashes cannot be converted to SCRAM credentials.
+		default:
+			salt := make([]byte, saltLength)
+			if _, err := rand.Read(salt); err != nil {
+				return nil, fmt.Errorf("failed to generate salt: %w", err)
+			}
+			creds, err := NewStoredCredentials(h, secret, salt, iterations)
+			if err != nil {
+				return nil, err
+			}
+			store.Set(username, creds)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// readQuoted reads a double-quoted value from the start of s and returns
+// it with the remainder of s.
+func readQuoted(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", fmt.Errorf("expected a quoted value")
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '"' {
+			b.WriteByte('"')
+			i++
+			continue
+		}
+		return b.String(), s[i+1:], nil
+	}
+	return "", "", fmt.Errorf("unterminated quoted value")
+}
+
+// SQLStore looks verifiers up in a database. Query takes the user name as
+// its only argument and returns a single verifier column, for example
+// "SELECT rolpassword FROM pg_authid WHERE rolname = $1" on PostgreSQL.
+type SQLStore struct {
+	DB    *sql.DB
+	Query string
+	Hash  crypto.Hash
+}
+
+// Lookup implements CredentialLookup.
+func (s *SQLStore) Lookup(username string) (StoredCredentials, error) {
+	var verifier sql.NullString
+	err := s.DB.QueryRow(s.Query, username).Scan(&verifier)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !verifier.Valid) {
+		return StoredCredentials{}, ErrUnknownUser
+	}
+	if err != nil {
+		return StoredCredentials{}, err
+	}
+	return parseVerifierFor(s.Hash, verifier.String)
+}
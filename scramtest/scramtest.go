@@ -0,0 +1,155 @@
+// Package scramtest provides an in-memory SCRAM client and server pair and
+// canned user fixtures for unit tests of code built on the scram package's
+// conversation API, so authentication paths can be tested without a
+// database or a network connection:
+//
+//	client, server, err := scramtest.NewPair(crypto.SHA256, "alice", scramtest.Alice.Password, scramtest.Alice)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	if _, err := scramtest.Exchange(client, server); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// To test a client against a server on a socket instead, see the
+// testscram package.
+package scramtest
+
+import (
+	"crypto"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/SonOfBytes/scram-sha-256/sasl"
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// Fixture is a user with a known password and fixed salt, so its stored
+// credentials and verifier are the same on every run.
+type Fixture struct {
+	Username   string
+	Password   string
+	Hash       crypto.Hash
+	Salt       []byte
+	Iterations int
+}
+
+// Canned fixtures. RFC5802User and RFC7677User are the users of the
+// example exchanges in those RFCs, so their credentials can be checked
+// against the published values; RFC5802User uses SCRAM-SHA-1, which is
+// refused in FIPS 140-3 mode.
+var (
+	RFC5802User = Fixture{Username: "user", Password: "pencil", Hash: crypto.SHA1, Salt: mustDecode("QSXCR+Q6sek8bf92"), Iterations: 4096}
+	RFC7677User = Fixture{Username: "user", Password: "pencil", Hash: crypto.SHA256, Salt: mustDecode("W22ZaJ0SNY7soEsUEjb6gQ=="), Iterations: 4096}
+	Alice       = Fixture{Username: "alice", Password: "correct horse battery staple", Hash: crypto.SHA256, Salt: []byte("scramtest-alice!"), Iterations: 4096}
+	Bob         = Fixture{Username: "bob", Password: "Tr0ub4dor&3", Hash: crypto.SHA256, Salt: []byte("scramtest-bob!!!"), Iterations: 4096}
+	Carol       = Fixture{Username: "carol", Password: "hunter2 but longer", Hash: crypto.SHA512, Salt: []byte("scramtest-carol!"), Iterations: 4096}
+)
+
+func mustDecode(s string) []byte {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Credentials derives the fixture's stored credentials. It panics if they
+// cannot be derived, as for a hash that is not linked in or not approved
+// in FIPS 140-3 mode.
+func (f Fixture) Credentials() scram.StoredCredentials {
+	creds, err := scram.NewStoredCredentials(f.Hash, f.Password, f.Salt, f.Iterations)
+	if err != nil {
+		panic(fmt.Sprintf("scramtest: fixture %s: %v", f.Username, err))
+	}
+	return creds
+}
+
+// Verifier returns the fixture's verifier, as stored by PostgreSQL.
+func (f Fixture) Verifier() string {
+	return scram.EncodeVerifier(f.Hash, f.Credentials())
+}
+
+// Store returns a credential store holding the fixtures, for a server
+// configured by hand. Lookups of other users fail with
+// scram.ErrUnknownUser.
+func Store(fixtures ...Fixture) *scram.MemoryStore {
+	store := scram.NewMemoryStore()
+	for _, f := range fixtures {
+		store.Set(f.Username, f.Credentials())
+	}
+	return store
+}
+
+// NewServer returns a server conversation for mechanism h that knows the
+// fixtures. Fixtures for another hash fail to authenticate, as they would
+// against a real server.
+func NewServer(h crypto.Hash, fixtures ...Fixture) (*scram.Server, error) {
+	return scram.NewServer(scram.ServerConfig{Hash: h, Lookup: Store(fixtures...)})
+}
+
+// NewPair returns a client conversation authenticating as username with
+// password, and a server conversation that knows the fixtures, both for
+// mechanism h.
+func NewPair(h crypto.Hash, username, password string, fixtures ...Fixture) (*scram.Client, *scram.Server, error) {
+	client, err := scram.NewClient(scram.ClientConfig{Hash: h, Username: username, Password: password})
+	if err != nil {
+		return nil, nil, err
+	}
+	server, err := NewServer(h, fixtures...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, server, nil
+}
+
+// Transcript holds the messages of a conversation run by Exchange. Those
+// after a failure are empty.
+type Transcript struct {
+	ClientFirst string
+	ServerFirst string
+	ClientFinal string
+	ServerFinal string
+}
+
+// Exchange runs a conversation between client and server in memory,
+// passing each message straight to the other side, and returns the
+// messages exchanged. When either side fails, the error wraps that
+// side's error, so tests can match it with errors.Is against the scram
+// package's failure classes; a server failure is reported in preference
+// to the client's reaction to it.
+func Exchange(client sasl.Client, server sasl.Server) (Transcript, error) {
+	var t Transcript
+	msgs := []*string{&t.ClientFirst, &t.ServerFirst, &t.ClientFinal, &t.ServerFinal}
+	record := func(msg []byte) {
+		if len(msgs) > 0 {
+			*msgs[0] = string(msg)
+			msgs = msgs[1:]
+		}
+	}
+
+	response, err := client.Start()
+	if err != nil {
+		return t, fmt.Errorf("scramtest: client: %w", err)
+	}
+	// SCRAM takes two round trips; the bound stops a misbehaving
+	// implementation from looping forever.
+	for range 4 {
+		record(response)
+		challenge, done, err := server.Next(response)
+		record(challenge)
+		if err != nil {
+			return t, fmt.Errorf("scramtest: server: %w", err)
+		}
+		if response, err = client.Next(challenge); err != nil {
+			return t, fmt.Errorf("scramtest: client: %w", err)
+		}
+		if done {
+			if !client.Done() {
+				return t, fmt.Errorf("scramtest: server finished but the client did not")
+			}
+			return t, nil
+		}
+	}
+	return t, fmt.Errorf("scramtest: conversation did not finish")
+}
@@ -0,0 +1,187 @@
+package scramtest_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/scramtest"
+)
+
+// rfcExchanges are the example exchanges of RFC 5802 section 5 and
+// RFC 7677 section 3, with the values a verifier for their user must
+// reproduce.
+var rfcExchanges = []struct {
+	fixture         scramtest.Fixture
+	verifier        string
+	authMessage     string
+	clientProof     string
+	serverSignature string
+}{
+	{
+		fixture:         scramtest.RFC5802User,
+		verifier:        "SCRAM-SHA-1$4096:QSXCR+Q6sek8bf92$6dlGYMOdZcOPutkcNY8U2g7vK9Y=:D+CSWLOshSulAsxiupA+qs2/fTE=",
+		authMessage:     "n=user,r=fyko+d2lbbFgONRv9qkxdawL,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096,c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j",
+		clientProof:     "v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=",
+		serverSignature: "rmF9pqV8S7suAoZWja4dJRkFsKQ=",
+	},
+	{
+		fixture:         scramtest.RFC7677User,
+		verifier:        "SCRAM-SHA-256$4096:W22ZaJ0SNY7soEsUEjb6gQ==$WG5d8oPm3OtcPnkdi4Uo7BkeZkBFzpcXkuLmtbsT4qY=:wfPLwcE6nTWhTAmQ7tl2KeoiWGPlZqQxSrmfPwDl2dU=",
+		authMessage:     "n=user,r=rOprNGfwEbeRWgbNEkqO,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096,c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0",
+		clientProof:     "dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+		serverSignature: "6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+	},
+}
+
+// TestRFCFixtureVerifiers pins the verifiers of the RFC fixtures and
+// checks them against the published exchanges: the ServerKey must sign the
+// AuthMessage to the published ServerSignature, and the published
+// ClientProof must recover a ClientKey that hashes to the StoredKey, just
+// as a server verifies it.
+func TestRFCFixtureVerifiers(t *testing.T) {
+	for _, tc := range rfcExchanges {
+		t.Run(scram.MechanismName(tc.fixture.Hash), func(t *testing.T) {
+			if got := tc.fixture.Verifier(); got != tc.verifier {
+				t.Fatalf("Verifier() = %s, want %s", got, tc.verifier)
+			}
+			h, creds, err := scram.ParseVerifier(tc.verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature := scram.HMAC(h, creds.ServerKey, []byte(tc.authMessage))
+			if got := base64.StdEncoding.EncodeToString(signature); got != tc.serverSignature {
+				t.Errorf("ServerSignature = %s, want %s", got, tc.serverSignature)
+			}
+
+			proof, _ := base64.StdEncoding.DecodeString(tc.clientProof)
+			clientSignature := scram.HMAC(h, creds.StoredKey, []byte(tc.authMessage))
+			clientKey := make([]byte, len(proof))
+			for i := range proof {
+				clientKey[i] = proof[i] ^ clientSignature[i]
+			}
+			if !hmac.Equal(scram.H(h, clientKey), creds.StoredKey) {
+				t.Error("the published ClientProof does not verify against StoredKey")
+			}
+		})
+	}
+}
+
+func TestFixturesAuthenticate(t *testing.T) {
+	for _, f := range []scramtest.Fixture{scramtest.RFC5802User, scramtest.RFC7677User, scramtest.Alice, scramtest.Bob, scramtest.Carol} {
+		t.Run(scram.MechanismName(f.Hash)+"/"+f.Username, func(t *testing.T) {
+			client, server, err := scramtest.NewPair(f.Hash, f.Username, f.Password, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			transcript, err := scramtest.Exchange(client, server)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, msg := range map[string]string{
+				"client-first": transcript.ClientFirst, "server-first": transcript.ServerFirst,
+				"client-final": transcript.ClientFinal, "server-final": transcript.ServerFinal,
+			} {
+				if msg == "" {
+					t.Errorf("%s not recorded", name)
+				}
+			}
+			if !strings.HasPrefix(transcript.ServerFinal, "v=") {
+				t.Errorf("server-final %q, want a signature", transcript.ServerFinal)
+			}
+			salt := base64.StdEncoding.EncodeToString(f.Salt)
+			if !strings.Contains(transcript.ServerFirst, ",s="+salt+",i=4096") {
+				t.Errorf("server-first %q does not carry the fixture's salt and iterations", transcript.ServerFirst)
+			}
+		})
+	}
+}
+
+func TestFixtureCredentialsAreStable(t *testing.T) {
+	a, b := scramtest.Alice.Credentials(), scramtest.Alice.Credentials()
+	if !bytes.Equal(a.StoredKey, b.StoredKey) || !bytes.Equal(a.ServerKey, b.ServerKey) {
+		t.Error("Credentials() differs between calls")
+	}
+	if scramtest.Alice.Verifier() == scramtest.Bob.Verifier() {
+		t.Error("Alice and Bob share a verifier")
+	}
+}
+
+func TestExchangeFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		h                  crypto.Hash
+		username, password string
+		fixtures           []scramtest.Fixture
+		want               error
+	}{
+		{"wrong password", crypto.SHA256, "alice", "wrong", []scramtest.Fixture{scramtest.Alice}, scram.ErrProofMismatch},
+		// The server knows the user is unknown, but the client sees the
+		// same e=invalid-proof as for a wrong password.
+		{"unknown user", crypto.SHA256, "mallory", "anything", []scramtest.Fixture{scramtest.Alice}, scram.ErrUnknownUser},
+		// Carol's credentials are SHA-512, so a SHA-256 server cannot use them.
+		{"fixture for another hash", crypto.SHA256, "carol", scramtest.Carol.Password, []scramtest.Fixture{scramtest.Carol}, scram.ErrProofMismatch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, server, err := scramtest.NewPair(tc.h, tc.username, tc.password, tc.fixtures...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			transcript, err := scramtest.Exchange(client, server)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+			if !strings.HasPrefix(err.Error(), "scramtest: server: ") {
+				t.Errorf("error %q does not name the failing side", err)
+			}
+			if transcript.ServerFinal != "e=invalid-proof" {
+				t.Errorf("server-final %q, want e=invalid-proof", transcript.ServerFinal)
+			}
+		})
+	}
+}
+
+// TestExchangeClientFailure corrupts the server signature, so the client
+// is the side that fails.
+func TestExchangeClientFailure(t *testing.T) {
+	client, server, err := scramtest.NewPair(crypto.SHA256, "alice", scramtest.Alice.Password, scramtest.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = scramtest.Exchange(client, corruptFinal{server})
+	if !errors.Is(err, scram.ErrServerSignatureMismatch) || !strings.HasPrefix(err.Error(), "scramtest: client: ") {
+		t.Errorf("got %v, want a client ErrServerSignatureMismatch", err)
+	}
+}
+
+type corruptFinal struct{ *scram.Server }
+
+func (c corruptFinal) Next(response []byte) ([]byte, bool, error) {
+	challenge, done, err := c.Server.Next(response)
+	if done && err == nil {
+		sig, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(challenge), "v="))
+		sig[0] ^= 1
+		challenge = []byte("v=" + base64.StdEncoding.EncodeToString(sig))
+	}
+	return challenge, done, err
+}
+
+func TestStore(t *testing.T) {
+	store := scramtest.Store(scramtest.Alice, scramtest.Bob)
+	creds, err := store.Lookup("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := scramtest.Bob.Credentials(); !bytes.Equal(creds.StoredKey, want.StoredKey) {
+		t.Error("Store returned other credentials for bob")
+	}
+	if _, err := store.Lookup("carol"); !errors.Is(err, scram.ErrUnknownUser) {
+		t.Errorf("got %v, want ErrUnknownUser", err)
+	}
+}
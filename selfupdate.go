@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// updatePublicKey is the minisign public key release checksums are signed
+// with, set at build time with -ldflags "-X main.updatePublicKey=RW...".
+var updatePublicKey = ""
+
+const (
+	latestReleaseURL = "https://api.github.com/repos/SonOfBytes/scram-sha-256/releases/latest"
+
+	// Each release carries one binary per platform, a SHA256SUMS file
+	// listing them and its minisign signature.
+	checksumsAsset  = "SHA256SUMS"
+	signatureAsset  = "SHA256SUMS.minisig"
+	maxDownloadSize = 64 << 20
+)
+
+type selfUpdateConfig struct {
+	Check     bool
+	PublicKey string
+}
+
+func selfUpdateFlags(config *selfUpdateConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("self-update", flag.ExitOnError)
+	fs.BoolVar(&config.Check, "check", false, "Only report whether a newer release is available")
+	fs.StringVar(&config.PublicKey, "public-key", updatePublicKey, "Minisign public key the release checksums must be signed with")
+	return fs
+}
+
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r release) assetURL(name string) (string, error) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, nil
+		}
+	}
+	return "", errorf("release %s has no %s", r.TagName, name)
+}
+
+func runSelfUpdate(args []string) int {
+	config := selfUpdateConfig{}
+
+	fs := selfUpdateFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if !config.Check && config.PublicKey == "" {
+		fmt.Fprintln(os.Stderr, "Error: no release signing key; pass -public-key or build with -X main.updatePublicKey")
+		return exitError
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	var latest release
+	if err := fetchJSON(client, latestReleaseURL, &latest); err != nil {
+		fmt.Fprintf(os.Stderr, "Error checking for updates: %v\n", err)
+		return exitError
+	}
+	current := toolVersion()
+	if !newerVersion(latest.TagName, current) {
+		fmt.Printf("scram-sha-256 %s is up to date\n", current)
+		return 0
+	}
+	if config.Check {
+		fmt.Printf("scram-sha-256 %s is available (installed: %s)\n", latest.TagName, current)
+		return 0
+	}
+
+	binary, err := downloadRelease(client, latest, config.PublicKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+	exe, err := replaceExecutable(binary)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error replacing %s: %v\n", exe, err)
+		return exitError
+	}
+	fmt.Printf("Updated %s from %s to %s\n", exe, current, latest.TagName)
+	return 0
+}
+
+// releaseAssetName is the name of this platform's binary in a release.
+func releaseAssetName() string {
+	name := fmt.Sprintf("scram-sha-256_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// downloadRelease fetches this platform's binary from r, checking it
+// against SHA256SUMS only once the signature on that file has verified.
+func downloadRelease(client *http.Client, r release, publicKey string) ([]byte, error) {
+	download := func(name string) ([]byte, error) {
+		url, err := r.assetURL(name)
+		if err != nil {
+			return nil, err
+		}
+		return fetch(client, url)
+	}
+
+	sums, err := download(checksumsAsset)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := download(signatureAsset)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyMinisign(publicKey, sums, sig); err != nil {
+		return nil, errorf("%s: %w", checksumsAsset, err)
+	}
+
+	name := releaseAssetName()
+	want, err := checksumFor(sums, name)
+	if err != nil {
+		return nil, err
+	}
+	binary, err := download(name)
+	if err != nil {
+		return nil, err
+	}
+	if got := sha256.Sum256(binary); !bytes.Equal(got[:], want) {
+		return nil, errorf("%s does not match its checksum in %s", name, checksumsAsset)
+	}
+	return binary, nil
+}
+
+func fetch(client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "scram-sha-256/"+toolVersion())
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorf("%s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDownloadSize {
+		return nil, errorf("%s: larger than %d bytes", url, maxDownloadSize)
+	}
+	return body, nil
+}
+
+func fetchJSON(client *http.Client, url string, v any) error {
+	body, err := fetch(client, url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// checksumFor finds name in a sha256sum-style listing.
+func checksumFor(sums []byte, name string) ([]byte, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			sum, err := hex.DecodeString(fields[0])
+			if err != nil || len(sum) != sha256.Size {
+				return nil, errorf("malformed checksum for %s", name)
+			}
+			return sum, nil
+		}
+	}
+	return nil, errorf("%s lists no checksum for %s", checksumsAsset, name)
+}
+
+// verifyMinisign checks a minisign signature of data, including the
+// signature over its trusted comment. publicKey is the base64 key, or the
+// contents of a minisign .pub file.
+func verifyMinisign(publicKey string, data, signature []byte) error {
+	key, err := decodeMinisign(lastLine(publicKey), 42)
+	if err != nil {
+		return errorf("invalid public key: %w", err)
+	}
+	if string(key[:2]) != "Ed" {
+		return errorf("invalid public key: unsupported algorithm %q", key[:2])
+	}
+	keyID, pub := key[2:10], ed25519.PublicKey(key[10:])
+
+	lines := strings.Split(strings.TrimRight(string(signature), "\r\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errorf("malformed signature")
+	}
+	sig, err := decodeMinisign(lines[1], 74)
+	if err != nil {
+		return errorf("malformed signature: %w", err)
+	}
+	globalSig, err := decodeMinisign(lines[3], ed25519.SignatureSize)
+	if err != nil {
+		return errorf("malformed signature: %w", err)
+	}
+	if !bytes.Equal(sig[2:10], keyID) {
+		return errorf("signed with a different key")
+	}
+
+	// "ED" signatures, the default since minisign 0.8, sign the BLAKE2b-512
+	// hash of the file; legacy "Ed" signatures sign the file itself.
+	signed := data
+	switch string(sig[:2]) {
+	case "ED":
+		sum := blake2b.Sum512(data)
+		signed = sum[:]
+	case "Ed":
+	default:
+		return errorf("unsupported signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(pub, signed, sig[10:]) {
+		return errorf("signature verification failed")
+	}
+	trusted := strings.TrimPrefix(strings.TrimSuffix(lines[2], "\r"), "trusted comment: ")
+	if !ed25519.Verify(pub, append(bytes.Clone(sig[10:]), trusted...), globalSig) {
+		return errorf("trusted comment signature verification failed")
+	}
+	return nil
+}
+
+func decodeMinisign(s string, size int) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, errorf("got %d bytes, want %d", len(b), size)
+	}
+	return b, nil
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	return s[strings.LastIndex(s, "\n")+1:]
+}
+
+// newerVersion reports whether release tag latest is newer than current.
+// A development build is always older than a release, and a pre-release
+// or pseudo-version older than the release of the same number.
+func newerVersion(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return isPrerelease(current) && !isPrerelease(latest)
+}
+
+func isPrerelease(v string) bool {
+	v, _, _ = strings.Cut(v, "+")
+	return strings.Contains(v, "-")
+}
+
+// parseVersion parses vMAJOR.MINOR.PATCH, ignoring any pre-release or
+// build suffix.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// replaceExecutable atomically replaces the running binary with binary,
+// keeping its permissions. It writes a temporary file beside the binary
+// and renames it over the original, so an interrupted update leaves the
+// old binary in place.
+func replaceExecutable(binary []byte) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return exe, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".scram-sha-256-update-*")
+	if err != nil {
+		return exe, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return exe, err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return exe, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return exe, err
+	}
+	if err := tmp.Close(); err != nil {
+		return exe, err
+	}
+
+	// Windows cannot rename over a running executable, but can move it
+	// aside first.
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return exe, err
+		}
+	}
+	return exe, os.Rename(tmp.Name(), exe)
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/pkcs11"
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"github.com/SonOfBytes/scram-sha-256/testscram"
+)
+
+type serveConfig struct {
+	Listen string
+	Mock   bool
+	Users  userFlags
+	// CredentialUsers maps users to systemd credentials holding their
+	// passwords.
+	CredentialUsers credentialUserFlags
+	Fault           string
+	Iterations      int
+	// MaxDerivations, MaxQueue and QueueTimeout bound the PBKDF2 work
+	// done at once; see scram.Limiter.
+	MaxDerivations int
+	MaxQueue       int
+	QueueTimeout   time.Duration
+	// KeyCacheSize keeps that many SaltedPassword results in a
+	// scram.KeyCache; zero disables it.
+	KeyCacheSize int
+	// Sandbox confines the server with Landlock and seccomp on Linux,
+	// allowing filesystem writes only beneath SandboxWrite.
+	Sandbox      bool
+	SandboxWrite pathFlags
+	// SocketMode and SocketOwner set the permissions and owner of a unix
+	// socket, so that only the intended local clients can connect.
+	SocketMode  string
+	SocketOwner string
+	// LogSink also sends the server's log to journald or syslog; see
+	// openLogSink.
+	LogSink string
+	// PKCS11Module, PKCS11Slot and PKCS11PINFile move the HMAC step of
+	// key derivation onto a PKCS#11 token; see package pkcs11. Keys are
+	// derived in software when no module is given.
+	PKCS11Module  string
+	PKCS11Slot    uint
+	PKCS11PINFile string
+}
+
+// pathFlags collects a repeated path flag.
+type pathFlags []string
+
+func (p *pathFlags) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *pathFlags) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// userFlags collects repeated -user name:password flags.
+type userFlags map[string]string
+
+func (u userFlags) String() string {
+	names := make([]string, 0, len(u))
+	for name := range u {
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (u userFlags) Set(value string) error {
+	name, password, ok := strings.Cut(value, ":")
+	if !ok || name == "" {
+		return errorf("expected name:password")
+	}
+	u[name] = password
+	return nil
+}
+
+func serveFlags(config *serveConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&config.Listen, "listen", "127.0.0.1:5433", "Address to listen on; prefix with unix: for a unix socket")
+	fs.StringVar(&config.SocketMode, "socket-mode", "", "Permissions of the unix socket in octal, such as 0660 (default 0600)")
+	fs.StringVar(&config.SocketOwner, "socket-owner", "", "Owner of the unix socket as user, user:group or :group")
+	fs.BoolVar(&config.Mock, "mock", false, "Run the mock SCRAM server for driver testing")
+	fs.Var(config.Users, "user", "User accepted by the mock server as name:password (repeatable)")
+	fs.Var(config.CredentialUsers, "user-credential", "User whose password is in a systemd credential, as name or name:credential (repeatable)")
+	fs.StringVar(&config.Fault, "fault", "none", "Fault to inject: none, wrong-server-signature, bad-nonce, truncate-server-first, truncate-server-final")
+	fs.IntVar(&config.Iterations, "iterations", defaultIterations, "Number of PBKDF2 iterations")
+	fs.IntVar(&config.Iterations, "i", defaultIterations, "Number of PBKDF2 iterations")
+	fs.IntVar(&config.MaxDerivations, "max-derivations", 0, "Maximum concurrent PBKDF2 derivations (default: number of CPUs)")
+	fs.IntVar(&config.MaxQueue, "max-queue", 0, "Maximum conversations waiting for a derivation before e=server-busy (0 for no limit)")
+	fs.DurationVar(&config.QueueTimeout, "queue-timeout", 0, "Answer e=server-busy after waiting this long for a derivation (0 to wait)")
+	fs.IntVar(&config.KeyCacheSize, "key-cache-size", 0, "Keep up to this many derived keys in memory and log hit rates to -log-sink (0 disables)")
+	fs.BoolVar(&config.Sandbox, "sandbox", false, "Forbid exec and filesystem writes using seccomp and Landlock (Linux)")
+	fs.Var(&config.SandboxWrite, "sandbox-write", "Path that stays writable under -sandbox (repeatable)")
+	fs.StringVar(&config.LogSink, "log-sink", "", "Also log startup and each authentication to journald, syslog or syslog[+tcp]://host:port")
+	fs.StringVar(&config.PKCS11Module, "pkcs11-module", "", "PKCS#11 library that derives user keys on a token, so SaltedPassword never enters process memory")
+	fs.UintVar(&config.PKCS11Slot, "pkcs11-slot", 0, "Slot ID of the token for -pkcs11-module")
+	fs.StringVar(&config.PKCS11PINFile, "pkcs11-pin-file", "", "File whose first line is the user PIN for -pkcs11-module")
+	return fs
+}
+
+func runServe(args []string) int {
+	config := serveConfig{Users: userFlags{}, CredentialUsers: credentialUserFlags{}}
+
+	fs := serveFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if err := serve(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func serve(config serveConfig) error {
+	if !config.Mock {
+		// The mock is the only server; there is no credential service.
+		return errorf("serve requires -mock, the only server it runs")
+	}
+	if len(config.SandboxWrite) > 0 && !config.Sandbox {
+		return errorf("-sandbox-write requires -sandbox")
+	}
+	if (config.SocketMode != "" || config.SocketOwner != "") && !strings.HasPrefix(config.Listen, "unix:") {
+		return errorf("-socket-mode and -socket-owner require a unix: -listen address")
+	}
+	if config.Sandbox {
+		// This re-executes the server on success, so it comes before
+		// anything that should only happen once.
+		writable := config.SandboxWrite
+		if path, ok := strings.CutPrefix(config.Listen, "unix:"); ok {
+			writable = append(writable, filepath.Dir(path))
+		}
+		if err := sandboxFilesystem(writable); err != nil {
+			return err
+		}
+	}
+	for name, credential := range config.CredentialUsers {
+		password, err := readCredential(credential)
+		if err != nil {
+			return errorf("user %s: %w", name, err)
+		}
+		config.Users[name] = password
+	}
+	if len(config.Users) == 0 {
+		return errorf("at least one -user or -user-credential is required")
+	}
+	if config.Iterations < 1 {
+		return errorf("iterations must be at least 1")
+	}
+	if config.MaxDerivations < 0 || config.MaxQueue < 0 || config.QueueTimeout < 0 || config.KeyCacheSize < 0 {
+		return errorf("-max-derivations, -max-queue, -queue-timeout and -key-cache-size must not be negative")
+	}
+	if config.KeyCacheSize > 0 && config.PKCS11Module != "" {
+		// Caching would keep SaltedPassword in memory after all.
+		return errorf("-key-cache-size cannot be used with -pkcs11-module")
+	}
+
+	if config.PKCS11Module == "" && (config.PKCS11Slot != 0 || config.PKCS11PINFile != "") {
+		return errorf("-pkcs11-slot and -pkcs11-pin-file require -pkcs11-module")
+	}
+
+	fault, err := testscram.ParseFault(config.Fault)
+	if err != nil {
+		return err
+	}
+	var deriver scram.KeyDeriver
+	if config.PKCS11Module != "" {
+		// Loaded before the syscall filter is installed.
+		hsm, err := openPKCS11(config)
+		if err != nil {
+			return err
+		}
+		defer hsm.Close()
+		deriver = hsm
+	}
+	var cache *scram.KeyCache
+	if config.KeyCacheSize > 0 {
+		cache = scram.NewKeyCache(nil, config.KeyCacheSize)
+		deriver = scram.SoftwareKeyDeriver{KDF: cache}
+	}
+	if config.LogSink != "" {
+		// Connected before the syscall filter is installed.
+		if auditLog, err = openLogSink(config.LogSink); err != nil {
+			return errorf("opening log sink: %w", err)
+		}
+	}
+
+	l, err := listen(config)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	if config.Sandbox {
+		if err := sandboxSyscalls(); err != nil {
+			return err
+		}
+	}
+
+	srv := testscram.NewServer(config.Users)
+	srv.Iterations = config.Iterations
+	srv.Fault = fault
+	srv.Deriver = deriver
+	srv.Limiter = &scram.Limiter{
+		MaxConcurrent: config.MaxDerivations,
+		MaxQueue:      config.MaxQueue,
+		QueueTimeout:  config.QueueTimeout,
+	}
+	srv.Observe = func(c testscram.Conversation) {
+		logConversation(c)
+		logKeyCacheStats(cache)
+	}
+
+	fmt.Fprintf(os.Stderr, "Mock SCRAM server listening on %s\n", l.Addr())
+	auditLog.Info("mock SCRAM server listening", "address", l.Addr().String(), "fault", config.Fault)
+	return srv.Serve(l)
+}
+
+// openPKCS11 logs in to the token named by the -pkcs11 flags.
+func openPKCS11(config serveConfig) (*pkcs11.Deriver, error) {
+	cfg := pkcs11.Config{Module: config.PKCS11Module, Slot: config.PKCS11Slot}
+	if config.PKCS11PINFile != "" {
+		pin, err := readPasswordFile(config.PKCS11PINFile, false)
+		if err != nil {
+			return nil, errorf("reading -pkcs11-pin-file: %w", err)
+		}
+		cfg.PIN = pin
+	}
+	return pkcs11.Open(cfg)
+}
+
+// logConversation records the outcome of a conversation in the audit log.
+func logConversation(c testscram.Conversation) {
+	attrs := []any{"user", c.Username, "mechanism", c.Mechanism}
+	if c.Remote != nil {
+		attrs = append(attrs, "remote", c.Remote.String())
+	}
+	if c.Err != nil {
+		auditLog.Warn("authentication failed", append(attrs, "error", c.Err.Error())...)
+		return
+	}
+	auditLog.Info("authentication succeeded", attrs...)
+}
+
+func listen(config serveConfig) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(config.Listen, "unix:"); ok {
+		return listenUnix(path, config.SocketMode, config.SocketOwner)
+	}
+	return net.Listen("tcp", config.Listen)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxedEnv marks the re-executed server as already confined by
+// Landlock.
+const sandboxedEnv = "SCRAM_SHA_256_SANDBOXED"
+
+// landlockWriteAccess is every filesystem right that modifies something.
+// Rights not handled by the ruleset, such as reading, stay allowed.
+const landlockWriteAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+	unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+// sandboxFilesystem confines filesystem writes to the writable paths with
+// Landlock. A Landlock domain only applies to the thread that enters it,
+// so the restricted thread re-executes the server, whose threads then all
+// start inside the domain; on success it does not return. In the
+// re-executed server, or when the kernel lacks Landlock, it returns nil.
+func sandboxFilesystem(writable []string) error {
+	if os.Getenv(sandboxedEnv) != "" {
+		return nil
+	}
+
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		fmt.Fprintf(os.Stderr, "Warning: Landlock is unavailable (%v); filesystem writes are not restricted\n", errno)
+		return nil
+	}
+	handled := uint64(landlockWriteAccess)
+	if abi >= 2 {
+		handled |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		handled |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return errorf("failed to create Landlock ruleset: %w", errno)
+	}
+	defer unix.Close(int(fd))
+
+	for _, path := range writable {
+		if err := allowWrites(int(fd), path, handled); err != nil {
+			return err
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// The domain and no_new_privs are per thread, so the thread that sets
+	// them must be the one that calls execve.
+	runtime.LockOSThread()
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return errorf("failed to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return errorf("failed to enter Landlock domain: %w", errno)
+	}
+	return syscall.Exec(exe, os.Args, append(os.Environ(), sandboxedEnv+"=1"))
+}
+
+func allowWrites(ruleset int, path string, handled uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return errorf("-sandbox-write %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return errorf("-sandbox-write %s: %w", path, err)
+	}
+	allowed := handled
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		// Rules on files may only grant rights that apply to files.
+		allowed &= unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: allowed, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return errorf("-sandbox-write %s: %w", path, errno)
+	}
+	return nil
+}
+
+// sandboxSyscalls installs a seccomp filter on every thread that makes
+// execve and execveat fail with EPERM, so a compromised server cannot
+// start other programs.
+func sandboxSyscalls() error {
+	arch, ok := auditArch[runtime.GOARCH]
+	if !ok {
+		return errorf("seccomp filtering is not supported on %s", runtime.GOARCH)
+	}
+
+	deny := []uint32{unix.SYS_EXECVE, unix.SYS_EXECVEAT}
+	// seccomp_data holds the syscall number at offset 0 and the audit
+	// architecture at offset 4.
+	filter := []unix.SockFilter{
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 4),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM)),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 0),
+	}
+	if runtime.GOARCH == "amd64" {
+		// Refuse the x32 ABI, whose syscall numbers have this bit set.
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, 0x40000000, uint8(len(deny)+1), 0))
+	}
+	for i, nr := range deny {
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, uint8(len(deny)-i), 0))
+	}
+	filter = append(filter,
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM)),
+	)
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+
+	// Installing a filter without CAP_SYS_ADMIN needs no_new_privs on the
+	// calling thread; TSYNC then applies both to every other thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return errorf("failed to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, unix.SECCOMP_FILTER_FLAG_TSYNC,
+		uintptr(unsafe.Pointer(&prog))); errno != 0 {
+		return errorf("failed to install seccomp filter: %w", errno)
+	}
+	return nil
+}
+
+var auditArch = map[string]uint32{
+	"amd64": unix.AUDIT_ARCH_X86_64,
+	"arm64": unix.AUDIT_ARCH_AARCH64,
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
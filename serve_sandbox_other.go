@@ -0,0 +1,11 @@
+//go:build !linux
+
+package main
+
+func sandboxFilesystem(writable []string) error {
+	return errorf("-sandbox is only supported on Linux")
+}
+
+func sandboxSyscalls() error {
+	return errorf("-sandbox is only supported on Linux")
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package main
+
+import (
+	"net"
+	"runtime"
+)
+
+// listenUnix listens on the unix socket at path. Socket permissions and
+// ownership cannot be set here, so mode and owner are refused.
+func listenUnix(path, mode, owner string) (net.Listener, error) {
+	if mode != "" || owner != "" {
+		return nil, errorf("-socket-mode and -socket-owner are not supported on %s", runtime.GOOS)
+	}
+	return net.Listen("unix", path)
+}
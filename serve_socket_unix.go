@@ -0,0 +1,92 @@
+//go:build unix
+
+package main
+
+import (
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenUnix listens on the unix socket at path with the permissions in
+// mode (octal, 0600 when empty) and the owner in owner (user, user:group
+// or :group, by name or number). The socket is created under a umask that
+// leaves it readable and writable by this user only, so it is never
+// reachable with looser permissions than asked for. Linux abstract
+// sockets, written with a leading @, have no permissions or owner.
+func listenUnix(path, mode, owner string) (net.Listener, error) {
+	if strings.HasPrefix(path, "@") {
+		if mode != "" || owner != "" {
+			return nil, errorf("-socket-mode and -socket-owner do not apply to abstract socket %s", path)
+		}
+		return net.Listen("unix", path)
+	}
+
+	perm := os.FileMode(0o600)
+	if mode != "" {
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || m > 0o777 {
+			return nil, errorf("-socket-mode: expected octal permissions such as 0660, got %q", mode)
+		}
+		perm = os.FileMode(m)
+	}
+	uid, gid, err := lookupOwner(owner)
+	if err != nil {
+		return nil, errorf("-socket-owner: %w", err)
+	}
+
+	old := unix.Umask(0o177)
+	l, err := net.Listen("unix", path)
+	unix.Umask(old)
+	if err != nil {
+		return nil, err
+	}
+	if uid != -1 || gid != -1 {
+		if err := os.Lchown(path, uid, gid); err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// lookupOwner resolves user, user:group or :group to ids, with -1 for a
+// part that is not given.
+func lookupOwner(owner string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	if owner == "" {
+		return uid, gid, nil
+	}
+	name, group, _ := strings.Cut(owner, ":")
+	if name != "" {
+		if uid, err = strconv.Atoi(name); err != nil {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return 0, 0, err
+			}
+			if uid, err = strconv.Atoi(u.Uid); err != nil {
+				return 0, 0, errorf("user %s has non-numeric id %q", name, u.Uid)
+			}
+		}
+	}
+	if group != "" {
+		if gid, err = strconv.Atoi(group); err != nil {
+			g, err := user.LookupGroup(group)
+			if err != nil {
+				return 0, 0, err
+			}
+			if gid, err = strconv.Atoi(g.Gid); err != nil {
+				return 0, 0, errorf("group %s has non-numeric id %q", group, g.Gid)
+			}
+		}
+	}
+	return uid, gid, nil
+}
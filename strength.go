@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"unicode"
+)
+
+// estimateEntropy gives a rough upper bound on password entropy in bits
+// from its length and the character classes it draws from. It does not
+// detect dictionary words or patterns, so treat it as optimistic.
+func estimateEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	length := 0
+	for _, r := range password {
+		length++
+		switch {
+		case r > unicode.MaxASCII:
+			other = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.present {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(length) * math.Log2(float64(pool))
+}
+
+// strengthLabel buckets an entropy estimate for display.
+func strengthLabel(bits float64) string {
+	switch {
+	case bits < 28:
+		return "very weak"
+	case bits < 36:
+		return "weak"
+	case bits < 60:
+		return "fair"
+	case bits < 128:
+		return "strong"
+	}
+	return "very strong"
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/SonOfBytes/scram-sha-256/internal/pgwire"
+)
+
+// cockroachIterations is CockroachDB's default for
+// server.user_login.password_hashes.default_cost.scram_sha_256.
+const cockroachIterations = 119680
+
+// validateTarget checks the -target options and applies the target's
+// default iteration count unless one was given explicitly.
+func validateTarget(config *Config, iterationsSet bool) error {
+	switch config.Target {
+	case "postgres":
+	case "cockroach":
+		if config.Format != "scram" {
+			return errorf("-format %s is not supported by CockroachDB", config.Format)
+		}
+		if config.Role == "" {
+			return errorf("-target cockroach requires -role")
+		}
+		if !iterationsSet {
+			config.Iterations = cockroachIterations
+		}
+	default:
+		return errorf("unknown target %q", config.Target)
+	}
+
+	if config.Apply {
+		if config.Role == "" {
+			return errorf("-apply requires -role")
+		}
+		if config.Format != "scram" {
+			return errorf("-apply only supports -format scram")
+		}
+		if config.Copy || config.Output != "" {
+			return errorf("-apply cannot be combined with -copy or -output")
+		}
+	}
+	return nil
+}
+
+// passwordStatement returns the SQL that sets role's password to verifier
+// on the target database.
+func passwordStatement(target, role, verifier string) string {
+	if target == "cockroach" {
+		return fmt.Sprintf("ALTER USER %s WITH PASSWORD %s;", pgwire.QuoteIdentifier(role), pgwire.QuoteLiteral(verifier))
+	}
+	return fmt.Sprintf("ALTER ROLE %s PASSWORD %s;", pgwire.QuoteIdentifier(role), pgwire.QuoteLiteral(verifier))
+}
+
+// applyPassword runs the password statement for role over a PostgreSQL
+// wire connection, which CockroachDB also speaks.
+func applyPassword(dsn, target, role, verifier string) error {
+	conn, err := connectPostgres(dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Exec(passwordStatement(target, role, verifier)); err != nil {
+		return errorf("failed to set password for %s: %w", role, err)
+	}
+	return nil
+}
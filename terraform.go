@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// terraformQuery is the object Terraform's external data source writes to
+// stdin. The protocol only allows string values, so iterations arrives as
+// a decimal string. Salt is base64; with it the verifier is the same on
+// every read, so plans do not show a changed password each time.
+type terraformQuery struct {
+	Password   string `json:"password"`
+	Iterations string `json:"iterations"`
+	Salt       string `json:"salt"`
+}
+
+// minTerraformSalt is the shortest salt accepted in a query, the length
+// of the random salts generated otherwise.
+const minTerraformSalt = 16
+
+func runTerraform(r io.Reader, w io.Writer, defaultIters int) error {
+	var query terraformQuery
+	if err := json.NewDecoder(io.LimitReader(r, maxDocumentSize)).Decode(&query); err != nil {
+		return errorf("failed to decode query: %w", err)
+	}
+
+	iterations := defaultIters
+	if query.Iterations != "" {
+		n, err := strconv.Atoi(query.Iterations)
+		if err != nil {
+			return errorf("invalid iterations %q: %w", query.Iterations, err)
+		}
+		iterations = n
+	}
+
+	if err := validatePassword(query.Password); err != nil {
+		return errorf("invalid password: %w", err)
+	}
+
+	var hash string
+	if query.Salt != "" {
+		salt, err := base64.StdEncoding.DecodeString(query.Salt)
+		if err != nil || len(salt) < minTerraformSalt {
+			return errorf("salt must be base64 of at least %d bytes", minTerraformSalt)
+		}
+		hash, err = verifierWithSalt(derivationContext(iterations), crypto.SHA256, query.Password, salt, iterations)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if hash, err = generateSCRAMSHA256(query.Password, iterations); err != nil {
+			return err
+		}
+	}
+
+	result := map[string]string{
+		"verifier":   hash,
+		"iterations": strconv.Itoa(iterations),
+	}
+	return json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,343 @@
+// Package testscram runs a mock SCRAM server over a stream socket so that
+// client implementations can be exercised against known-good and
+// deliberately broken server behaviour.
+//
+// The wire protocol is line based. The client opens with the mechanism name
+// and its client-first message separated by a space; every later message is
+// sent on its own line:
+//
+//	C: SCRAM-SHA-256 n,,n=user,r=fyko+d2lbbFgONRv9qkxdawL
+//	S: r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096
+//	C: c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=...
+//	S: v=...
+//
+// The server closes the connection after the server-final message. When
+// the server's Limiter turns a derivation away with scram.ErrBusy, it
+// answers the client-first message with e=server-busy instead,
+// which clients can treat like an HTTP 503 and retry.
+package testscram
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// Fault selects a misbehaviour for the server to inject.
+type Fault int
+
+const (
+	// NoFault runs a conforming server.
+	NoFault Fault = iota
+	// WrongServerSignature corrupts the v= value in server-final.
+	WrongServerSignature
+	// BadNonce sends a server nonce that does not extend the client nonce.
+	BadNonce
+	// TruncateServerFirst sends half of server-first and closes.
+	TruncateServerFirst
+	// TruncateServerFinal sends half of server-final and closes.
+	TruncateServerFinal
+)
+
+var faultNames = map[string]Fault{
+	"none":                   NoFault,
+	"wrong-server-signature": WrongServerSignature,
+	"bad-nonce":              BadNonce,
+	"truncate-server-first":  TruncateServerFirst,
+	"truncate-server-final":  TruncateServerFinal,
+}
+
+// ParseFault returns the Fault with the given name, such as "bad-nonce".
+func ParseFault(name string) (Fault, error) {
+	f, ok := faultNames[name]
+	if !ok {
+		return 0, fmt.Errorf("testscram: unknown fault %q", name)
+	}
+	return f, nil
+}
+
+// Server is a mock SCRAM server backed by an in-memory user table.
+type Server struct {
+	// Iterations is the PBKDF2 cost used for every user.
+	Iterations int
+	// Fault is injected into every conversation.
+	Fault Fault
+	// Deriver computes user keys; scram.SoftwareKeyDeriver is used when it
+	// is nil.
+	Deriver scram.KeyDeriver
+	// Limiter, when set, bounds the key derivations the server runs at
+	// once, whatever Deriver performs them. It is separate from
+	// scram.DefaultLimiter, so servers sharing a process each get their
+	// own slots.
+	Limiter *scram.Limiter
+
+	// Observe, when set, is called as each conversation ends, from the
+	// goroutine that ran it.
+	Observe func(Conversation)
+
+	mu    sync.Mutex
+	users map[string]string
+	creds map[credKey]scram.StoredCredentials
+}
+
+// Conversation describes a finished conversation.
+type Conversation struct {
+	Remote    net.Addr
+	Mechanism string
+	// Username is the user the client named, or "" when the conversation
+	// ended before the client-first message was read.
+	Username string
+	// Err is the authentication error, or nil when the client
+	// authenticated.
+	Err error
+}
+
+type credKey struct {
+	hash     crypto.Hash
+	username string
+}
+
+// NewServer returns a server that accepts the given username to password
+// mappings.
+func NewServer(users map[string]string) *Server {
+	s := &Server{Iterations: 4096, users: make(map[string]string), creds: make(map[credKey]scram.StoredCredentials)}
+	for name, password := range users {
+		s.users[name] = password
+	}
+	return s
+}
+
+// AddUser adds or replaces a user.
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[username] = password
+	for k := range s.creds {
+		if k.username == username {
+			delete(s.creds, k)
+		}
+	}
+}
+
+// Serve accepts connections on l until it is closed, handling each on its
+// own goroutine.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			s.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn runs one SCRAM conversation on conn. It returns the
+// authentication error, if any, but does not close conn.
+func (s *Server) ServeConn(conn net.Conn) error {
+	return s.ServeConnContext(context.Background(), conn)
+}
+
+// ServeConnContext is like ServeConn but gives up when ctx is done. Key
+// derivation also stops early when the client disconnects while it runs,
+// so abandoned conversations do not hold a derivation slot.
+func (s *Server) ServeConnContext(ctx context.Context, conn net.Conn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	if !deadline.IsZero() {
+		conn.SetDeadline(deadline)
+	}
+
+	c := Conversation{Remote: conn.RemoteAddr()}
+	err := s.converse(ctx, cancel, conn, deadline, &c)
+	if s.Observe != nil {
+		c.Err = err
+		s.Observe(c)
+	}
+	return err
+}
+
+func (s *Server) converse(ctx context.Context, cancel func(), conn net.Conn, deadline time.Time, c *Conversation) error {
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	mech, clientFirst, ok := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+	if !ok {
+		return fmt.Errorf("testscram: expected mechanism and client-first message")
+	}
+	c.Mechanism = mech
+	h, ok := scram.MechanismHash(mech)
+	if !ok {
+		fmt.Fprintf(conn, "e=unsupported-mechanism\n")
+		return fmt.Errorf("testscram: unsupported mechanism %q", mech)
+	}
+
+	srv, err := scram.NewServer(scram.ServerConfig{
+		Hash:   h,
+		Lookup: scram.CredentialLookupFunc(func(username string) (scram.StoredCredentials, error) { return s.lookup(ctx, h, username) }),
+	})
+	if err != nil {
+		return err
+	}
+
+	stop := watchDisconnect(conn, r, deadline, cancel)
+	serverFirst, done, err := srv.Next([]byte(clientFirst))
+	stop()
+	c.Username = srv.Username()
+	if done {
+		if errors.Is(err, scram.ErrBusy) {
+			serverFirst = []byte("e=server-busy")
+		}
+		fmt.Fprintf(conn, "%s\n", serverFirst)
+		return err
+	}
+	switch s.Fault {
+	case BadNonce:
+		serverFirst = corruptNonce(serverFirst)
+	case TruncateServerFirst:
+		_, err := conn.Write(serverFirst[:len(serverFirst)/2])
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "%s\n", serverFirst); err != nil {
+		return err
+	}
+
+	line, err = r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	serverFinal, _, authErr := srv.Next([]byte(strings.TrimRight(line, "\r\n")))
+	switch s.Fault {
+	case WrongServerSignature:
+		if authErr == nil {
+			serverFinal = corruptSignature(serverFinal)
+		}
+	case TruncateServerFinal:
+		_, err := conn.Write(serverFinal[:len(serverFinal)/2])
+		if authErr != nil {
+			return authErr
+		}
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "%s\n", serverFinal); err != nil {
+		return err
+	}
+	return authErr
+}
+
+func (s *Server) lookup(ctx context.Context, h crypto.Hash, username string) (scram.StoredCredentials, error) {
+	key := credKey{h, username}
+	creds, password, ok := s.cached(key)
+	if !ok {
+		return scram.StoredCredentials{}, fmt.Errorf("testscram: %w: %q", scram.ErrUnknownUser, username)
+	}
+	if creds != nil {
+		return *creds, nil
+	}
+
+	// Keys are derived the first time a user authenticates with a
+	// mechanism and cached after that; s.Limiter bounds how many
+	// derivations run at once.
+	release, err := s.Limiter.Acquire(ctx)
+	if err != nil {
+		return scram.StoredCredentials{}, err
+	}
+	defer release()
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return scram.StoredCredentials{}, err
+	}
+	deriver := s.Deriver
+	if deriver == nil {
+		deriver = scram.SoftwareKeyDeriver{}
+	}
+	derived, err := scram.DeriveStoredCredentialsContext(ctx, deriver, h, password, salt, s.Iterations)
+	if err != nil {
+		return scram.StoredCredentials{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// AddUser may have changed the password during the derivation.
+	if s.users[username] == password {
+		s.creds[key] = derived
+	}
+	return derived, nil
+}
+
+// watchDisconnect calls cancel if the client closes the connection
+// before the returned stop function is called. The client sends nothing
+// while it waits for server-first, so any byte that does arrive is left
+// in r for the next read.
+func watchDisconnect(conn net.Conn, r *bufio.Reader, deadline time.Time, cancel func()) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := r.Peek(1); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			cancel()
+		}
+	}()
+	return func() {
+		// A deadline in the past wakes the Peek; the error it returns is
+		// not kept by r.
+		conn.SetReadDeadline(time.Unix(1, 0))
+		<-done
+		conn.SetReadDeadline(deadline)
+	}
+}
+
+// cached returns the stored credentials for key if they have been
+// derived, and otherwise the user's password and whether the user exists.
+func (s *Server) cached(key credKey) (*scram.StoredCredentials, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if creds, ok := s.creds[key]; ok {
+		return &creds, "", true
+	}
+	password, ok := s.users[key.username]
+	return nil, password, ok
+}
+
+// corruptNonce replaces the r= attribute with a nonce unrelated to the
+// client's.
+func corruptNonce(serverFirst []byte) []byte {
+	attrs := strings.Split(string(serverFirst), ",")
+	for i, attr := range attrs {
+		if strings.HasPrefix(attr, "r=") {
+			attrs[i] = "r=" + base64.StdEncoding.EncodeToString([]byte("not-your-nonce"))
+		}
+	}
+	return []byte(strings.Join(attrs, ","))
+}
+
+// corruptSignature flips a bit of the decoded v= value.
+func corruptSignature(serverFinal []byte) []byte {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(serverFinal), "v="))
+	if err != nil || len(sig) == 0 {
+		return serverFinal
+	}
+	sig[0] ^= 0x01
+	return []byte("v=" + base64.StdEncoding.EncodeToString(sig))
+}
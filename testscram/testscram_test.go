@@ -0,0 +1,116 @@
+package testscram
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// clientFirst sends a client-first message for user on conn and returns
+// the server's reply.
+func clientFirst(t *testing.T, conn net.Conn, user string) string {
+	t.Helper()
+	client, err := scram.NewClient(scram.ClientConfig{Hash: crypto.SHA256, Username: user, Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := client.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(conn, "SCRAM-SHA-256 %s\n", msg)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimRight(line, "\n")
+}
+
+func TestServerBusy(t *testing.T) {
+	s := NewServer(map[string]string{"alice": "secret"})
+	s.Limiter = &scram.Limiter{MaxConcurrent: 1, QueueTimeout: 10 * time.Millisecond}
+
+	// Hold the only slot, as a long derivation would.
+	release, err := s.Limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ServeConn(server)
+		server.Close()
+	}()
+	if got := clientFirst(t, client, "alice"); got != "e=server-busy" {
+		t.Errorf("got %q, want e=server-busy", got)
+	}
+	if err := <-done; !errors.Is(err, scram.ErrBusy) {
+		t.Errorf("ServeConn: got %v, want ErrBusy", err)
+	}
+}
+
+// blockedServer returns a server whose only derivation slot is held until
+// the test ends, so conversations wait in lookup until their context is
+// done.
+func blockedServer(t *testing.T) *Server {
+	t.Helper()
+	s := NewServer(map[string]string{"alice": "secret"})
+	s.Limiter = &scram.Limiter{MaxConcurrent: 1}
+	release, err := s.Limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(release)
+	return s
+}
+
+func TestServeConnDisconnectCancels(t *testing.T) {
+	s := blockedServer(t)
+	client, server := net.Pipe()
+	defer server.Close()
+	done := make(chan error, 1)
+	go func() { done <- s.ServeConn(server) }()
+
+	client.Write([]byte("SCRAM-SHA-256 n,,n=alice,r=fyko+d2lbbFgONRv9qkxdawL\n"))
+	client.Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("derivation was not cancelled when the client disconnected")
+	}
+}
+
+func TestServeConnContextDeadline(t *testing.T) {
+	s := blockedServer(t)
+	client, server := net.Pipe()
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- s.ServeConnContext(ctx, server) }()
+
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	go client.Write([]byte("SCRAM-SHA-256 n,,n=alice,r=fyko+d2lbbFgONRv9qkxdawL\n"))
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("derivation outlived the context deadline")
+	}
+}
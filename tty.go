@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// ttyIn and ttyOut are the controlling terminal once -tty has opened it.
+// Password prompts then read from and write to it, leaving stdin and
+// stdout to the data being processed.
+var ttyIn, ttyOut *os.File
+
+// validateTTY checks the options that go with -tty.
+func validateTTY(config Config) error {
+	if !config.TTY {
+		return nil
+	}
+	if config.UseStdin || config.Keychain != "" || config.PasswordFile != "" || config.Credential != "" ||
+		config.SaltedPassword != "" || config.Passphrase != "" || config.Terraform || config.Ansible {
+		return errorf("-tty prompts for the password and cannot be combined with another password source")
+	}
+	if config.Batch && config.User != "" {
+		return errorf("-user cannot be combined with -batch -tty, which reads user names from stdin")
+	}
+	return nil
+}
+
+// promptTerminal returns the terminal to read a password from and the
+// writer for its prompt: the controlling terminal with -tty, and
+// otherwise stdin and out.
+func promptTerminal(out io.Writer) (*os.File, io.Writer) {
+	if ttyIn != nil {
+		return ttyIn, ttyOut
+	}
+	return os.Stdin, out
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package main
+
+import "os"
+
+// openTTY opens /dev/tty, the controlling terminal, for -tty.
+func openTTY() error {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	ttyIn, ttyOut = f, f
+	return nil
+}
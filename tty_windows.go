@@ -0,0 +1,20 @@
+package main
+
+import "os"
+
+// openTTY opens the console for -tty. Windows has no /dev/tty; CONIN$ and
+// CONOUT$ name the console's input and output whatever stdin and stdout
+// are redirected to.
+func openTTY() error {
+	in, err := os.OpenFile("CONIN$", os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile("CONOUT$", os.O_RDWR, 0)
+	if err != nil {
+		in.Close()
+		return err
+	}
+	ttyIn, ttyOut = in, out
+	return nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+var errTUIAborted = errors.New("aborted")
+
+var tuiIterations = []int{4096, 8192, 16384, 32768, 100000, 1000000}
+
+var tuiFormats = []string{"scram", "pg-md5", "both"}
+
+type key int
+
+const (
+	keyRune key = iota
+	keyEnter
+	keyBackspace
+	keyUp
+	keyDown
+	keyAbort
+	keyOther
+)
+
+// tui drives the interactive screens on the controlling terminal. The
+// terminal is in raw mode throughout, so lines end in "\r\n".
+type tui struct {
+	r   *bufio.Reader
+	out io.Writer
+}
+
+// runTUI walks through password entry, iteration and format selection and
+// a confirmation screen, filling in config. It returns the password.
+func runTUI(config *Config) (string, error) {
+	in, out := promptTerminal(os.Stderr)
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errorf("-tui requires an interactive terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	t := &tui{r: bufio.NewReader(in), out: out}
+	t.printf("SCRAM-SHA-256 Password Generator\r\n\r\n")
+
+	password, err := t.readPassword()
+	if err != nil {
+		return "", err
+	}
+
+	choice, err := t.choose(msg.Text("PBKDF2 iterations"), formatInts(tuiIterations), 0)
+	if err != nil {
+		return "", err
+	}
+	config.Iterations = tuiIterations[choice]
+
+	choice, err = t.choose(msg.Text("Output format"), tuiFormats, 0)
+	if err != nil {
+		return "", err
+	}
+	config.Format = tuiFormats[choice]
+	if config.Format != "scram" {
+		if config.Role, err = t.readLine(msg.Text("PostgreSQL role name: ")); err != nil {
+			return "", err
+		}
+	}
+
+	t.printf("\r\nSummary\r\n")
+	t.printf("  Password strength: %s\r\n", msg.Text(strengthLabel(estimateEntropy(password))))
+	t.printf("  Iterations:        %d\r\n", config.Iterations)
+	t.printf("  Format:            %s\r\n", config.Format)
+	if config.Role != "" {
+		t.printf("  Role:              %s\r\n", config.Role)
+	}
+	ok, err := t.confirm(msg.Text("Generate? [y/N] "))
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errTUIAborted
+	}
+	return password, nil
+}
+
+// printf writes to the terminal, localizing format.
+func (t *tui) printf(format string, args ...any) {
+	fmt.Fprint(t.out, msg.Sprintf(format, args...))
+}
+
+func (t *tui) readKey() (key, rune, error) {
+	r, _, err := t.r.ReadRune()
+	if err != nil {
+		return keyOther, 0, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, 0, nil
+	case 0x7f, 0x08:
+		return keyBackspace, 0, nil
+	case 0x03, 0x04:
+		return keyAbort, 0, nil
+	case 0x1b:
+		// Arrow keys arrive as ESC [ A / ESC [ B.
+		if next, _ := t.r.Peek(2); len(next) == 2 && next[0] == '[' {
+			t.r.Discard(2)
+			switch next[1] {
+			case 'A':
+				return keyUp, 0, nil
+			case 'B':
+				return keyDown, 0, nil
+			}
+		}
+		return keyOther, 0, nil
+	}
+	if r < 0x20 {
+		return keyOther, 0, nil
+	}
+	return keyRune, r, nil
+}
+
+// readPassword reads a masked password, redrawing a strength meter after
+// every keystroke.
+func (t *tui) readPassword() (string, error) {
+	var password []rune
+	for {
+		bits := estimateEntropy(string(password))
+		filled := min(int(bits/8), 16)
+		t.printf("\r\033[KPassword: %s  [%s%s] %s",
+			strings.Repeat("*", len(password)),
+			strings.Repeat("#", filled), strings.Repeat(".", 16-filled),
+			msg.Text(strengthLabel(bits)))
+
+		k, r, err := t.readKey()
+		if err != nil {
+			return "", err
+		}
+		switch k {
+		case keyRune:
+			password = append(password, r)
+		case keyBackspace:
+			if len(password) > 0 {
+				password = password[:len(password)-1]
+			}
+		case keyAbort:
+			t.printf("\r\n")
+			return "", errTUIAborted
+		case keyEnter:
+			if len(password) == 0 {
+				continue
+			}
+			t.printf("\r\n\r\n")
+			if len(string(password)) > maxPasswordLength {
+				return "", &PasswordTooLongError{Max: maxPasswordLength}
+			}
+			return string(password), nil
+		}
+	}
+}
+
+// choose shows a menu navigated with the arrow keys and returns the index
+// of the selected option.
+func (t *tui) choose(title string, options []string, selected int) (int, error) {
+	t.printf("%s (arrow keys, Enter to select)\r\n", title)
+	for {
+		for i, opt := range options {
+			marker := "  "
+			if i == selected {
+				marker = "> "
+			}
+			t.printf("\r\033[K%s%s\r\n", marker, opt)
+		}
+
+		k, _, err := t.readKey()
+		if err != nil {
+			return 0, err
+		}
+		switch k {
+		case keyUp:
+			selected = (selected + len(options) - 1) % len(options)
+		case keyDown:
+			selected = (selected + 1) % len(options)
+		case keyAbort:
+			return 0, errTUIAborted
+		case keyEnter:
+			t.printf("\r\n")
+			return selected, nil
+		}
+		t.printf("\033[%dA", len(options))
+	}
+}
+
+func (t *tui) readLine(prompt string) (string, error) {
+	var line []rune
+	for {
+		t.printf("\r\033[K%s%s", prompt, string(line))
+		k, r, err := t.readKey()
+		if err != nil {
+			return "", err
+		}
+		switch k {
+		case keyRune:
+			line = append(line, r)
+		case keyBackspace:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case keyAbort:
+			return "", errTUIAborted
+		case keyEnter:
+			if len(line) > 0 {
+				t.printf("\r\n")
+				return string(line), nil
+			}
+		}
+	}
+}
+
+func (t *tui) confirm(prompt string) (bool, error) {
+	t.printf("\r\n%s", prompt)
+	k, r, err := t.readKey()
+	t.printf("\r\n")
+	if err != nil || k == keyAbort {
+		return false, err
+	}
+	return k == keyRune && (r == 'y' || r == 'Y'), nil
+}
+
+func formatInts(values []int) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = fmt.Sprint(v)
+	}
+	return out
+}
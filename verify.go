@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"crypto"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+	"golang.org/x/term"
+)
+
+type verifyConfig struct {
+	Verifier         string
+	UseStdin         bool
+	MinIterations    int
+	Iterations       int
+	IterationsBudget time.Duration
+	Upgrade          bool
+	Apply            bool
+	Role             string
+	DSN              string
+	LogSink          string
+	KeyCacheSize     int
+}
+
+func verifyFlags(config *verifyConfig) *flag.FlagSet {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.StringVar(&config.Verifier, "verifier", "", "Verifier to check the password against")
+	fs.BoolVar(&config.UseStdin, "stdin", false, "Read password from stdin instead of prompting")
+	fs.IntVar(&config.MinIterations, "min-iterations", defaultIterations, "Offer an upgrade for verifiers with fewer iterations than this")
+	iterations := iterationsValue{&config.Iterations, &config.IterationsBudget}
+	fs.Var(iterations, "iterations", "Iteration count for the upgraded verifier, or auto[:duration] (default: -min-iterations)")
+	fs.Var(iterations, "i", "Iteration count for the upgraded verifier, or auto[:duration] (default: -min-iterations)")
+	fs.BoolVar(&config.Upgrade, "upgrade", false, "Print a re-hashed verifier when the password matches one below -min-iterations")
+	fs.BoolVar(&config.Apply, "apply", false, "With -upgrade, set the re-hashed verifier as the password of -role at -dsn instead of printing it")
+	fs.StringVar(&config.Role, "role", "", "PostgreSQL role whose password -apply sets")
+	fs.StringVar(&config.DSN, "dsn", os.Getenv("DATABASE_URL"), "Connection URL for -apply (default: $DATABASE_URL, then PG* variables)")
+	fs.StringVar(&config.LogSink, "log-sink", "", "Also log checks and upgrades to journald, syslog or syslog[+tcp]://host:port")
+	fs.IntVar(&config.KeyCacheSize, "key-cache-size", 0, "Keep up to this many derived keys in memory and log hit rates to -log-sink (0 disables)")
+	return fs
+}
+
+// runVerify checks a password against a verifier. When the password
+// matches but the verifier's iteration count is below -min-iterations, the
+// password is at hand, so it offers an upgrade: with -upgrade, or when the
+// user answers yes at the terminal, it re-hashes the password at the
+// current policy and prints the new verifier or applies it to -role.
+func runVerify(args []string) int {
+	config := verifyConfig{}
+
+	fs := verifyFlags(&config)
+	localizeFlags(fs)
+	fs.Parse(args)
+
+	if config.Verifier == "" && fs.NArg() == 1 {
+		config.Verifier = fs.Arg(0)
+	}
+	if config.Verifier == "" {
+		fmt.Fprintln(os.Stderr, msg.Text("Error: -verifier is required"))
+		return exitError
+	}
+	if config.Apply && !config.Upgrade {
+		fmt.Fprintln(os.Stderr, msg.Text("Invalid options: -apply requires -upgrade"))
+		return exitError
+	}
+	if config.Apply && config.Role == "" {
+		fmt.Fprintln(os.Stderr, msg.Text("Invalid options: -apply requires -role"))
+		return exitError
+	}
+	h, old, err := scram.ParseVerifier(config.Verifier)
+	if err != nil {
+		msg.Eprintf("Error: %v\n", err)
+		return exitError
+	}
+	if config.Apply && h != crypto.SHA256 {
+		msg.Eprintf("Invalid options: -apply needs a SCRAM-SHA-256 verifier, not %s\n", scram.MechanismName(h))
+		return exitError
+	}
+	if config.KeyCacheSize < 0 {
+		fmt.Fprintln(os.Stderr, msg.Text("Invalid options: -key-cache-size must not be negative"))
+		return exitError
+	}
+	if config.LogSink != "" {
+		if auditLog, err = openLogSink(config.LogSink); err != nil {
+			msg.Eprintf("Error opening log sink: %v\n", err)
+			return exitError
+		}
+	}
+	if config.KeyCacheSize > 0 {
+		cache := scram.NewKeyCache(scram.DefaultKDF, config.KeyCacheSize)
+		scram.DefaultKDF = cache
+		defer logKeyCacheStats(cache)
+	}
+
+	var password string
+	if config.UseStdin {
+		password, err = readPasswordFromStdin()
+	} else {
+		password, err = promptPassword()
+	}
+	if err != nil {
+		msg.Eprintf("Error reading password: %v\n", err)
+		return exitError
+	}
+
+	ok, err := scram.VerifyContext(derivationContext(old.Iterations), password, config.Verifier)
+	if err != nil {
+		msg.Eprintf("Error: %v\n", err)
+		return exitError
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, msg.Text("Error: password does not match the verifier"))
+		auditLog.Warn("password does not match the verifier", "mechanism", scram.MechanismName(h), "role", config.Role)
+		return exitMismatch
+	}
+	fmt.Fprintln(os.Stderr, msg.Text("Password matches"))
+	auditLog.Info("password verified", "mechanism", scram.MechanismName(h), "iterations", old.Iterations, "role", config.Role)
+
+	if old.Iterations >= config.MinIterations {
+		return 0
+	}
+	msg.Eprintf("The verifier uses %d iterations, below the recommended %d\n", old.Iterations, config.MinIterations)
+	if !config.Upgrade {
+		// Only offer the upgrade when the password was typed, so that
+		// scripts feeding -stdin never block on the question.
+		if config.UseStdin || !term.IsTerminal(int(os.Stdin.Fd())) || !confirmUpgrade() {
+			fmt.Fprintln(os.Stderr, msg.Text("Run with -upgrade to re-hash it"))
+			return 0
+		}
+	}
+
+	newIterations := config.MinIterations
+	if config.IterationsBudget > 0 {
+		newIterations, err = calibrateIterations(h, config.IterationsBudget)
+		if err != nil {
+			msg.Eprintf("Error calibrating iterations: %v\n", err)
+			return exitError
+		}
+		msg.Eprintf("Calibrated iterations: %d (%v budget)\n", newIterations, config.IterationsBudget)
+	} else if config.Iterations != 0 {
+		newIterations = config.Iterations
+	}
+	if newIterations < config.MinIterations {
+		msg.Eprintf("Invalid options: refusing to upgrade to %d iterations, below -min-iterations %d\n", newIterations, config.MinIterations)
+		return exitPolicy
+	}
+
+	verifier, err := scram.NewVerifierContext(derivationContext(newIterations), h, password, newIterations)
+	if err != nil {
+		msg.Eprintf("Error generating verifier: %v\n", err)
+		return exitError
+	}
+	if config.Apply {
+		if err := applyPassword(config.DSN, "postgres", config.Role, verifier); err != nil {
+			msg.Eprintf("Error: %v\n", err)
+			return exitError
+		}
+		msg.Eprintf("Password updated for %s\n", config.Role)
+	} else {
+		fmt.Println(verifier)
+	}
+	auditLog.Info("verifier upgraded", "mechanism", scram.MechanismName(h), "old_iterations", old.Iterations, "iterations", newIterations, "role", config.Role)
+	return 0
+}
+
+// confirmUpgrade asks on stderr whether to re-hash the password, reading
+// the answer from stdin.
+func confirmUpgrade() bool {
+	fmt.Fprint(os.Stderr, msg.Text("Re-hash it now? [y/N] "))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
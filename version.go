@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"slices"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+// version is set at build time with -ldflags "-X main.version=v1.2.3";
+// otherwise the module version recorded by go install is used.
+var version = ""
+
+func runVersion(args []string) int {
+	fmt.Printf("scram-sha-256 %s\n", toolVersion())
+	fmt.Printf("Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if scram.FIPSMode() {
+		fmt.Println("FIPS 140-3 mode: enabled (Go Cryptographic Module)")
+	} else {
+		fmt.Println("FIPS 140-3 mode: disabled")
+	}
+	return 0
+}
+
+func toolVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func checkFIPS() error {
+	if !scram.FIPSMode() {
+		return errorf("FIPS 140-3 mode is not enabled; run with GODEBUG=fips140=on or build with GOFIPS140=v1.0.0")
+	}
+	return nil
+}
+
+// nonFIPSFormats are the formats built on algorithms outside FIPS 140-3:
+// MD5 for pg-md5 and both, PBKDF2 with SHA-1 for prosody, and a single
+// round of salted SHA-256 for rabbitmq.
+var nonFIPSFormats = []string{"pg-md5", "both", "prosody", "rabbitmq"}
+
+// checkFIPSFormat refuses a format -fips does not allow.
+func checkFIPSFormat(format string) error {
+	if slices.Contains(nonFIPSFormats, format) {
+		return errorf("format %s is not permitted with -fips", format)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// bidiControls are formatting characters that reorder text without being
+// visible. The embedding and override controls are already rejected by
+// checkSASLprep; these are the ones it lets through.
+var bidiControls = &unicode.RangeTable{
+	R16: []unicode.Range16{{0x061c, 0x061c, 1}, {0x2066, 0x2069, 1}},
+}
+
+// confusableScripts are scripts whose letters are commonly mistaken for
+// one another; mixing them means a password may not be retyped correctly.
+var confusableScripts = map[string]*unicode.RangeTable{
+	"Latin":    unicode.Latin,
+	"Cyrillic": unicode.Cyrillic,
+	"Greek":    unicode.Greek,
+	"Armenian": unicode.Armenian,
+	"Cherokee": unicode.Cherokee,
+	"Coptic":   unicode.Coptic,
+}
+
+// passwordWarnings returns reasons the password may be hard to retype on
+// another system. They are warnings by default and errors under -strict.
+func passwordWarnings(password string) []string {
+	var warnings []string
+
+	position := 0
+	var rtl, ltr bool
+	scripts := make(map[string]bool)
+	for _, r := range password {
+		position++
+		if unicode.Is(bidiControls, r) {
+			warnings = append(warnings, msg.Sprintf("password contains invisible bidirectional control U+%04X at position %d", r, position))
+		}
+
+		switch {
+		case unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko):
+			rtl = true
+		case unicode.IsLetter(r):
+			ltr = true
+		}
+		for name, table := range confusableScripts {
+			if unicode.IsLetter(r) && unicode.Is(table, r) {
+				scripts[name] = true
+			}
+		}
+	}
+
+	if rtl && ltr {
+		warnings = append(warnings, msg.Text("password mixes right-to-left and left-to-right letters, which SASLprep rejects and editors display reordered"))
+	}
+	if len(scripts) > 1 {
+		names := make([]string, 0, len(scripts))
+		for name := range scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		warnings = append(warnings, msg.Sprintf("password mixes visually confusable %s letters", strings.Join(names, msg.Text(" and "))))
+	}
+	return warnings
+}
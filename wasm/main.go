@@ -0,0 +1,62 @@
+//go:build js && wasm
+
+// Command wasm exposes verifier generation and verification to JavaScript,
+// so browser admin tools can derive a verifier without the plaintext ever
+// leaving the page. See scram.js for the wrapper that loads it.
+package main
+
+import (
+	"crypto"
+	"syscall/js"
+
+	"github.com/SonOfBytes/scram-sha-256/scram"
+)
+
+const defaultIterations = 4096
+
+func main() {
+	js.Global().Set("scramGenerate", js.FuncOf(generate))
+	js.Global().Set("scramVerify", js.FuncOf(verify))
+
+	// Keep the exported functions alive for the lifetime of the page.
+	select {}
+}
+
+// generate(password, iterations?) returns {verifier} or {error}.
+func generate(this js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return result("error", "password must be a string")
+	}
+	password := args[0].String()
+	if password == "" {
+		return result("error", "password cannot be empty")
+	}
+
+	iterations := defaultIterations
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		iterations = args[1].Int()
+	}
+
+	verifier, err := scram.NewVerifier(crypto.SHA256, password, iterations)
+	if err != nil {
+		return result("error", err.Error())
+	}
+	return result("verifier", verifier)
+}
+
+// verify(password, verifier) returns {valid} or {error}.
+func verify(this js.Value, args []js.Value) any {
+	if len(args) < 2 || args[0].Type() != js.TypeString || args[1].Type() != js.TypeString {
+		return result("error", "password and verifier must be strings")
+	}
+
+	ok, err := scram.Verify(args[0].String(), args[1].String())
+	if err != nil {
+		return result("error", err.Error())
+	}
+	return map[string]any{"valid": ok}
+}
+
+func result(key, value string) map[string]any {
+	return map[string]any{key: value}
+}
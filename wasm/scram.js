@@ -0,0 +1,23 @@
+// Thin promise-based wrapper around scram.wasm. Load wasm_exec.js from
+// "$(go env GOROOT)/lib/wasm/wasm_exec.js" before this file.
+//
+//   const scram = await loadScram("/static/scram.wasm");
+//   const verifier = scram.generate(password, 4096);
+//   const ok = scram.verify(password, verifier);
+async function loadScram(url) {
+  const go = new Go();
+  const { instance } = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(instance);
+
+  const unwrap = (res) => {
+    if (res.error) {
+      throw new Error(res.error);
+    }
+    return res;
+  };
+
+  return {
+    generate: (password, iterations) => unwrap(scramGenerate(password, iterations)).verifier,
+    verify: (password, verifier) => unwrap(scramVerify(password, verifier)).valid,
+  };
+}
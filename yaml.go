@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// yamlLine is a non-blank line of a YAML document with its comment removed.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// parseYAML reads the block-style subset of YAML used by configuration
+// files: nested mappings and sequences, flow sequences of scalars, and
+// plain or quoted scalars. The result is built from map[string]any, []any,
+// string, int64, bool and nil, like the output of encoding/json, so it can
+// be re-encoded as JSON and decoded into a struct. Anchors, tags, flow
+// mappings and multi-line scalars are rejected rather than misread.
+func parseYAML(data string) (any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(data, "\n") {
+		raw = strings.TrimRight(raw, " \r")
+		text := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		text = stripYAMLComment(text)
+		if text == "" || (len(lines) == 0 && text == "---") {
+			continue
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(raw) - len(strings.TrimLeft(raw, " ")), text: text})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	p := &yamlParser{lines: lines}
+	node, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return node, nil
+}
+
+// stripYAMLComment removes a trailing comment, which starts with a '#'
+// outside quotes at the start of the line or after a space. A quote only
+// opens a quoted scalar where a scalar starts, so the apostrophe in a plain
+// scalar such as it's-secret does not hide the comment after it.
+func stripYAMLComment(text string) string {
+	var quote byte
+	flow := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote == '\'' && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && yamlScalarStart(text, i, flow):
+			quote = c
+		case c == '[' && yamlScalarStart(text, i, flow):
+			flow++
+		case c == ']' && flow > 0:
+			flow--
+		case c == '#' && (i == 0 || text[i-1] == ' '):
+			return strings.TrimRight(text[:i], " ")
+		}
+	}
+	return text
+}
+
+// yamlScalarStart reports whether a scalar can begin at text[i]: at the
+// start of the line, after a "key: " or "- " indicator, or after '[' or
+// ',' inside a flow sequence.
+func yamlScalarStart(text string, i, flow int) bool {
+	prev := strings.TrimRight(text[:i], " ")
+	if prev == "" {
+		return true
+	}
+	spaced := len(prev) < i
+	switch prev[len(prev)-1] {
+	case '[', ',':
+		return flow > 0
+	case ':':
+		return spaced
+	case '-':
+		return spaced && strings.Trim(prev, "- ") == ""
+	}
+	return false
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence whose entries start at indent.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent || !isYAMLSequenceItem(line.text) {
+			return nil, errorf("line %d: expected a sequence item", line.num)
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			item, err := p.nested(indent, line.num)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		// An item holding more structure, such as "- name: x", is parsed
+		// as if that text started its own line at the column it occupies.
+		p.lines[p.pos] = yamlLine{num: line.num, indent: line.indent + len(line.text) - len(rest), text: rest}
+		if isYAMLSequenceItem(rest) || isYAMLMappingEntry(rest) {
+			item, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+		value, err := parseYAMLScalar(rest, line.num)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+		p.pos++
+	}
+	return items, nil
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := map[string]any{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, errorf("line %d: unexpected indentation", line.num)
+		}
+		if !isYAMLMappingEntry(line.text) {
+			return nil, errorf("line %d: expected key: value", line.num)
+		}
+
+		key, value := splitYAMLEntry(line.text)
+		if strings.HasPrefix(key, `"`) || strings.HasPrefix(key, "'") {
+			k, err := parseYAMLScalar(key, line.num)
+			if err != nil {
+				return nil, err
+			}
+			key = fmt.Sprint(k)
+		}
+		if _, dup := m[key]; dup {
+			return nil, errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		if value == "" {
+			// A nested block, or a sequence that YAML allows at the
+			// same indentation as its key.
+			if p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSequenceItem(p.lines[p.pos].text) {
+				v, err := p.sequence(indent)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+				continue
+			}
+			v, err := p.nested(indent, line.num)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+
+		v, err := parseYAMLScalar(value, line.num)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// nested parses the block indented under the line numbered num, or
+// returns nil when there is none.
+func (p *yamlParser) nested(indent, num int) (any, error) {
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+		return nil, nil
+	}
+	return p.block(p.lines[p.pos].indent)
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isYAMLMappingEntry(text string) bool {
+	key, _ := splitYAMLEntry(text)
+	return key != ""
+}
+
+// splitYAMLEntry splits "key: value" at the first ": " or trailing ':'
+// outside quotes, returning an empty key when text is not an entry.
+func splitYAMLEntry(text string) (string, string) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+		}
+	}
+	return "", ""
+}
+
+func parseYAMLScalar(text string, num int) (any, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		s, ok := unquoteYAML(text)
+		if !ok {
+			return nil, errorf("line %d: invalid double-quoted string %s", num, text)
+		}
+		return s, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, errorf("line %d: invalid single-quoted string %s", num, text)
+		}
+		inner := text[1 : len(text)-1]
+		if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+			return nil, errorf("line %d: invalid single-quoted string %s", num, text)
+		}
+		return strings.ReplaceAll(inner, "''", "'"), nil
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, errorf("line %d: unterminated flow sequence", num)
+		}
+		items := []any{}
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		if inner == "" {
+			return items, nil
+		}
+		for _, part := range strings.Split(inner, ",") {
+			v, err := parseYAMLScalar(strings.TrimSpace(part), num)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case strings.ContainsAny(text[:1], "{&*!|>%@`"):
+		return nil, errorf("line %d: unsupported YAML syntax %q", num, text)
+	}
+
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n, nil
+	}
+	return text, nil
+}
+
+// yamlEscapes maps the single-character escapes of YAML double-quoted
+// scalars to their values. They differ from Go's: YAML has \0, \e, \/,
+// \N, \_, \L, \P and an escaped space, and lacks \' and octal.
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n",
+	'v': "\v", 'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"",
+	'/': "/", '\\': "\\", 'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// yamlHexEscapes gives the number of hex digits after \x, \u and \U,
+// which name a code point rather than a byte.
+var yamlHexEscapes = map[byte]int{'x': 2, 'u': 4, 'U': 8}
+
+// unquoteYAML decodes a double-quoted YAML scalar on a single line.
+func unquoteYAML(text string) (string, bool) {
+	if len(text) < 2 || text[len(text)-1] != '"' {
+		return "", false
+	}
+	inner := text[1 : len(text)-1]
+	var b strings.Builder
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		if c == '"' {
+			return "", false
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(inner) {
+			return "", false
+		}
+		if s, ok := yamlEscapes[inner[i]]; ok {
+			b.WriteString(s)
+			continue
+		}
+		n, ok := yamlHexEscapes[inner[i]]
+		if !ok || i+n >= len(inner) {
+			return "", false
+		}
+		r, err := strconv.ParseUint(inner[i+1:i+1+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return "", false
+		}
+		b.WriteRune(rune(r))
+		i += n
+	}
+	return b.String(), true
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAMLComments(t *testing.T) {
+	tests := []struct {
+		name, doc string
+		want      any
+	}{
+		{"apostrophe in plain scalar", "password: it's-secret # rotate yearly", "it's-secret"},
+		{"hash without space", "password: a#b", "a#b"},
+		{"hash in single quotes", "password: 'a # b' # note", "a # b"},
+		{"escaped quote in single quotes", "password: 'it''s # here' # note", "it's # here"},
+		{"hash in double quotes", `password: "a # b" # note`, "a # b"},
+		{"escaped quote in double quotes", `password: "say \" # hi" # note`, `say " # hi`},
+		{"sequence item", "password:\n  - 'x # y' # note", []any{"x # y"}},
+		{"flow sequence", "password: ['a # b', c] # note", []any{"a # b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v := got.(map[string]any)["password"]; !reflect.DeepEqual(v, tt.want) {
+				t.Errorf("password = %#v, want %#v", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLDoubleQuotedEscapes(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`"a\tb"`, "a\tb"},
+		{`"\e[0m"`, "\x1b[0m"},
+		{`"a\/b"`, "a/b"},
+		{`"\0"`, "\x00"},
+		{`"a\ b"`, "a b"},
+		{`"\N\_\L\P"`, "\u0085\u00a0\u2028\u2029"},
+		// \x names a code point in YAML, not a byte as in Go.
+		{`"\xe9"`, "é"},
+		{`"é\U0001F600"`, "é\U0001F600"},
+	}
+	for _, tt := range tests {
+		got, err := parseYAMLScalar(tt.in, 1)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// Go escapes that YAML does not have.
+	for _, in := range []string{`"it\'s"`, `"\101"`, `"\q"`, `"\x4"`, `"\uD800"`, `"a"b"`, `"a\"`} {
+		if got, err := parseYAMLScalar(in, 1); err == nil {
+			t.Errorf("%s = %q, want an error", in, got)
+		}
+	}
+}